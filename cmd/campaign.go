@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/lindell/multi-gitter/internal/campaign"
+	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/lindell/multi-gitter/internal/scm"
+)
+
+// configureCampaign adds the campaign flags to a command, recording should be set for commands that create pull requests
+func configureCampaign(cmd *cobra.Command, recording bool) {
+	desc := "The name of the campaign. All created or updated pull requests are recorded under this name, and can later be used with the --campaign flag of status, merge, close, comment, update-branch, edit, approve, retry-checks and diff."
+	if !recording {
+		desc = "Only use the pull requests recorded by runs with this campaign name. The branch, platform and repositories are taken from the campaign, and any repository listing flags are ignored."
+	}
+	cmd.Flags().StringP("campaign", "", "", desc)
+	configureCampaignDir(cmd)
+}
+
+func configureCampaignDir(cmd *cobra.Command) {
+	cmd.Flags().StringP("campaign-dir", "", "", "The directory where campaigns are recorded. Defaults to ~/.multi-gitter/campaigns.")
+}
+
+func getCampaignStore(flag *pflag.FlagSet) (campaign.Store, error) {
+	dir, _ := flag.GetString("campaign-dir")
+	if dir == "" {
+		var err error
+		dir, err = campaign.DefaultDir()
+		if err != nil {
+			return campaign.Store{}, err
+		}
+	}
+	return campaign.Store{Dir: dir}, nil
+}
+
+// getCampaignRecorder returns a recorder of the campaign set with the --campaign flag, or nil if no campaign is used
+func getCampaignRecorder(flag *pflag.FlagSet) (multigitter.PullRequestRecorder, error) {
+	name, _ := flag.GetString("campaign")
+	if name == "" {
+		return nil, nil
+	}
+
+	branch, _ := flag.GetString("branch")
+	platform, _ := flag.GetString("platform")
+	baseURL, _ := flag.GetString("base-url")
+
+	store, err := getCampaignStore(flag)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := store.Load(name)
+	if errors.Is(err, campaign.ErrNotFound) {
+		c = campaign.Campaign{
+			Name:     name,
+			Branch:   branch,
+			Platform: platform,
+			BaseURL:  baseURL,
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if c.Branch != branch {
+		return nil, errors.Errorf(`the campaign "%s" uses the branch "%s", not "%s"`, name, c.Branch, branch)
+	}
+	if c.Platform != platform {
+		return nil, errors.Errorf(`the campaign "%s" uses the platform "%s", not "%s"`, name, c.Platform, platform)
+	}
+
+	return campaign.NewRecorder(store, c), nil
+}
+
+// campaignListingFlags are all flags that decides which repositories are used
+var campaignListingFlags = []string{"org", "group", "user", "repo", "project", "repo-file", "topic", "repo-search", "code-search"}
+
+// useCampaign changes the flags to target the repositories of the campaign set with the --campaign flag, and returns a function
+// that only includes the recorded pull requests. If no campaign is used, nil is returned
+func useCampaign(flag *pflag.FlagSet) (func(pr scm.PullRequest) bool, error) {
+	name, _ := flag.GetString("campaign")
+	if name == "" {
+		return nil, nil
+	}
+
+	store, err := getCampaignStore(flag)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := store.Load(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(c.PullRequests) == 0 {
+		return nil, errors.Errorf(`no pull requests are recorded in the campaign "%s"`, name)
+	}
+
+	if branch, _ := flag.GetString("branch"); flag.Changed("branch") && branch != c.Branch {
+		return nil, errors.Errorf(`the campaign "%s" uses the branch "%s", not "%s"`, name, c.Branch, branch)
+	}
+	if err := flag.Set("branch", c.Branch); err != nil {
+		return nil, err
+	}
+
+	if platform, _ := flag.GetString("platform"); flag.Changed("platform") && platform != c.Platform {
+		return nil, errors.Errorf(`the campaign "%s" uses the platform "%s", not "%s"`, name, c.Platform, platform)
+	}
+	if err := flag.Set("platform", c.Platform); err != nil {
+		return nil, err
+	}
+	if !flag.Changed("base-url") && c.BaseURL != "" {
+		if err := flag.Set("base-url", c.BaseURL); err != nil {
+			return nil, err
+		}
+	}
+
+	// When profiles are used, the repositories are listed by the profiles
+	if profiles, _ := flag.GetStringSlice("profile"); len(profiles) == 0 {
+		if err := setCampaignListing(flag, c); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.Contains, nil
+}
+
+// setCampaignListing replaces the repository listing flags, to list exactly the repositories of a campaign
+func setCampaignListing(flag *pflag.FlagSet, c campaign.Campaign) error {
+	orgs, _ := flag.GetStringSlice("org")
+
+	for _, name := range campaignListingFlags {
+		f := flag.Lookup(name)
+		if f == nil {
+			continue
+		}
+		if sliceValue, ok := f.Value.(pflag.SliceValue); ok {
+			if err := sliceValue.Replace(nil); err != nil {
+				return err
+			}
+		} else if err := f.Value.Set(""); err != nil {
+			return err
+		}
+	}
+
+	repos := c.Repositories()
+	switch c.Platform {
+	case "gitlab":
+		return setFlagValue(flag, "project", repos)
+	case "bitbucket_cloud":
+		// Bitbucket cloud lists repositories by name within workspaces
+		var workspaces, names []string
+		for _, repo := range repos {
+			workspace, name, _ := strings.Cut(repo, "/")
+			if !slices.Contains(workspaces, workspace) {
+				workspaces = append(workspaces, workspace)
+			}
+			names = append(names, name)
+		}
+		if err := setFlagValue(flag, "org", workspaces); err != nil {
+			return err
+		}
+		return setFlagValue(flag, "repo", names)
+	case "azure_devops":
+		// The organization is part of where the platform is, and not only a repository listing
+		if err := setFlagValue(flag, "org", orgs); err != nil {
+			return err
+		}
+		return setFlagValue(flag, "repo", repos)
+	default:
+		return setFlagValue(flag, "repo", repos)
+	}
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/lindell/multi-gitter/internal/campaign"
+)
+
+//nolint:lll
+const applyHelp = `
+This command reads a campaign manifest, and uses it to run, get the status of, or merge the pull requests of the campaign. The action defaults to run. The validate action only checks that the manifest is valid.
+
+The concurrent and dry-run keys of the rollout section are only used when running the campaign, and the ones of the merge section only when merging it.
+
+The name of the manifest is used as the campaign name. Pull requests created by run are recorded under it, and status and merge only use the recorded pull requests. If no pull requests has been recorded locally, status and merge instead find the pull requests by the branch name.
+
+Flags set on the command line take precedence over values in the manifest, which in turn take precedence over the config file.
+
+Example manifest:
+  version: 1
+  name: update-readme
+  targets:
+    platform: github
+    org: [my-org]
+  filters:
+    repo-exclude: "^my-org/legacy-"
+    skip-forks: true
+  steps: # or script: ./update-readme.sh
+    - sed -i 's/old/new/g' README.md
+  branch: update-readme
+  pull-request:
+    title: Update the README
+    body: This PR was created by the update-readme campaign.
+    labels: [docs]
+    reviewers: [alice, bob]
+    max-reviewers: 1
+  rollout:
+    concurrent: 4
+    conflict-strategy: replace
+  merge:
+    merge-type: [squash]
+    concurrent: 2
+
+Available keys:
+  targets:      profile, platform, base-url, insecure, username, auth-type, org, group, user, repo, project, repo-file, repo-search, code-search, include-subgroups, ssh-auth
+  filters:      repo-include, repo-exclude, skip-repo, topic, skip-forks, language, visibility, pushed-after, exclude-archived, filter, has-file
+  pull-request: title, body, commit-message, reviewers, team-reviewers, max-reviewers, max-team-reviewers, codeowners, reviewer-rotation-file, unavailable-reviewers-file, assignees, labels, draft, auto-merge
+  rollout:      concurrent, dry-run, conflict-strategy, skip-pr, push-only, api-push, manual-commit, push-option, author-name, author-email, clone-dir, git-type, fetch-depth, fork, fork-owner
+  merge:        merge-type, wait-for-checks, check-interval, required-approvals, merge-interval, concurrent, dry-run
+
+The token of the platform is never part of the manifest, it's set with the --token flag or an environment variable.
+`
+
+// ApplyCmd runs run, status or merge based on a campaign manifest
+func ApplyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:       "apply [manifest] [run|status|merge|validate]",
+		Short:     "Run, get the status of, or merge a campaign described in a manifest file.",
+		Long:      applyHelp,
+		Args:      cobra.RangeArgs(1, 2),
+		ValidArgs: []string{"run", "status", "merge", "validate"},
+		PreRunE:   logFlagInit,
+		RunE:      apply,
+	}
+
+	cmd.Flags().StringP("token", "T", "", "The personal access token for the targeting platform. Can also be set using the GITHUB_TOKEN/GITLAB_TOKEN/GITEA_TOKEN/BITBUCKET_SERVER_TOKEN/BITBUCKET_CLOUD_APP_PASSWORD/BITBUCKET_CLOUD_WORKSPACE_TOKEN/GERRIT_TOKEN/AZURE_DEVOPS_TOKEN environment variable.")
+	cmd.Flags().BoolP("dry-run", "d", false, "Run without pushing changes or creating pull requests.")
+	configureCampaignDir(cmd)
+	configureLogging(cmd, "-")
+	cmd.Flags().AddFlagSet(outputFlag())
+
+	return cmd
+}
+
+func apply(cmd *cobra.Command, args []string) error {
+	m, err := readManifest(args[0])
+	if err != nil {
+		return err
+	}
+
+	action := "run"
+	if len(args) > 1 {
+		action = args[1]
+	}
+
+	var target *cobra.Command
+	switch action {
+	case "run":
+		target = RunCmd()
+	case "status":
+		target = StatusCmd()
+	case "merge":
+		target = MergeCmd()
+	case "validate":
+		fmt.Fprintf(cmd.OutOrStdout(), "The manifest of the campaign %s is valid\n", m.Name)
+		return nil
+	default:
+		return errors.Errorf(`unknown action "%s"`, action)
+	}
+
+	// Flags set on the apply command takes precedence over the manifest
+	var flagErr error
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if target.Flags().Lookup(f.Name) != nil && flagErr == nil {
+			flagErr = target.Flags().Set(f.Name, f.Value.String())
+		}
+	})
+	if flagErr != nil {
+		return flagErr
+	}
+
+	if err := m.setFlags(target, action); err != nil {
+		return err
+	}
+	if err := initializeStaticConfig(target); err != nil {
+		return err
+	}
+
+	if action != "run" {
+		if err := fallbackFromCampaign(target); err != nil {
+			return err
+		}
+		target.SetContext(cmd.Context())
+		return target.RunE(target, nil)
+	}
+
+	command, cleanup, err := m.command()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	if err := target.Flags().Parse([]string{"--", command}); err != nil {
+		return err
+	}
+
+	runner, err := newRunner(target.Flags())
+	if err != nil {
+		return err
+	}
+	if closer, ok := runner.Output.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	return runner.Run(ctx)
+}
+
+// fallbackFromCampaign stops using the campaign of the manifest if nothing has been recorded for it,
+// in which case the pull requests are instead found by the branch name
+func fallbackFromCampaign(cmd *cobra.Command) error {
+	flag := cmd.Flags()
+	name, _ := flag.GetString("campaign")
+
+	store, err := getCampaignStore(flag)
+	if err != nil {
+		return err
+	}
+
+	c, err := store.Load(name)
+	if err != nil && !errors.Is(err, campaign.ErrNotFound) {
+		return err
+	}
+	if len(c.PullRequests) > 0 {
+		return nil
+	}
+
+	log.Warnf(`No pull requests are recorded for the campaign "%s", finding them by the branch name instead`, name)
+	return flag.Set("campaign", "")
+}
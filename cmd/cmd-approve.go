@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/spf13/cobra"
+)
+
+// ApproveCmd approves pull requests
+func ApproveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "approve",
+		Short: "Approve pull requests.",
+		Long: `Approve pull requests with a specified branch name in an organization and with specified conditions.
+
+An approving review is submitted as the authenticated user. On GitHub and Gitea this is an approving review,
+on GitLab an approval, on Bitbucket an approval, on Azure DevOps an approving vote and on Gerrit a Code-Review +2.
+Pull requests created by the authenticated user are skipped, since most platforms don't allow approving your own pull requests.`,
+		Args:    cobra.NoArgs,
+		PreRunE: logFlagInit,
+		RunE:    approve,
+	}
+
+	cmd.Flags().StringP("branch", "B", "multi-gitter-branch", "The name of the branch where changes are committed.")
+	configurePullRequestStatus(cmd, "Only approve pull requests with one of these statuses.")
+	configurePullRequestFilters(cmd, "approve")
+	cmd.Flags().BoolP("dry-run", "d", false, "List the pull requests that would be approved, without approving them.")
+	configurePlatform(cmd)
+	configureRepoFilters(cmd)
+	configureRunPlatform(cmd, false)
+	configureCampaign(cmd, false)
+	configureLogging(cmd, "-")
+	configureConfig(cmd)
+	cmd.Flags().AddFlagSet(outputFlag())
+
+	return cmd
+}
+
+func approve(cmd *cobra.Command, _ []string) error {
+	flag := cmd.Flags()
+
+	includePullRequest, err := useCampaign(flag)
+	if err != nil {
+		return err
+	}
+	includePullRequest, err = usePullRequestStatus(flag, includePullRequest)
+	if err != nil {
+		return err
+	}
+	includePullRequest, err = usePullRequestFilters(flag, includePullRequest)
+	if err != nil {
+		return err
+	}
+
+	branchName, _ := flag.GetString("branch")
+	hasFiles, _ := flag.GetStringSlice("has-file")
+	dryRun, _ := flag.GetBool("dry-run")
+	strOutput, _ := flag.GetString("output")
+
+	filters, err := parseRepoFilters(flag)
+	if err != nil {
+		return err
+	}
+
+	vc, err := getVersionController(flag, true, false)
+	if err != nil {
+		return err
+	}
+
+	output, err := fileOutput(strOutput, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	approver := multigitter.Approver{
+		VersionController: vc,
+
+		Output: output,
+
+		FeatureBranch: branchName,
+		PullRequestSelection: multigitter.PullRequestSelection{
+			IncludePullRequest: includePullRequest,
+			RepoFilters:        filters,
+			HasFiles:           hasFiles,
+		},
+
+		DryRun: dryRun,
+	}
+
+	return approver.Approve(cmd.Context())
+}
@@ -1,7 +1,7 @@
 package cmd
 
 import (
-	"context"
+	"os"
 
 	"github.com/lindell/multi-gitter/internal/multigitter"
 	"github.com/spf13/cobra"
@@ -19,10 +19,18 @@ func CloseCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringP("branch", "B", "multi-gitter-branch", "The name of the branch where changes are committed.")
+	configurePullRequestStatus(cmd, "Only close pull requests with one of these statuses.")
+	configurePullRequestFilters(cmd, "close")
+	cmd.Flags().StringP("comment", "", "", "A comment that is posted on each pull request before it's closed, for example to explain why it's closed.")
+	cmd.Flags().BoolP("delete-branch", "", false, "Delete the branch of each pull request after it has been closed.")
+	cmd.Flags().BoolP("dry-run", "d", false, "List the pull requests that would be closed, without closing them.")
 	configurePlatform(cmd)
+	configureRepoFilters(cmd)
 	configureRunPlatform(cmd, false)
+	configureCampaign(cmd, false)
 	configureLogging(cmd, "-")
 	configureConfig(cmd)
+	cmd.Flags().AddFlagSet(outputFlag())
 
 	return cmd
 }
@@ -30,20 +38,58 @@ func CloseCmd() *cobra.Command {
 func closeCMD(cmd *cobra.Command, _ []string) error {
 	flag := cmd.Flags()
 
+	includePullRequest, err := useCampaign(flag)
+	if err != nil {
+		return err
+	}
+	includePullRequest, err = usePullRequestStatus(flag, includePullRequest)
+	if err != nil {
+		return err
+	}
+	includePullRequest, err = usePullRequestFilters(flag, includePullRequest)
+	if err != nil {
+		return err
+	}
+
 	branchName, _ := flag.GetString("branch")
+	hasFiles, _ := flag.GetStringSlice("has-file")
+	comment, _ := flag.GetString("comment")
+	deleteBranch, _ := flag.GetBool("delete-branch")
+	dryRun, _ := flag.GetBool("dry-run")
+	strOutput, _ := flag.GetString("output")
+
+	filters, err := parseRepoFilters(flag)
+	if err != nil {
+		return err
+	}
 
 	vc, err := getVersionController(flag, true, false)
 	if err != nil {
 		return err
 	}
 
+	output, err := fileOutput(strOutput, os.Stdout)
+	if err != nil {
+		return err
+	}
+
 	statuser := multigitter.Closer{
 		VersionController: vc,
 
+		Output: output,
+
 		FeatureBranch: branchName,
+		PullRequestSelection: multigitter.PullRequestSelection{
+			IncludePullRequest: includePullRequest,
+			RepoFilters:        filters,
+			HasFiles:           hasFiles,
+		},
+		Comment:      comment,
+		DeleteBranch: deleteBranch,
+		DryRun:       dryRun,
 	}
 
-	err = statuser.Close(context.Background())
+	err = statuser.Close(cmd.Context())
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// CommentCmd comments on pull requests
+func CommentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "comment",
+		Short: "Comment on pull requests.",
+		Long: `Comment on pull requests with a specified branch name in an organization and with specified conditions.
+
+The comment is a Go template, where the following fields of each pull request can be used:
+{{.PullRequest}}, {{.Repository}}, {{.Number}}, {{.Title}}, {{.Author}}, {{.URL}}, {{.Branch}}, {{.Status}}, {{.Labels}} and {{.CreatedAt}}.
+Pull requests that already have the same comment are skipped, so the command can safely be run again.`,
+		Args:    cobra.NoArgs,
+		PreRunE: logFlagInit,
+		RunE:    comment,
+	}
+
+	cmd.Flags().StringP("branch", "B", "multi-gitter-branch", "The name of the branch where changes are committed.")
+	cmd.Flags().StringP("body", "b", "", "The comment to post on each pull request.")
+	cmd.Flags().StringP("body-file", "", "", "A file containing the comment to post on each pull request.")
+	cmd.Flags().BoolP("allow-duplicates", "", false, "Comment even on pull requests that already have the same comment.")
+	configurePullRequestStatus(cmd, "Only comment on pull requests with one of these statuses.")
+	configurePullRequestFilters(cmd, "comment on")
+	cmd.Flags().BoolP("dry-run", "d", false, "List the pull requests that would be commented on, without commenting.")
+	configurePlatform(cmd)
+	configureRepoFilters(cmd)
+	configureRunPlatform(cmd, false)
+	configureCampaign(cmd, false)
+	configureLogging(cmd, "-")
+	configureConfig(cmd)
+	cmd.Flags().AddFlagSet(outputFlag())
+
+	return cmd
+}
+
+func comment(cmd *cobra.Command, _ []string) error {
+	flag := cmd.Flags()
+
+	includePullRequest, err := useCampaign(flag)
+	if err != nil {
+		return err
+	}
+	includePullRequest, err = usePullRequestStatus(flag, includePullRequest)
+	if err != nil {
+		return err
+	}
+	includePullRequest, err = usePullRequestFilters(flag, includePullRequest)
+	if err != nil {
+		return err
+	}
+
+	branchName, _ := flag.GetString("branch")
+	body, _ := flag.GetString("body")
+	bodyFile, _ := flag.GetString("body-file")
+	allowDuplicates, _ := flag.GetBool("allow-duplicates")
+	hasFiles, _ := flag.GetStringSlice("has-file")
+	dryRun, _ := flag.GetBool("dry-run")
+	strOutput, _ := flag.GetString("output")
+
+	if body != "" && bodyFile != "" {
+		return errors.New("only one of --body and --body-file can be used")
+	}
+	if bodyFile != "" {
+		data, err := os.ReadFile(bodyFile)
+		if err != nil {
+			return errors.Wrap(err, "could not read the comment file")
+		}
+		body = string(data)
+	}
+	if body == "" {
+		return errors.New("a comment has to be set with --body or --body-file")
+	}
+
+	filters, err := parseRepoFilters(flag)
+	if err != nil {
+		return err
+	}
+
+	vc, err := getVersionController(flag, true, false)
+	if err != nil {
+		return err
+	}
+
+	output, err := fileOutput(strOutput, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	commenter := multigitter.Commenter{
+		VersionController: vc,
+
+		Output: output,
+
+		FeatureBranch: branchName,
+		PullRequestSelection: multigitter.PullRequestSelection{
+			IncludePullRequest: includePullRequest,
+			RepoFilters:        filters,
+			HasFiles:           hasFiles,
+		},
+
+		Body:            body,
+		AllowDuplicates: allowDuplicates,
+		DryRun:          dryRun,
+	}
+
+	return commenter.Comment(cmd.Context())
+}
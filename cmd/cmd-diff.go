@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/lindell/multi-gitter/internal/git/cmdgit"
+	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/spf13/cobra"
+)
+
+// DiffCmd shows the changes of pull requests
+func DiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show the changes of pull requests.",
+		Long: `Show the changes of open pull requests with a specified branch name in an organization and with specified conditions.
+
+The head of each pull request is fetched together with the branch it targets and compared with it, which requires git to be installed.
+Pull requests made from forks can be compared on all platforms except Bitbucket Cloud and Azure DevOps.
+With --group-identical, pull requests with identical changes are shown together. Diffs are seen as identical even if the changes are made at different lines.
+With --deviating, only the pull requests with changes that differ from the most common ones are shown, which makes manual fixups easy to spot.`,
+		Args:    cobra.NoArgs,
+		PreRunE: logFlagInit,
+		RunE:    diff,
+	}
+
+	cmd.Flags().StringP("branch", "B", "multi-gitter-branch", "The name of the branch where changes are committed.")
+	cmd.Flags().BoolP("group-identical", "", false, "Show pull requests with identical diffs together, with the diff only shown once.")
+	cmd.Flags().BoolP("deviating", "", false, "Only show the pull requests with a diff that differs from the most common one.")
+	cmd.Flags().StringP("clone-dir", "", "", "The temporary directory where the repositories will be cloned. If not set, the default os temporary directory will be used.")
+	configurePullRequestStatus(cmd, "Only show the diff of pull requests with one of these statuses.")
+	configurePullRequestFilters(cmd, "show the diff of")
+	configurePlatform(cmd)
+	configureRepoFilters(cmd)
+	configureRunPlatform(cmd, false)
+	configureCampaign(cmd, false)
+	configureLogging(cmd, "-")
+	configureConfig(cmd)
+	cmd.Flags().AddFlagSet(outputFlag())
+
+	return cmd
+}
+
+func diff(cmd *cobra.Command, _ []string) error {
+	flag := cmd.Flags()
+
+	includePullRequest, err := useCampaign(flag)
+	if err != nil {
+		return err
+	}
+	includePullRequest, err = usePullRequestStatus(flag, includePullRequest)
+	if err != nil {
+		return err
+	}
+	includePullRequest, err = usePullRequestFilters(flag, includePullRequest)
+	if err != nil {
+		return err
+	}
+
+	branchName, _ := flag.GetString("branch")
+	groupIdentical, _ := flag.GetBool("group-identical")
+	deviating, _ := flag.GetBool("deviating")
+	cloneDir, _ := flag.GetString("clone-dir")
+	hasFiles, _ := flag.GetStringSlice("has-file")
+	strOutput, _ := flag.GetString("output")
+
+	filters, err := parseRepoFilters(flag)
+	if err != nil {
+		return err
+	}
+
+	vc, err := getVersionController(flag, true, false)
+	if err != nil {
+		return err
+	}
+
+	output, err := fileOutput(strOutput, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	differ := multigitter.Differ{
+		VersionController: vc,
+
+		Output: output,
+
+		FeatureBranch: branchName,
+		PullRequestSelection: multigitter.PullRequestSelection{
+			IncludePullRequest: includePullRequest,
+			RepoFilters:        filters,
+			HasFiles:           hasFiles,
+		},
+
+		GroupIdentical: groupIdentical,
+		OnlyDeviating:  deviating,
+
+		CloneDir: cloneDir,
+		// Comparing branches needs the git command, and the entire history of the branches
+		CreateGit: func(dir string) multigitter.Git {
+			return &cmdgit.Git{
+				Directory: dir,
+			}
+		},
+	}
+
+	return differ.Diff(cmd.Context())
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// EditCmd edits pull requests
+func EditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "edit",
+		Short: "Edit the title, body and other metadata of pull requests.",
+		Long: `Edit the title, body, reviewers, assignees, labels, draft state or auto-merge of pull requests with a specified branch name in an organization and with specified conditions.
+
+Only the values that are set are changed, and the commits of the pull requests are left untouched.
+Reviewers, assignees and labels replace the existing ones. Not all platforms support changing all values, and changes on Gerrit can not be edited.`,
+		Args:    cobra.NoArgs,
+		PreRunE: logFlagInit,
+		RunE:    edit,
+	}
+
+	cmd.Flags().StringP("branch", "B", "multi-gitter-branch", "The name of the branch where changes are committed.")
+	cmd.Flags().StringP("pr-title", "t", "", "The new title of the pull requests.")
+	cmd.Flags().StringP("pr-body", "b", "", "The new body of the pull requests.")
+	cmd.Flags().StringSliceP("reviewers", "r", nil, "The username of the reviewers of the pull requests.")
+	cmd.Flags().StringSliceP("team-reviewers", "", nil, "Github team names of the reviewers, in format: 'org/team'")
+	cmd.Flags().StringSliceP("assignees", "a", nil, "The username of the assignees of the pull requests.")
+	cmd.Flags().StringSliceP("labels", "", nil, "The labels of the pull requests.")
+	cmd.Flags().BoolP("draft", "", false, "Mark the pull requests as draft, or as ready for review with --draft=false.")
+	cmd.Flags().BoolP("pr-auto-merge", "", false, "Enable auto-merge for the pull requests.")
+	configurePullRequestStatus(cmd, "Only edit pull requests with one of these statuses.")
+	configurePullRequestFilters(cmd, "edit")
+	cmd.Flags().BoolP("dry-run", "d", false, "List the pull requests that would be edited, without editing them.")
+	configurePlatform(cmd)
+	configureRepoFilters(cmd)
+	configureRunPlatform(cmd, false)
+	configureCampaign(cmd, false)
+	configureLogging(cmd, "-")
+	configureConfig(cmd)
+	cmd.Flags().AddFlagSet(outputFlag())
+
+	return cmd
+}
+
+func edit(cmd *cobra.Command, _ []string) error {
+	flag := cmd.Flags()
+
+	includePullRequest, err := useCampaign(flag)
+	if err != nil {
+		return err
+	}
+	includePullRequest, err = usePullRequestStatus(flag, includePullRequest)
+	if err != nil {
+		return err
+	}
+	includePullRequest, err = usePullRequestFilters(flag, includePullRequest)
+	if err != nil {
+		return err
+	}
+
+	branchName, _ := flag.GetString("branch")
+	reviewers, _ := stringSlice(flag, "reviewers")
+	teamReviewers, _ := stringSlice(flag, "team-reviewers")
+	assignees, _ := stringSlice(flag, "assignees")
+	labels, _ := stringSlice(flag, "labels")
+	prAutoMerge, _ := flag.GetBool("pr-auto-merge")
+	hasFiles, _ := flag.GetStringSlice("has-file")
+	dryRun, _ := flag.GetBool("dry-run")
+	strOutput, _ := flag.GetString("output")
+
+	changeFlags := []string{"pr-title", "pr-body", "reviewers", "team-reviewers", "assignees", "labels", "draft", "pr-auto-merge"}
+	changed := false
+	for _, name := range changeFlags {
+		changed = changed || flag.Changed(name)
+	}
+	if !changed {
+		return errors.New("nothing to edit, at least one of --pr-title, --pr-body, --reviewers, --team-reviewers, --assignees, --labels, --draft or --pr-auto-merge has to be set")
+	}
+
+	var title, body *string
+	if flag.Changed("pr-title") {
+		prTitle, _ := flag.GetString("pr-title")
+		title = &prTitle
+	}
+	if flag.Changed("pr-body") {
+		prBody, _ := flag.GetString("pr-body")
+		body = &prBody
+	}
+	var draft *bool
+	if flag.Changed("draft") {
+		d, _ := flag.GetBool("draft")
+		draft = &d
+	}
+
+	filters, err := parseRepoFilters(flag)
+	if err != nil {
+		return err
+	}
+
+	vc, err := getVersionController(flag, true, false)
+	if err != nil {
+		return err
+	}
+
+	output, err := fileOutput(strOutput, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	editor := multigitter.Editor{
+		VersionController: vc,
+
+		Output: output,
+
+		FeatureBranch: branchName,
+		PullRequestSelection: multigitter.PullRequestSelection{
+			IncludePullRequest: includePullRequest,
+			RepoFilters:        filters,
+			HasFiles:           hasFiles,
+		},
+
+		Title:         title,
+		Body:          body,
+		Draft:         draft,
+		Reviewers:     reviewers,
+		TeamReviewers: teamReviewers,
+		Assignees:     assignees,
+		Labels:        labels,
+		AutoMerge:     prAutoMerge,
+
+		DryRun: dryRun,
+	}
+
+	return editor.Edit(cmd.Context())
+}
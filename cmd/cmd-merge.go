@@ -1,9 +1,11 @@
 package cmd
 
 import (
-	"context"
+	"os"
+	"time"
 
 	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -20,10 +22,20 @@ func MergeCmd() *cobra.Command {
 
 	cmd.Flags().StringP("branch", "B", "multi-gitter-branch", "The name of the branch where changes are committed.")
 	configureMergeType(cmd, false)
+	cmd.Flags().DurationP("wait-for-checks", "", 0, "The maximum time to wait for pending checks to complete before merging, for example 30m. "+
+		"Pull requests with checks that are still pending after this time are skipped. Pending pull requests are skipped directly if not set.")
+	cmd.Flags().DurationP("check-interval", "", 30*time.Second, "The time between each poll of the pull requests while waiting for checks.")
+	cmd.Flags().IntP("required-approvals", "", 0, "The number of approvals a pull request needs to have to be merged.")
+	cmd.Flags().IntP("concurrent", "C", 1, "The maximum number of concurrent merges.")
+	cmd.Flags().DurationP("merge-interval", "", 0, "The minimum time between the start of two merges, to limit the load on the platform and the CI of the base branch.")
+	cmd.Flags().BoolP("dry-run", "d", false, "List the pull requests that would be merged and skipped, without merging them.")
 	configurePlatform(cmd)
+	configureRepoFilters(cmd)
 	configureRunPlatform(cmd, false)
+	configureCampaign(cmd, false)
 	configureLogging(cmd, "-")
 	configureConfig(cmd)
+	cmd.Flags().AddFlagSet(outputFlag())
 
 	return cmd
 }
@@ -31,20 +43,61 @@ func MergeCmd() *cobra.Command {
 func merge(cmd *cobra.Command, _ []string) error {
 	flag := cmd.Flags()
 
+	includePullRequest, err := useCampaign(flag)
+	if err != nil {
+		return err
+	}
+
 	branchName, _ := flag.GetString("branch")
+	hasFiles, _ := flag.GetStringSlice("has-file")
+	dryRun, _ := flag.GetBool("dry-run")
+	checksTimeout, _ := flag.GetDuration("wait-for-checks")
+	checksInterval, _ := flag.GetDuration("check-interval")
+	requiredApprovals, _ := flag.GetInt("required-approvals")
+	concurrent, _ := flag.GetInt("concurrent")
+	mergeInterval, _ := flag.GetDuration("merge-interval")
+	strOutput, _ := flag.GetString("output")
+
+	if concurrent < 1 {
+		return errors.New("concurrent merges can't be less than one")
+	}
+
+	filters, err := parseRepoFilters(flag)
+	if err != nil {
+		return err
+	}
 
 	vc, err := getVersionController(flag, true, false)
 	if err != nil {
 		return err
 	}
 
+	output, err := fileOutput(strOutput, os.Stdout)
+	if err != nil {
+		return err
+	}
+
 	statuser := multigitter.Merger{
 		VersionController: vc,
 
+		Output: output,
+
 		FeatureBranch: branchName,
+		PullRequestSelection: multigitter.PullRequestSelection{
+			IncludePullRequest: includePullRequest,
+			RepoFilters:        filters,
+			HasFiles:           hasFiles,
+		},
+		DryRun: dryRun,
+
+		ChecksTimeout:     checksTimeout,
+		ChecksInterval:    checksInterval,
+		RequiredApprovals: requiredApprovals,
+		Concurrent:        concurrent,
+		MergeInterval:     mergeInterval,
 	}
 
-	err = statuser.Merge(context.Background())
+	err = statuser.Merge(cmd.Context())
 	if err != nil {
 		return err
 	}
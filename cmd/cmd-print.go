@@ -71,6 +71,7 @@ func printCMD(cmd *cobra.Command, _ []string) error {
 	if err != nil {
 		return err
 	}
+	hasFiles, _ := flag.GetStringSlice("has-file")
 
 	vc, err := getVersionController(flag, true, true)
 	if err != nil {
@@ -109,6 +110,7 @@ func printCMD(cmd *cobra.Command, _ []string) error {
 		Stderr: errOutput,
 
 		RepoFilters: filters,
+		HasFiles:    hasFiles,
 
 		Concurrent: concurrent,
 		CloneDir:   cloneDir,
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/spf13/cobra"
+)
+
+// RetryChecksCmd re-runs the failed checks of pull requests
+func RetryChecksCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "retry-checks",
+		Short: "Re-run the failed checks of pull requests.",
+		Long: `Re-run the failed checks of pull requests with a specified branch name in an organization and with specified conditions.
+
+Only open pull requests with failed checks are used, including drafts and pull requests with conflicts. The failed workflow runs, pipelines or builds of each pull request are re-run through the API of the platform,
+unless they have already been run --max-attempts times. Re-running checks is supported on GitHub, GitLab, Gitea and Azure DevOps.
+It's not supported on Bitbucket Server, where checks are reported by external build servers, or on Bitbucket Cloud, where the API can't re-run Pipelines steps.`,
+		Args:    cobra.NoArgs,
+		PreRunE: logFlagInit,
+		RunE:    retryChecks,
+	}
+
+	cmd.Flags().StringP("branch", "B", "multi-gitter-branch", "The name of the branch where changes are committed.")
+	cmd.Flags().IntP("max-attempts", "", 3, "The maximum number of times a check is run, including its first run.")
+	configurePullRequestFilters(cmd, "re-run the checks of")
+	cmd.Flags().BoolP("dry-run", "d", false, "List the pull requests that would have their checks re-run, without re-running them.")
+	configurePlatform(cmd)
+	configureRepoFilters(cmd)
+	configureRunPlatform(cmd, false)
+	configureCampaign(cmd, false)
+	configureLogging(cmd, "-")
+	configureConfig(cmd)
+	cmd.Flags().AddFlagSet(outputFlag())
+
+	return cmd
+}
+
+func retryChecks(cmd *cobra.Command, _ []string) error {
+	flag := cmd.Flags()
+
+	includePullRequest, err := useCampaign(flag)
+	if err != nil {
+		return err
+	}
+	includePullRequest, err = usePullRequestFilters(flag, includePullRequest)
+	if err != nil {
+		return err
+	}
+
+	branchName, _ := flag.GetString("branch")
+	maxAttempts, _ := flag.GetInt("max-attempts")
+	hasFiles, _ := flag.GetStringSlice("has-file")
+	dryRun, _ := flag.GetBool("dry-run")
+	strOutput, _ := flag.GetString("output")
+
+	filters, err := parseRepoFilters(flag)
+	if err != nil {
+		return err
+	}
+
+	vc, err := getVersionController(flag, true, false)
+	if err != nil {
+		return err
+	}
+
+	output, err := fileOutput(strOutput, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	retrier := multigitter.ChecksRetrier{
+		VersionController: vc,
+
+		Output: output,
+
+		FeatureBranch: branchName,
+		PullRequestSelection: multigitter.PullRequestSelection{
+			IncludePullRequest: includePullRequest,
+			RepoFilters:        filters,
+			HasFiles:           hasFiles,
+		},
+
+		MaxAttempts: maxAttempts,
+
+		DryRun: dryRun,
+	}
+
+	return retrier.Retry(cmd.Context())
+}
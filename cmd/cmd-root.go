@@ -21,7 +21,15 @@ func RootCmd() *cobra.Command {
 	cmd.AddCommand(StatusCmd())
 	cmd.AddCommand(MergeCmd())
 	cmd.AddCommand(CloseCmd())
+	cmd.AddCommand(CommentCmd())
+	cmd.AddCommand(UpdateBranchCmd())
+	cmd.AddCommand(EditCmd())
+	cmd.AddCommand(ApproveCmd())
+	cmd.AddCommand(RetryChecksCmd())
+	cmd.AddCommand(DiffCmd())
 	cmd.AddCommand(PrintCmd())
+	cmd.AddCommand(ApplyCmd())
+	cmd.AddCommand(ServeCmd())
 	cmd.AddCommand(VersionCmd())
 
 	return cmd
@@ -11,8 +11,10 @@ import (
 	"github.com/lindell/multi-gitter/internal/git"
 
 	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/lindell/multi-gitter/internal/rotation"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 //nolint:lll
@@ -43,8 +45,16 @@ func RunCmd() *cobra.Command {
 	cmd.Flags().StringSliceP("reviewers", "r", nil, "The username of the reviewers to be added on the pull request.")
 	cmd.Flags().StringSliceP("team-reviewers", "", nil, "Github team names of the reviewers, in format: 'org/team'")
 	cmd.Flags().StringSliceP("assignees", "a", nil, "The username of the assignees to be added on the pull request.")
-	cmd.Flags().IntP("max-reviewers", "M", 0, "If this value is set, reviewers will be randomized.")
-	cmd.Flags().IntP("max-team-reviewers", "", 0, "If this value is set, team reviewers will be randomized")
+	cmd.Flags().IntP("max-reviewers", "M", 0, "If this value is set, only this many of the reviewers are requested on each pull request. "+
+		"The reviewers that have been requested the fewest times are picked, to spread the reviews evenly.")
+	cmd.Flags().IntP("max-team-reviewers", "", 0, "If this value is set, only this many of the team reviewers are requested on each pull request. "+
+		"The teams that have been requested the fewest times are picked, to spread the reviews evenly.")
+	cmd.Flags().BoolP("codeowners", "", false, "Request the code owners of the changed files as reviewers, as defined in the CODEOWNERS file of each repository. "+
+		"The code owners are added to any other reviewers, and max-reviewers and max-team-reviewers still apply.")
+	cmd.Flags().StringP("reviewer-rotation-file", "", "", "Path of a file where the number of review requests of each reviewer is stored. "+
+		"When set, reviews are spread evenly across runs, and not only within a single run.")
+	cmd.Flags().StringP("unavailable-reviewers-file", "", "", "Path of a file with reviewers and teams, one per line, that should not be requested as reviewers. "+
+		"For example, colleagues that are on vacation.")
 	cmd.Flags().IntP("concurrent", "C", 1, "The maximum number of concurrent runs.")
 	cmd.Flags().BoolP("skip-pr", "", false, "Skip pull request and directly push to the branch.")
 	cmd.Flags().BoolP("push-only", "", false, "Skip pull request and only push the feature branch.")
@@ -73,6 +83,7 @@ Available values:
 	configureGit(cmd)
 	configurePlatform(cmd)
 	configureRunPlatform(cmd, true)
+	configureCampaign(cmd, true)
 	configureLogging(cmd, "-")
 	configureConfig(cmd)
 	cmd.Flags().AddFlagSet(outputFlag())
@@ -81,8 +92,34 @@ Available values:
 }
 
 func run(cmd *cobra.Command, _ []string) error {
-	flag := cmd.Flags()
+	runner, err := newRunner(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	// Set up signal listening to cancel the context and let started runs finish gracefully
+	ctx, cancel := context.WithCancel(cmd.Context())
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-c
+		fmt.Fprintln(os.Stderr, "Finishing up ongoing runs. Press CTRL+C again to abort now.")
+		cancel()
+		<-c
+		os.Exit(1)
+	}()
 
+	err = runner.Run(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	return nil
+}
+
+// newRunner parses all flags of the run command into a runner
+func newRunner(flag *pflag.FlagSet) (*multigitter.Runner, error) {
 	branchName, _ := flag.GetString("branch")
 	baseBranchName, _ := flag.GetString("base-branch")
 	prTitle, _ := flag.GetString("pr-title")
@@ -92,6 +129,9 @@ func run(cmd *cobra.Command, _ []string) error {
 	teamReviewers, _ := stringSlice(flag, "team-reviewers")
 	maxReviewers, _ := flag.GetInt("max-reviewers")
 	maxTeamReviewers, _ := flag.GetInt("max-team-reviewers")
+	codeOwners, _ := flag.GetBool("codeowners")
+	reviewerRotationFile, _ := flag.GetString("reviewer-rotation-file")
+	unavailableReviewersFile, _ := flag.GetString("unavailable-reviewers-file")
 	concurrent, _ := flag.GetInt("concurrent")
 	skipPullRequest, _ := flag.GetBool("skip-pr")
 	pushOnly, _ := flag.GetBool("push-only")
@@ -115,17 +155,17 @@ func run(cmd *cobra.Command, _ []string) error {
 	platform, _ := flag.GetString("platform")
 
 	if concurrent < 1 {
-		return errors.New("concurrent runs can't be less than one")
+		return nil, errors.New("concurrent runs can't be less than one")
 	}
 
 	output, err := fileOutput(strOutput, os.Stdout)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Set commit message based on pr title and body or the reverse
 	if commitMessage == "" && prTitle == "" && !manualCommit {
-		return errors.New("pull request title or commit message must be set")
+		return nil, errors.New("pull request title or commit message must be set")
 	} else if commitMessage == "" {
 		commitMessage = prTitle
 		if prBody != "" {
@@ -140,32 +180,36 @@ func run(cmd *cobra.Command, _ []string) error {
 	}
 
 	if pushOnly && forkMode {
-		return errors.New("--push-only and --fork can't be used at the same time")
+		return nil, errors.New("--push-only and --fork can't be used at the same time")
 	}
 
 	if skipPullRequest && pushOnly {
-		return errors.New("--push-only and --skip-pr can't be used at the same time")
+		return nil, errors.New("--push-only and --skip-pr can't be used at the same time")
 	}
 
 	if skipPullRequest && forkMode {
-		return errors.New("--fork and --skip-pr can't be used at the same time")
+		return nil, errors.New("--fork and --skip-pr can't be used at the same time")
 	}
 
 	if concurrent > 1 && interactive {
-		return errors.New("--concurrent and --interactive can't be used at the same time")
+		return nil, errors.New("--concurrent and --interactive can't be used at the same time")
 	}
 
 	if apiPush {
 		if platform != "github" {
-			return errors.New("api-push is only supported for GitHub")
+			return nil, errors.New("api-push is only supported for GitHub")
 		}
 	}
 
+	if platform == "git" && !skipPullRequest && !pushOnly {
+		return nil, errors.New("the git platform does not support pull requests, use --push-only or --skip-pr")
+	}
+
 	// Parse commit author data
 	var commitAuthor *git.CommitAuthor
 	if authorName != "" || authorEmail != "" {
 		if authorName == "" || authorEmail == "" {
-			return errors.New("both author-name and author-email has to be set if the other is set")
+			return nil, errors.New("both author-name and author-email has to be set if the other is set")
 		}
 		commitAuthor = &git.CommitAuthor{
 			Name:  authorName,
@@ -174,50 +218,49 @@ func run(cmd *cobra.Command, _ []string) error {
 	}
 
 	if maxReviewers < 0 {
-		return errors.New("max-reviewers cannot be negative")
+		return nil, errors.New("max-reviewers cannot be negative")
 	}
 	if maxTeamReviewers < 0 {
-		return errors.New("max-team-reviewers cannot be negative")
+		return nil, errors.New("max-team-reviewers cannot be negative")
 	}
 
 	filters, err := parseRepoFilters(flag)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	hasFiles, _ := flag.GetStringSlice("has-file")
 
 	vc, err := getVersionController(flag, true, false)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	gitCreator, err := getGitCreator(flag)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	executablePath, arguments, err := parseCommand(flag.Arg(0))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	conflictStrategy, err := multigitter.ParseConflictStrategy(conflictStrategyStr)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Set up signal listening to cancel the context and let started runs finish gracefully
-	ctx, cancel := context.WithCancel(context.Background())
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-c
-		fmt.Fprintln(os.Stderr, "Finishing up ongoing runs. Press CTRL+C again to abort now.")
-		cancel()
-		<-c
-		os.Exit(1)
-	}()
+	recorder, err := getCampaignRecorder(flag)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewerRotation, err := getReviewerRotation(reviewerRotationFile, unavailableReviewersFile)
+	if err != nil {
+		return nil, err
+	}
 
-	runner := &multigitter.Runner{
+	return &multigitter.Runner{
 		ScriptPath:    executablePath,
 		Arguments:     arguments,
 		FeatureBranch: branchName,
@@ -233,6 +276,8 @@ func run(cmd *cobra.Command, _ []string) error {
 		TeamReviewers:    teamReviewers,
 		MaxReviewers:     maxReviewers,
 		MaxTeamReviewers: maxTeamReviewers,
+		CodeOwners:       codeOwners,
+		ReviewerRotation: reviewerRotation,
 		Interactive:      interactive,
 		DryRun:           dryRun,
 		Fork:             forkMode,
@@ -243,6 +288,7 @@ func run(cmd *cobra.Command, _ []string) error {
 		PushOptions:      pushOptions,
 		ManualCommit:     manualCommit,
 		RepoFilters:      filters,
+		HasFiles:         hasFiles,
 		CommitAuthor:     commitAuthor,
 		BaseBranch:       baseBranchName,
 		Assignees:        assignees,
@@ -251,17 +297,28 @@ func run(cmd *cobra.Command, _ []string) error {
 		AutoMerge:        prAutoMerge,
 		Labels:           labels,
 		CloneDir:         cloneDir,
+		Recorder:         recorder,
 
 		Concurrent: concurrent,
 
 		CreateGit: gitCreator,
-	}
+	}, nil
+}
 
-	err = runner.Run(ctx)
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err.Error())
-		os.Exit(1)
+// getReviewerRotation creates the rotation used to spread the reviews evenly, with the counts from
+// the rotation file if one is set
+func getReviewerRotation(rotationFile, unavailableFile string) (*rotation.Rotation, error) {
+	var unavailable []string
+	if unavailableFile != "" {
+		var err error
+		unavailable, err = rotation.ReadUnavailable(unavailableFile)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return nil
+	if rotationFile == "" {
+		return rotation.New(unavailable), nil
+	}
+	return rotation.Load(rotationFile, unavailable)
 }
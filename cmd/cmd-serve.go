@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/lindell/multi-gitter/internal/server"
+)
+
+//nolint:lll
+const serveHelp = `
+This command starts an HTTP server where run, status, merge, close, comment, update-branch, edit, approve, retry-checks and diff jobs can be submitted. Jobs are queued, and up to --max-jobs of them run at the same time, each in its own process with the credentials of the server.
+
+Endpoints:
+  POST   /jobs              Submit a job, the body is a JSON object: {"command": "run", "args": ["/path/to/script"], "flags": {"org": ["my-org"], "branch": "my-branch", "concurrent": 4}}
+  GET    /jobs              List all jobs
+  GET    /jobs/{id}         Get a job and its status
+  DELETE /jobs/{id}         Cancel a queued or running job
+  GET    /jobs/{id}/events  Get the logs of a job as newline delimited JSON. Use ?follow=true to stream them until the job is done
+  GET    /jobs/{id}/output  Get the output of a job
+
+Flags in a job use the same format as the config file. The token of the platform can't be set in a job and is instead taken from the environment of the server.
+Only flags that can't be used to read or write files on the server, or to leak its token, can be set in a job. Jobs always use the platform and base URL of the server,
+and can only use the profiles listed with --profile. Run jobs are only allowed if --script-dir is set, and can only run scripts in that directory.
+`
+
+// ServeCmd starts a server where jobs can be submitted through an HTTP API
+func ServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "serve",
+		Short:   "Start an HTTP server where campaigns can be submitted and tracked.",
+		Long:    serveHelp,
+		Args:    cobra.NoArgs,
+		PreRunE: logFlagInit,
+		RunE:    serve,
+	}
+
+	cmd.Flags().StringP("listen", "", ":8080", "The address the server should listen on.")
+	cmd.Flags().StringP("data-dir", "", "multi-gitter-data", "The directory where jobs, their logs and their output are stored.")
+	cmd.Flags().StringP("api-token", "", "", "If set, every request has to contain this value as a bearer token. Can also be set using the MULTI_GITTER_API_TOKEN environment variable.")
+	cmd.Flags().IntP("queue-size", "", 100, "The maximum number of jobs that can be waiting to run.")
+	cmd.Flags().IntP("max-jobs", "", 4, "The maximum number of jobs that run at the same time.")
+	cmd.Flags().StringP("platform", "p", "github", "The platform of all jobs. Available values: github, gitlab, gitea, bitbucket_server, bitbucket_cloud, gerrit, azure_devops.")
+	cmd.Flags().StringP("base-url", "g", "", "Base URL of the target platform of all jobs, needs to be changed for GitHub enterprise, a self-hosted GitLab instance, Gitea or BitBucket, Gerrit, or Azure DevOps Server.")
+	cmd.Flags().StringSliceP("profile", "", nil, "The names of the profiles, defined in the config file, that jobs are allowed to use.")
+	cmd.Flags().StringP("script-dir", "", "", "The directory with the scripts that run jobs can use. If not set, run jobs are not allowed.")
+	configureLogging(cmd, "-")
+	configureConfig(cmd)
+
+	return cmd
+}
+
+func serve(cmd *cobra.Command, _ []string) error {
+	flag := cmd.Flags()
+
+	listen, _ := flag.GetString("listen")
+	dataDir, _ := flag.GetString("data-dir")
+	apiToken, _ := flag.GetString("api-token")
+	queueSize, _ := flag.GetInt("queue-size")
+	maxJobs, _ := flag.GetInt("max-jobs")
+	platform, _ := flag.GetString("platform")
+	baseURL, _ := flag.GetString("base-url")
+	profiles, _ := flag.GetStringSlice("profile")
+	scriptDir, _ := flag.GetString("script-dir")
+
+	if apiToken == "" {
+		apiToken = os.Getenv("MULTI_GITTER_API_TOKEN")
+	}
+
+	if queueSize < 1 {
+		return errors.New("queue-size can't be less than one")
+	}
+	if maxJobs < 1 {
+		return errors.New("max-jobs can't be less than one")
+	}
+
+	policy := server.JobPolicy{
+		Platform:  platform,
+		BaseURL:   baseURL,
+		Profiles:  profiles,
+		ScriptDir: scriptDir,
+	}
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	store, err := server.NewStore(dataDir)
+	if err != nil {
+		return err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return errors.Wrap(err, "could not find the multi-gitter executable")
+	}
+	configFiles, _ := flag.GetStringArray("config")
+
+	srv := server.New(server.Config{
+		Store: store,
+		Executor: func(ctx context.Context, req server.JobRequest, outputPath string, logs io.Writer) error {
+			return executeJob(ctx, executable, jobArgs(req, outputPath, policy, configFiles), logs)
+		},
+		APIToken:  apiToken,
+		QueueSize: queueSize,
+		MaxJobs:   maxJobs,
+		JobPolicy: policy,
+	})
+
+	ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	srv.Start(ctx)
+
+	httpServer := &http.Server{
+		Addr:              listen,
+		Handler:           srv.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	log.Infof("Listening on %s", listen)
+	if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+
+	return nil
+}
+
+// jobArgs returns the arguments that multi-gitter is started with to run a job, in the same way as it would have been run from the command line.
+// The job always uses the platform and base URL of the server, and writes its logs as json to stderr
+func jobArgs(req server.JobRequest, outputPath string, policy server.JobPolicy, configFiles []string) []string {
+	args := []string{req.Command}
+
+	names := make([]string, 0, len(req.Flags))
+	for name := range req.Flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		switch val := req.Flags[name].(type) {
+		case []interface{}:
+			for _, v := range val {
+				args = append(args, fmt.Sprintf("--%s=%v", name, v))
+			}
+		default:
+			args = append(args, fmt.Sprintf("--%s=%v", name, val))
+		}
+	}
+
+	args = append(args,
+		"--platform="+policy.Platform,
+		"--base-url="+policy.BaseURL,
+		"--output="+outputPath,
+		"--log-format=json",
+		"--log-file=-",
+		"--log-level="+log.GetLevel().String(),
+	)
+	for _, file := range configFiles {
+		args = append(args, "--config="+file)
+	}
+
+	return append(args, req.Args...)
+}
+
+// executeJob runs a job in a new multi-gitter process. The logs of the job are written to logs
+func executeJob(ctx context.Context, executable string, args []string, logs io.Writer) error {
+	errWriter := &commandErrorWriter{w: logs}
+
+	cmd := exec.CommandContext(ctx, executable, args...)
+	cmd.Stdout = errWriter
+	cmd.Stderr = errWriter
+	// Let the job finish ongoing work, in the same way as when it's canceled from the command line
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = time.Minute
+
+	if err := cmd.Run(); err != nil {
+		if msg := errWriter.lastError(); msg != "" {
+			return errors.New(msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// commandErrorWriter passes on the json log entries written to it. Other lines are printed by multi-gitter itself,
+// like the usage and the error of a failed command, and the last of them is kept as the error of the job
+type commandErrorWriter struct {
+	w       io.Writer
+	partial []byte
+	last    string
+}
+
+func (w *commandErrorWriter) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		if err := w.addLine(w.partial[:i+1]); err != nil {
+			return 0, err
+		}
+		w.partial = w.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+func (w *commandErrorWriter) addLine(line []byte) error {
+	trimmed := bytes.TrimSpace(line)
+	switch {
+	case len(trimmed) == 0:
+		return nil
+	case json.Valid(trimmed):
+		_, err := w.w.Write(line)
+		return err
+	default:
+		w.last = string(trimmed)
+		return nil
+	}
+}
+
+func (w *commandErrorWriter) lastError() string {
+	_ = w.addLine(w.partial)
+	w.partial = nil
+	return w.last
+}
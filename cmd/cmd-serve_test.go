@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lindell/multi-gitter/internal/server"
+)
+
+func TestJobArgs(t *testing.T) {
+	req := server.JobRequest{
+		Command: "run",
+		Args:    []string{"/scripts/script.sh"},
+		Flags: map[string]interface{}{
+			"org":        []interface{}{"org1", "org2"},
+			"branch":     "my-branch",
+			"concurrent": float64(4),
+			"dry-run":    true,
+		},
+	}
+	policy := server.JobPolicy{Platform: "gitlab", BaseURL: "https://git.example.com"}
+
+	assert.Equal(t, []string{
+		"run",
+		"--branch=my-branch",
+		"--concurrent=4",
+		"--dry-run=true",
+		"--org=org1",
+		"--org=org2",
+		"--platform=gitlab",
+		"--base-url=https://git.example.com",
+		"--output=/data/output.txt",
+		"--log-format=json",
+		"--log-file=-",
+		"--log-level=" + log.GetLevel().String(),
+		"--config=/config.yaml",
+		"/scripts/script.sh",
+	}, jobArgs(req, "/data/output.txt", policy, []string{"/config.yaml"}))
+}
+
+func TestCommandErrorWriter(t *testing.T) {
+	logs := &bytes.Buffer{}
+	w := &commandErrorWriter{w: logs}
+
+	_, _ = w.Write([]byte("{\"msg\":\"Doing things\"}\nsome err"))
+	_, _ = w.Write([]byte("or\n{\"msg\":\"Done\"}\n"))
+	_, _ = w.Write([]byte("the error"))
+
+	assert.Equal(t, "the error", w.lastError())
+	assert.Equal(t, "{\"msg\":\"Doing things\"}\n{\"msg\":\"Done\"}\n", logs.String())
+}
@@ -1,8 +1,8 @@
 package cmd
 
 import (
-	"context"
 	"os"
+	"time"
 
 	"github.com/lindell/multi-gitter/internal/multigitter"
 	"github.com/spf13/cobra"
@@ -20,8 +20,24 @@ func StatusCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringP("branch", "B", "multi-gitter-branch", "The name of the branch where changes are committed.")
+	cmd.Flags().StringP("format", "", "text", `The format of the output. Available values:
+  text: One line with the status of each pull request.
+  table: A table with the status, checks, review decision and other details of each pull request.
+  json: A JSON array with all details of each pull request, including individual checks.
+  csv: A CSV file with all details of each pull request, including individual checks.
+`)
+	_ = cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{"text", "table", "json", "csv"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().BoolP("watch", "", false, "Keep polling the pull requests and show a live summary until all of them are merged, closed or failed. "+
+		"Checks that failed, conflicts and requested changes count as failed. The exit code is non-zero if any pull request failed or the watch timed out.")
+	cmd.Flags().DurationP("watch-interval", "", time.Minute, "The time between each poll of the pull requests when using --watch.")
+	cmd.Flags().DurationP("watch-timeout", "", 0, "The maximum time to watch the pull requests when using --watch, for example 2h. No timeout is used if not set.")
+	configurePullRequestStatus(cmd, "Only show pull requests with one of these statuses.")
 	configurePlatform(cmd)
+	configureRepoFilters(cmd)
 	configureRunPlatform(cmd, false)
+	configureCampaign(cmd, false)
 	configureLogging(cmd, "-")
 	configureConfig(cmd)
 	cmd.Flags().AddFlagSet(outputFlag())
@@ -32,8 +48,32 @@ func StatusCmd() *cobra.Command {
 func status(cmd *cobra.Command, _ []string) error {
 	flag := cmd.Flags()
 
+	includePullRequest, err := useCampaign(flag)
+	if err != nil {
+		return err
+	}
+	includePullRequest, err = usePullRequestStatus(flag, includePullRequest)
+	if err != nil {
+		return err
+	}
+
 	branchName, _ := flag.GetString("branch")
+	hasFiles, _ := flag.GetStringSlice("has-file")
 	strOutput, _ := flag.GetString("output")
+	strFormat, _ := flag.GetString("format")
+	watch, _ := flag.GetBool("watch")
+	watchInterval, _ := flag.GetDuration("watch-interval")
+	watchTimeout, _ := flag.GetDuration("watch-timeout")
+
+	format, err := multigitter.ParseStatusFormat(strFormat)
+	if err != nil {
+		return err
+	}
+
+	filters, err := parseRepoFilters(flag)
+	if err != nil {
+		return err
+	}
 
 	vc, err := getVersionController(flag, true, false)
 	if err != nil {
@@ -49,11 +89,21 @@ func status(cmd *cobra.Command, _ []string) error {
 		VersionController: vc,
 
 		Output: output,
+		Format: format,
 
 		FeatureBranch: branchName,
+		PullRequestSelection: multigitter.PullRequestSelection{
+			IncludePullRequest: includePullRequest,
+			RepoFilters:        filters,
+			HasFiles:           hasFiles,
+		},
+
+		Watch:         watch,
+		WatchInterval: watchInterval,
+		WatchTimeout:  watchTimeout,
 	}
 
-	err = statuser.Statuses(context.Background())
+	err = statuser.Statuses(cmd.Context())
 	if err != nil {
 		return err
 	}
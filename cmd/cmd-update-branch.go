@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/lindell/multi-gitter/internal/git"
+	"github.com/lindell/multi-gitter/internal/git/cmdgit"
+	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// UpdateBranchCmd updates the branches of pull requests with their base branch
+func UpdateBranchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update-branch",
+		Short: "Update the branches of pull requests with their base branch.",
+		Long: `Update the branches of pull requests with a specified branch name in an organization and with specified conditions, with the latest changes of their base branch.
+
+The update is done through the API of the platform when it supports it, like "Update branch" on GitHub and Gitea, or rebasing on GitLab.
+Otherwise, the branch is cloned, updated and pushed, which requires git to be installed. Pull requests made from forks can't be updated this way.
+Pull requests that conflict with their base branch are reported, and left as they are.`,
+		Args:    cobra.NoArgs,
+		PreRunE: logFlagInit,
+		RunE:    updateBranch,
+	}
+
+	cmd.Flags().StringP("branch", "B", "multi-gitter-branch", "The name of the branch where changes are committed.")
+	cmd.Flags().BoolP("rebase", "", false, "Rebase the branches onto the base branch, instead of merging the base branch into them.")
+	cmd.Flags().StringP("base-branch", "", "", "The branch the pull requests are updated with when they are updated locally. If not set, the default branch of each repository is used.")
+	cmd.Flags().StringP("author-name", "", "", "Name of the committer of merge commits and rebased commits made locally. If not set, the global git config setting will be used.")
+	cmd.Flags().StringP("author-email", "", "", "Email of the committer of merge commits and rebased commits made locally. If not set, the global git config setting will be used.")
+	cmd.Flags().StringP("clone-dir", "", "", "The temporary directory where the repositories will be cloned. If not set, the default os temporary directory will be used.")
+	configurePullRequestStatus(cmd, "Only update pull requests with one of these statuses.")
+	configurePullRequestFilters(cmd, "update")
+	cmd.Flags().BoolP("dry-run", "d", false, "List the pull requests that would be updated, without updating them.")
+	configurePlatform(cmd)
+	configureRepoFilters(cmd)
+	configureRunPlatform(cmd, false)
+	configureCampaign(cmd, false)
+	configureLogging(cmd, "-")
+	configureConfig(cmd)
+	cmd.Flags().AddFlagSet(outputFlag())
+
+	return cmd
+}
+
+func updateBranch(cmd *cobra.Command, _ []string) error {
+	flag := cmd.Flags()
+
+	includePullRequest, err := useCampaign(flag)
+	if err != nil {
+		return err
+	}
+	includePullRequest, err = usePullRequestStatus(flag, includePullRequest)
+	if err != nil {
+		return err
+	}
+	includePullRequest, err = usePullRequestFilters(flag, includePullRequest)
+	if err != nil {
+		return err
+	}
+
+	branchName, _ := flag.GetString("branch")
+	rebase, _ := flag.GetBool("rebase")
+	baseBranch, _ := flag.GetString("base-branch")
+	authorName, _ := flag.GetString("author-name")
+	authorEmail, _ := flag.GetString("author-email")
+	cloneDir, _ := flag.GetString("clone-dir")
+	hasFiles, _ := flag.GetStringSlice("has-file")
+	dryRun, _ := flag.GetBool("dry-run")
+	strOutput, _ := flag.GetString("output")
+
+	var commitAuthor *git.CommitAuthor
+	if authorName != "" || authorEmail != "" {
+		if authorName == "" || authorEmail == "" {
+			return errors.New("both author-name and author-email has to be set if the other is set")
+		}
+		commitAuthor = &git.CommitAuthor{
+			Name:  authorName,
+			Email: authorEmail,
+		}
+	}
+
+	filters, err := parseRepoFilters(flag)
+	if err != nil {
+		return err
+	}
+
+	vc, err := getVersionController(flag, true, false)
+	if err != nil {
+		return err
+	}
+
+	output, err := fileOutput(strOutput, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	updater := multigitter.BranchUpdater{
+		VersionController: vc,
+
+		Output: output,
+
+		FeatureBranch: branchName,
+		PullRequestSelection: multigitter.PullRequestSelection{
+			IncludePullRequest: includePullRequest,
+			RepoFilters:        filters,
+			HasFiles:           hasFiles,
+		},
+
+		Rebase: rebase,
+
+		BaseBranch:   baseBranch,
+		CommitAuthor: commitAuthor,
+		CloneDir:     cloneDir,
+		// Merging and rebasing needs the git command, and the entire history of the branches
+		CreateGit: func(dir string) multigitter.Git {
+			return &cmdgit.Git{
+				Directory: dir,
+			}
+		},
+
+		DryRun: dryRun,
+	}
+
+	return updater.Update(cmd.Context())
+}
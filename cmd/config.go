@@ -70,16 +70,29 @@ func bindFlags(cmd *cobra.Command, v *viper.Viper) {
 	cmd.Flags().VisitAll(func(f *pflag.Flag) {
 		// Apply the viper config value to the flag when the flag is not set and viper has a value
 		if !f.Changed && v.IsSet(f.Name) {
-			val := v.Get(f.Name)
-
-			switch val := val.(type) {
-			case []interface{}:
-				for _, v := range val {
-					_ = cmd.Flags().Set(f.Name, fmt.Sprintf("%v", v))
-				}
-			default:
-				_ = cmd.Flags().Set(f.Name, fmt.Sprintf("%v", val))
-			}
+			_ = setFlagValue(cmd.Flags(), f.Name, v.Get(f.Name))
 		}
 	})
 }
+
+// setFlagValue sets a flag from a value parsed from a config file (or similar),
+// list values are added one by one to allow them to be used with slice flags
+func setFlagValue(flags *pflag.FlagSet, name string, val interface{}) error {
+	switch val := val.(type) {
+	case []interface{}:
+		for _, v := range val {
+			if err := flags.Set(name, fmt.Sprintf("%v", v)); err != nil {
+				return err
+			}
+		}
+	case []string:
+		for _, v := range val {
+			if err := flags.Set(name, v); err != nil {
+				return err
+			}
+		}
+	default:
+		return flags.Set(name, fmt.Sprintf("%v", val))
+	}
+	return nil
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/spf13/pflag"
 
 	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/lindell/multi-gitter/internal/repofilter"
+	"github.com/lindell/multi-gitter/internal/scm"
 )
 
 // configureRepoFilters adds the repository filtering flags to a command
@@ -15,6 +17,18 @@ func configureRepoFilters(cmd *cobra.Command) {
 	cmd.Flags().StringP("repo-include", "", "", "Include repositories that match with a given Regular Expression")
 	cmd.Flags().StringP("repo-exclude", "", "", "Exclude repositories that match with a given Regular Expression")
 	cmd.Flags().StringSliceP("skip-repo", "s", nil, "Skip specified repositories, the name is including the owner of repository in the format \"ownerName/repoName\".")
+	cmd.Flags().StringSliceP("language", "", nil, "Only include repositories with one of the specified primary languages. Repositories where the language is unknown are skipped.")
+	cmd.Flags().StringSliceP("visibility", "", nil, "Only include repositories with one of the specified visibilities. Can be public, private or internal.")
+	cmd.Flags().StringP("pushed-after", "", "", "Only include repositories that have been pushed to after the specified date (2006-01-02) or time (RFC 3339).")
+	cmd.Flags().BoolP("exclude-archived", "", false, "Exclude archived repositories.")
+	cmd.Flags().StringSliceP("has-file", "", nil, "Only include repositories that contain a file or directory matching one of the specified paths or glob patterns, "+
+		"for example \".github/workflows/ci.yml\" or \"**/*.tf\". Checked through the API of the platform before cloning.")
+	cmd.Flags().StringP("filter", "", "", "Only include repositories matching a filter expression, for example "+
+		"'language == go && topics contains backend && !archived && owner != team-x'. "+
+		"The fields name, owner, repo, language, visibility, topics, archived, fork, pushed and size can be used.")
+	_ = cmd.RegisterFlagCompletionFunc("visibility", func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{"public", "private", "internal"}, cobra.ShellCompDirectiveNoFileComp
+	})
 }
 
 // parseRepoFilters parses flags into a multigitter.RepoFilters struct
@@ -22,27 +36,60 @@ func parseRepoFilters(flag *pflag.FlagSet) (multigitter.RepoFilters, error) {
 	repoInclude, _ := flag.GetString("repo-include")
 	repoExclude, _ := flag.GetString("repo-exclude")
 	skipRepository, _ := flag.GetStringSlice("skip-repo")
+	languages, _ := flag.GetStringSlice("language")
+	visibilityStrs, _ := flag.GetStringSlice("visibility")
+	pushedAfterStr, _ := flag.GetString("pushed-after")
+	excludeArchived, _ := flag.GetBool("exclude-archived")
+	filterExpr, _ := flag.GetString("filter")
 
-	var regExIncludeRepository *regexp.Regexp
-	var regExExcludeRepository *regexp.Regexp
+	var filters multigitter.RepoFilters
+	if len(skipRepository) > 0 {
+		filters = append(filters, repofilter.SkipRepositories(skipRepository))
+	}
 	if repoInclude != "" {
 		compiled, err := regexp.Compile(repoInclude)
 		if err != nil {
-			return multigitter.RepoFilters{}, errors.WithMessage(err, "could not parse repo-include")
+			return nil, errors.WithMessage(err, "could not parse repo-include")
 		}
-		regExIncludeRepository = compiled
+		filters = append(filters, repofilter.IncludeRegexp(compiled))
 	}
 	if repoExclude != "" {
 		compiled, err := regexp.Compile(repoExclude)
 		if err != nil {
-			return multigitter.RepoFilters{}, errors.WithMessage(err, "could not parse repo-exclude")
+			return nil, errors.WithMessage(err, "could not parse repo-exclude")
+		}
+		filters = append(filters, repofilter.ExcludeRegexp(compiled))
+	}
+
+	metadataFilter := repofilter.Metadata{
+		Languages:       languages,
+		ExcludeArchived: excludeArchived,
+	}
+	for _, str := range visibilityStrs {
+		visibility, err := scm.ParseVisibility(str)
+		if err != nil {
+			return nil, errors.WithMessage(err, "could not parse visibility")
+		}
+		metadataFilter.Visibilities = append(metadataFilter.Visibilities, visibility)
+	}
+	if pushedAfterStr != "" {
+		pushedAfter, err := repofilter.ParseTime(pushedAfterStr)
+		if err != nil {
+			return nil, errors.WithMessage(err, "could not parse pushed-after")
+		}
+		metadataFilter.PushedAfter = pushedAfter
+	}
+	if !metadataFilter.IsZero() {
+		filters = append(filters, metadataFilter)
+	}
+
+	if filterExpr != "" {
+		expr, err := repofilter.ParseExpression(filterExpr)
+		if err != nil {
+			return nil, errors.WithMessage(err, "could not parse filter")
 		}
-		regExExcludeRepository = compiled
+		filters = append(filters, expr)
 	}
 
-	return multigitter.RepoFilters{
-		SkipRepository:         skipRepository,
-		RegExIncludeRepository: regExIncludeRepository,
-		RegExExcludeRepository: regExExcludeRepository,
-	}, nil
+	return filters, nil
 }
@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/lindell/multi-gitter/internal/campaign"
+	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/lindell/multi-gitter/internal/scm"
+)
+
+// manifestVersion is the only version of the manifest format that is currently supported
+const manifestVersion = 1
+
+// manifest describes a campaign, a change that should be made to a set of repositories
+// together with how the pull requests should be created and merged
+type manifest struct {
+	Version     int                    `mapstructure:"version"`
+	Name        string                 `mapstructure:"name"`
+	Targets     map[string]interface{} `mapstructure:"targets"`
+	Filters     map[string]interface{} `mapstructure:"filters"`
+	Script      string                 `mapstructure:"script"`
+	Steps       []string               `mapstructure:"steps"`
+	Branch      string                 `mapstructure:"branch"`
+	BaseBranch  string                 `mapstructure:"base-branch"`
+	PullRequest map[string]interface{} `mapstructure:"pull-request"`
+	Rollout     map[string]interface{} `mapstructure:"rollout"`
+	Merge       map[string]interface{} `mapstructure:"merge"`
+
+	// dir is the directory of the manifest file, relative script paths are resolved from it
+	dir string
+}
+
+// manifestSections maps the keys allowed in each section of the manifest to the flag they set
+var manifestSections = map[string]map[string]string{
+	"targets": sameNames(
+		"profile", "platform", "base-url", "insecure", "username", "auth-type",
+		"org", "group", "user", "repo", "project", "repo-file", "repo-search", "code-search",
+		"include-subgroups", "ssh-auth",
+	),
+	"filters": sameNames(
+		"repo-include", "repo-exclude", "skip-repo", "topic", "skip-forks",
+		"language", "visibility", "pushed-after", "exclude-archived", "filter",
+		"has-file",
+	),
+	"pull-request": {
+		"title":                      "pr-title",
+		"body":                       "pr-body",
+		"commit-message":             "commit-message",
+		"reviewers":                  "reviewers",
+		"team-reviewers":             "team-reviewers",
+		"max-reviewers":              "max-reviewers",
+		"max-team-reviewers":         "max-team-reviewers",
+		"codeowners":                 "codeowners",
+		"reviewer-rotation-file":     "reviewer-rotation-file",
+		"unavailable-reviewers-file": "unavailable-reviewers-file",
+		"assignees":                  "assignees",
+		"labels":                     "labels",
+		"draft":                      "draft",
+		"auto-merge":                 "pr-auto-merge",
+	},
+	"rollout": sameNames(
+		"concurrent", "dry-run", "conflict-strategy", "skip-pr", "push-only", "api-push",
+		"manual-commit", "push-option", "author-name", "author-email", "clone-dir",
+		"git-type", "fetch-depth", "fork", "fork-owner",
+	),
+	"merge": sameNames(
+		"merge-type", "wait-for-checks", "check-interval", "required-approvals", "merge-interval",
+		"concurrent", "dry-run",
+	),
+}
+
+// manifestActionKeys are keys that only apply to one action, since both the run and merge command has flags with the same name
+var manifestActionKeys = map[string]map[string]string{
+	"rollout": {"concurrent": "run", "dry-run": "run"},
+	"merge":   {"concurrent": "merge", "dry-run": "merge"},
+}
+
+func sameNames(names ...string) map[string]string {
+	ret := make(map[string]string, len(names))
+	for _, name := range names {
+		ret[name] = name
+	}
+	return ret
+}
+
+// readManifest reads and validates a manifest file
+func readManifest(path string) (manifest, error) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return manifest{}, errors.WithMessage(err, "could not read manifest")
+	}
+
+	var m manifest
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		ErrorUnused:      true,
+		WeaklyTypedInput: true,
+		Result:           &m,
+	})
+	if err != nil {
+		return manifest{}, err
+	}
+	if err := decoder.Decode(v.AllSettings()); err != nil {
+		return manifest{}, errors.WithMessage(err, "could not parse manifest")
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return manifest{}, err
+	}
+	m.dir = filepath.Dir(absPath)
+
+	// Relative files are relative to the manifest
+	if repoFile, ok := m.Targets["repo-file"].(string); ok && repoFile != "-" && !filepath.IsAbs(repoFile) {
+		m.Targets["repo-file"] = filepath.Join(m.dir, repoFile)
+	}
+	for _, key := range []string{"reviewer-rotation-file", "unavailable-reviewers-file"} {
+		if file, ok := m.PullRequest[key].(string); ok && file != "" && !filepath.IsAbs(file) {
+			m.PullRequest[key] = filepath.Join(m.dir, file)
+		}
+	}
+
+	if err := m.validate(); err != nil {
+		return manifest{}, errors.WithMessagef(err, "invalid manifest %s", path)
+	}
+
+	return m, nil
+}
+
+func (m manifest) sections() map[string]map[string]interface{} {
+	return map[string]map[string]interface{}{
+		"targets":      m.Targets,
+		"filters":      m.Filters,
+		"pull-request": m.PullRequest,
+		"rollout":      m.Rollout,
+		"merge":        m.Merge,
+	}
+}
+
+// validate checks the manifest for errors that can be found without contacting any platform
+func (m manifest) validate() error {
+	var problems []string
+
+	if m.Version != manifestVersion {
+		problems = append(problems, fmt.Sprintf("unsupported version %d, the supported version is %d", m.Version, manifestVersion))
+	}
+	if m.Name == "" {
+		problems = append(problems, "name has to be set")
+	} else if err := campaign.ValidateName(m.Name); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if m.Branch == "" {
+		problems = append(problems, "branch has to be set")
+	}
+	if len(m.Targets) == 0 {
+		problems = append(problems, "targets has to be set")
+	}
+	if m.Script == "" && len(m.Steps) == 0 {
+		problems = append(problems, "either script or steps has to be set")
+	} else if m.Script != "" && len(m.Steps) > 0 {
+		problems = append(problems, "script and steps can't both be set")
+	}
+	if m.PullRequest["title"] == nil && m.PullRequest["commit-message"] == nil && m.Rollout["manual-commit"] != true {
+		problems = append(problems, "pull-request.title or pull-request.commit-message has to be set")
+	}
+
+	for sectionName, section := range m.sections() {
+		allowed := manifestSections[sectionName]
+		for key := range section {
+			if _, ok := allowed[key]; !ok {
+				problems = append(problems, fmt.Sprintf(`unknown key "%s" in %s`, key, sectionName))
+			}
+		}
+	}
+
+	// Validate that all values can be parsed by the flags they are set to
+	if err := m.setFlags(MergeCmd(), "merge"); err != nil {
+		problems = append(problems, err.Error())
+	}
+	command := RunCmd()
+	if err := m.setFlags(command, "run"); err != nil {
+		problems = append(problems, err.Error())
+	} else {
+		conflictStrategy, _ := command.Flags().GetString("conflict-strategy")
+		if _, err := multigitter.ParseConflictStrategy(conflictStrategy); err != nil {
+			problems = append(problems, err.Error())
+		}
+		mergeTypes, _ := command.Flags().GetStringSlice("merge-type")
+		for _, mergeType := range mergeTypes {
+			if _, err := scm.ParseMergeType(mergeType); err != nil {
+				problems = append(problems, err.Error())
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		problems = slices.Compact(problems)
+		return errors.New(strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+// flagValues returns the flags the manifest sets for an action, by flag name
+func (m manifest) flagValues(action string) map[string]interface{} {
+	values := map[string]interface{}{
+		"campaign": m.Name,
+		"branch":   m.Branch,
+	}
+	if m.BaseBranch != "" {
+		values["base-branch"] = m.BaseBranch
+	}
+
+	for sectionName, section := range m.sections() {
+		for key, value := range section {
+			if keyAction, ok := manifestActionKeys[sectionName][key]; ok && keyAction != action {
+				continue
+			}
+			if flagName, ok := manifestSections[sectionName][key]; ok {
+				values[flagName] = value
+			}
+		}
+	}
+
+	return values
+}
+
+// setFlags sets all flags defined in the manifest for an action that exist on the command and has not already been set
+func (m manifest) setFlags(cmd *cobra.Command, action string) error {
+	values := m.flagValues(action)
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	flags := cmd.Flags()
+	for _, name := range names {
+		f := flags.Lookup(name)
+		if f == nil || f.Changed {
+			continue
+		}
+		if err := setFlagValue(flags, name, values[name]); err != nil {
+			return errors.WithMessagef(err, `could not set "%s"`, name)
+		}
+	}
+
+	return nil
+}
+
+// command returns the script that should be run in each repository. If steps are used, they are written to
+// a temporary script, and the returned cleanup function removes it
+func (m manifest) command() (command string, cleanup func(), err error) {
+	if len(m.Steps) == 0 {
+		return m.resolvedScript()
+	}
+
+	file, err := os.CreateTemp("", "multi-gitter-steps-*.sh")
+	if err != nil {
+		return "", nil, errors.Wrap(err, "could not create script from steps")
+	}
+	cleanup = func() { _ = os.Remove(file.Name()) }
+
+	script := "#!/bin/sh\nset -e\n" + strings.Join(m.Steps, "\n") + "\n"
+	_, err = file.WriteString(script)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(file.Name(), 0700)
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, errors.Wrap(err, "could not create script from steps")
+	}
+
+	return file.Name(), cleanup, nil
+}
+
+// resolvedScript returns the script of the manifest, where a relative script path is made relative to the manifest
+func (m manifest) resolvedScript() (string, func(), error) {
+	parsed, err := parseCommandLine(m.Script)
+	if err != nil || len(parsed) == 0 {
+		return m.Script, func() {}, nil
+	}
+
+	executable := parsed[0]
+	if filepath.IsAbs(executable) || !strings.ContainsAny(executable, `/\`) {
+		return m.Script, func() {}, nil
+	}
+
+	parsed[0] = filepath.Join(m.dir, executable)
+	for i := range parsed {
+		if strings.ContainsAny(parsed[i], " \t\"'\\") {
+			parsed[i] = "'" + parsed[i] + "'"
+		}
+	}
+	return strings.Join(parsed, " "), func() {}, nil
+}
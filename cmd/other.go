@@ -3,6 +3,10 @@ package cmd
 import (
 	"io"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/lindell/multi-gitter/internal/scm"
 	"github.com/pkg/errors"
@@ -19,7 +23,7 @@ func outputFlag() *flag.FlagSet {
 }
 
 func configureMergeType(cmd *cobra.Command, includeAutoMergeText bool) {
-	description := "The type of merge that should be done (GitHub/Gitea). Multiple types can be used as backup strategies if the first one is not allowed."
+	description := "The type of merge that should be done (GitHub/Gitea/Azure DevOps). Multiple types can be used as backup strategies if the first one is not allowed."
 	if includeAutoMergeText {
 		description += " The first type is used for auto-merge."
 	}
@@ -30,6 +34,118 @@ func configureMergeType(cmd *cobra.Command, includeAutoMergeText bool) {
 	})
 }
 
+func configurePullRequestStatus(cmd *cobra.Command, description string) {
+	var statuses []string
+	for _, status := range scm.AllPullRequestStatuses {
+		statuses = append(statuses, status.String())
+	}
+
+	cmd.Flags().StringSliceP("status", "", nil, description+" Available values: "+strings.Join(statuses, ", ")+".")
+	_ = cmd.RegisterFlagCompletionFunc("status", func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return statuses, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// usePullRequestStatus extends include to only include pull requests with one of the statuses set with the --status flag
+func usePullRequestStatus(flag *flag.FlagSet, include func(pr scm.PullRequest) bool) (func(pr scm.PullRequest) bool, error) {
+	strStatuses, _ := flag.GetStringSlice("status")
+	if len(strStatuses) == 0 {
+		return include, nil
+	}
+
+	statuses := make([]scm.PullRequestStatus, 0, len(strStatuses))
+	for _, str := range strStatuses {
+		status, err := scm.ParsePullRequestStatus(str)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+
+	return func(pr scm.PullRequest) bool {
+		if include != nil && !include(pr) {
+			return false
+		}
+		return slices.Contains(statuses, pr.Status())
+	}, nil
+}
+
+// configurePullRequestFilters adds flags to only use pull requests with some labels or of some age
+func configurePullRequestFilters(cmd *cobra.Command, verb string) {
+	cmd.Flags().StringSliceP("label", "", nil, "Only "+verb+" pull requests that have at least one of these labels.")
+	cmd.Flags().StringP("older-than", "", "", "Only "+verb+` pull requests that were created longer ago than this. For example "72h" or "30d".`)
+	cmd.Flags().StringP("newer-than", "", "", "Only "+verb+` pull requests that were created more recently than this. For example "72h" or "30d".`)
+}
+
+// usePullRequestFilters extends include to only include pull requests that match the --label, --older-than and --newer-than flags.
+// Pull requests where the platform does not provide the creation time are not included when filtering on age
+func usePullRequestFilters(flag *flag.FlagSet, include func(pr scm.PullRequest) bool) (func(pr scm.PullRequest) bool, error) {
+	labels, _ := flag.GetStringSlice("label")
+	strOlderThan, _ := flag.GetString("older-than")
+	strNewerThan, _ := flag.GetString("newer-than")
+
+	olderThan, err := parseAge(strOlderThan)
+	if err != nil {
+		return nil, err
+	}
+	newerThan, err := parseAge(strNewerThan)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(labels) == 0 && olderThan == 0 && newerThan == 0 {
+		return include, nil
+	}
+
+	now := time.Now()
+	return func(pr scm.PullRequest) bool {
+		if include != nil && !include(pr) {
+			return false
+		}
+
+		details := scm.GetPullRequestDetails(pr)
+		if len(labels) > 0 && !slices.ContainsFunc(details.Labels, func(label string) bool {
+			return slices.ContainsFunc(labels, func(l string) bool { return strings.EqualFold(l, label) })
+		}) {
+			return false
+		}
+
+		if olderThan > 0 || newerThan > 0 {
+			if details.CreatedAt.IsZero() {
+				return false
+			}
+			age := now.Sub(details.CreatedAt)
+			if (olderThan > 0 && age < olderThan) || (newerThan > 0 && age > newerThan) {
+				return false
+			}
+		}
+
+		return true
+	}, nil
+}
+
+// parseAge parses a duration that, in addition to the units of time.ParseDuration, can be given in days or weeks, like "30d" or "2w"
+func parseAge(str string) (time.Duration, error) {
+	if str == "" {
+		return 0, nil
+	}
+
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if number, found := strings.CutSuffix(str, suffix); found {
+			if n, err := strconv.Atoi(number); err == nil && n >= 0 {
+				return time.Duration(n) * unit, nil
+			}
+		}
+	}
+
+	age, err := time.ParseDuration(str)
+	if err != nil || age < 0 {
+		return 0, errors.Errorf(`not a valid age: "%s"`, str)
+	}
+	return age, nil
+}
+
 func getToken(flag *flag.FlagSet) (string, error) {
 	if OverrideVersionController != nil {
 		return "", nil
@@ -52,11 +168,13 @@ func getToken(flag *flag.FlagSet) (string, error) {
 			token = ght
 		} else if ght := os.Getenv("GERRIT_TOKEN"); ght != "" {
 			token = ght
+		} else if ght := os.Getenv("AZURE_DEVOPS_TOKEN"); ght != "" {
+			token = ght
 		}
 	}
 
 	if token == "" {
-		return "", errors.New("either the --token flag or the GITHUB_TOKEN/GITLAB_TOKEN/GITEA_TOKEN/BITBUCKET_SERVER_TOKEN/BITBUCKET_CLOUD_APP_PASSWORD/BITBUCKET_CLOUD_WORKSPACE_TOKEN/GERRIT_TOKEN environment variable has to be set")
+		return "", errors.New("either the --token flag or the GITHUB_TOKEN/GITLAB_TOKEN/GITEA_TOKEN/BITBUCKET_SERVER_TOKEN/BITBUCKET_CLOUD_APP_PASSWORD/BITBUCKET_CLOUD_WORKSPACE_TOKEN/GERRIT_TOKEN/AZURE_DEVOPS_TOKEN environment variable has to be set")
 	}
 
 	return token, nil
@@ -8,12 +8,15 @@ import (
 
 	"github.com/lindell/multi-gitter/internal/http"
 	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/lindell/multi-gitter/internal/scm/azuredevops"
 	"github.com/lindell/multi-gitter/internal/scm/bitbucketcloud"
 	"github.com/lindell/multi-gitter/internal/scm/bitbucketserver"
 	"github.com/lindell/multi-gitter/internal/scm/gerrit"
 	"github.com/lindell/multi-gitter/internal/scm/gitea"
 	"github.com/lindell/multi-gitter/internal/scm/github"
 	"github.com/lindell/multi-gitter/internal/scm/gitlab"
+	"github.com/lindell/multi-gitter/internal/scm/local"
+	"github.com/lindell/multi-gitter/internal/scm/plaingit"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -23,30 +26,33 @@ import (
 func configurePlatform(cmd *cobra.Command) {
 	flags := cmd.Flags()
 
-	flags.StringP("base-url", "g", "", "Base URL of the target platform, needs to be changed for GitHub enterprise, a self-hosted GitLab instance, Gitea or BitBucket, Gerrit.")
+	flags.StringP("base-url", "g", "", "Base URL of the target platform, needs to be changed for GitHub enterprise, a self-hosted GitLab instance, Gitea or BitBucket, Gerrit, or Azure DevOps Server. For the local platform, this is the directory containing the repositories.")
 	flags.BoolP("insecure", "", false, "Insecure controls whether a client verifies the server certificate chain and host name. Used only for Bitbucket server.")
 	flags.StringP("username", "u", "", "The Bitbucket server username.")
-	flags.StringP("token", "T", "", "The personal access token for the targeting platform. Can also be set using the GITHUB_TOKEN/GITLAB_TOKEN/GITEA_TOKEN/BITBUCKET_SERVER_TOKEN/BITBUCKET_CLOUD_APP_PASSWORD/BITBUCKET_CLOUD_WORKSPACE_TOKEN/GERRIT_TOKEN environment variable.")
+	flags.StringP("token", "T", "", "The personal access token for the targeting platform. Can also be set using the GITHUB_TOKEN/GITLAB_TOKEN/GITEA_TOKEN/BITBUCKET_SERVER_TOKEN/BITBUCKET_CLOUD_APP_PASSWORD/BITBUCKET_CLOUD_WORKSPACE_TOKEN/GERRIT_TOKEN/AZURE_DEVOPS_TOKEN environment variable.")
 	flags.StringP("auth-type", "", "app-password", `The authentication type. Used only for Bitbucket cloud. Available values: app-password, workspace-token.`)
 	_ = cmd.RegisterFlagCompletionFunc("auth-type", func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 		return []string{"app-password", "workspace-token"}, cobra.ShellCompDirectiveNoFileComp
 	})
 
-	flags.StringSliceP("org", "O", nil, "The name of a GitHub organization. All repositories in that organization will be used.")
+	flags.StringSliceP("org", "O", nil, "The name of a GitHub organization. All repositories in that organization will be used. For Azure DevOps, the organization (or collection) that the projects and repositories are in.")
 	flags.StringSliceP("group", "G", nil, "The name of a GitLab organization. All repositories in that group will be used.")
 	flags.StringSliceP("user", "U", nil, "The name of a user. All repositories owned by that user will be used.")
-	flags.StringSliceP("repo", "R", nil, "The name, including owner of a GitHub repository in the format \"ownerName/repoName\".")
+	flags.StringSliceP("repo", "R", nil, "The name, including owner of a GitHub repository in the format \"ownerName/repoName\". For the git platform, the clone URL of the repository.")
+	flags.StringP("repo-file", "", "", `Path of a file with repositories to target, "-" means stdin. The file may be a newline separated list, a CSV file or a JSON array. Each repository uses the same format as --repo (--project for GitLab).`)
 	flags.StringP("repo-search", "", "", "Use a repository search to find repositories to target (GitHub only). Forks are NOT included by default, use `fork:true` to include them. See the GitHub documentation for full syntax: https://docs.github.com/en/search-github/searching-on-github/searching-for-repositories.")
 	flags.StringP("code-search", "", "", "Use a code search to find a set of repositories to target (GitHub only). Repeated results from a given repository will be ignored, forks are NOT included by default (use `fork:true` to include them). See the GitHub documentation for full syntax: https://docs.github.com/en/search-github/searching-on-github/searching-code.")
 	flags.StringSliceP("topic", "", nil, "The topic of a GitHub/GitLab/Gitea repository. All repositories having at least one matching topic are targeted.")
-	flags.StringSliceP("project", "P", nil, "The name, including owner of a GitLab project in the format \"ownerName/repoName\".")
+	flags.StringSliceP("project", "P", nil, "The name, including owner of a GitLab project in the format \"ownerName/repoName\". For Azure DevOps, the name of a project, all repositories in that project will be used.")
 	flags.BoolP("include-subgroups", "", false, "Include GitLab subgroups when using the --group flag.")
 	flags.BoolP("ssh-auth", "", false, `Use SSH cloning URL instead of HTTPS + token. This requires that a setup with ssh keys that have access to all repos and that the server is already in known_hosts.`)
 	flags.BoolP("skip-forks", "", false, `Skip repositories which are forks.`)
 
-	flags.StringP("platform", "p", "github", "The platform that is used. Available values: github, gitlab, gitea, bitbucket_server, bitbucket_cloud, gerrit. Note: bitbucket_cloud is in Beta")
+	configureProfiles(cmd)
+
+	flags.StringP("platform", "p", "github", "The platform that is used. Available values: github, gitlab, gitea, bitbucket_server, bitbucket_cloud, gerrit, azure_devops, local, git. Note: bitbucket_cloud is in Beta")
 	_ = cmd.RegisterFlagCompletionFunc("platform", func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
-		return []string{"github", "gitlab", "gitea", "bitbucket_server", "bitbucket_cloud"}, cobra.ShellCompDirectiveDefault
+		return []string{"github", "gitlab", "gitea", "bitbucket_server", "bitbucket_cloud", "gerrit", "azure_devops", "local", "git"}, cobra.ShellCompDirectiveDefault
 	})
 
 	// Autocompletion for organizations
@@ -112,7 +118,16 @@ func getVersionController(flag *flag.FlagSet, verifyFlags bool, readOnly bool) (
 		return OverrideVersionController, nil
 	}
 
+	if profileNames, _ := flag.GetStringSlice("profile"); len(profileNames) > 0 {
+		return createMultiplexClient(flag, profileNames, verifyFlags, readOnly)
+	}
+
 	platform, _ := flag.GetString("platform")
+
+	if err := addRepoFile(flag, platform); err != nil {
+		return nil, err
+	}
+
 	switch platform {
 	case "github":
 		return createGithubClient(flag, verifyFlags, readOnly)
@@ -126,6 +141,12 @@ func getVersionController(flag *flag.FlagSet, verifyFlags bool, readOnly bool) (
 		return createBitbucketCloudClient(flag, verifyFlags)
 	case "gerrit":
 		return createGerritClient(flag, verifyFlags)
+	case "azure_devops":
+		return createAzureDevOpsClient(flag, verifyFlags)
+	case "local":
+		return createLocalClient(flag)
+	case "git":
+		return createPlainGitClient(flag)
 	default:
 		return nil, fmt.Errorf("unknown platform: %s", platform)
 	}
@@ -429,6 +450,79 @@ func createGerritClient(flag *flag.FlagSet, verifyFlags bool) (multigitter.Versi
 	return vc, err
 }
 
+func createAzureDevOpsClient(flag *flag.FlagSet, verifyFlags bool) (multigitter.VersionController, error) {
+	baseURL, _ := flag.GetString("base-url")
+	orgs, _ := flag.GetStringSlice("org")
+	projects, _ := flag.GetStringSlice("project")
+	repos, _ := flag.GetStringSlice("repo")
+	username, _ := flag.GetString("username")
+	insecure, _ := flag.GetBool("insecure")
+	sshAuth, _ := flag.GetBool("ssh-auth")
+	skipForks, _ := flag.GetBool("skip-forks")
+
+	if len(orgs) != 1 {
+		return nil, errors.New("exactly one organization has to be set for azure devops")
+	}
+
+	token, err := getToken(flag)
+	if err != nil {
+		return nil, err
+	}
+
+	repoRefs := make([]azuredevops.RepositoryReference, len(repos))
+	for i := range repos {
+		repoRefs[i], err = azuredevops.ParseRepositoryReference(repos[i])
+		if err != nil {
+			return nil, err
+		}
+		if verifyFlags && slices.Contains(projects, repoRefs[i].Project) {
+			log.Warnf("Repository %s and project %s are both set. This is likely a mistake", repoRefs[i].String(), repoRefs[i].Project)
+		}
+	}
+
+	mergeTypes, err := getMergeTypes(flag)
+	if err != nil {
+		return nil, err
+	}
+
+	return azuredevops.New(azuredevops.Config{
+		BaseURL:             baseURL,
+		Organization:        orgs[0],
+		Username:            username,
+		Token:               token,
+		Insecure:            insecure,
+		SSHAuth:             sshAuth,
+		TransportMiddleware: http.NewLoggingRoundTripper,
+		RepoListing: azuredevops.RepositoryListing{
+			Projects:     projects,
+			Repositories: repoRefs,
+			SkipForks:    skipForks,
+		},
+		MergeTypes: mergeTypes,
+	})
+}
+
+func createLocalClient(flag *flag.FlagSet) (multigitter.VersionController, error) {
+	dir, _ := flag.GetString("base-url")
+	if dir == "" {
+		return nil, errors.New("no base-url set, it should be the directory containing the repositories")
+	}
+
+	repoRefs, _ := flag.GetStringSlice("repo")
+
+	mergeTypes, err := getMergeTypes(flag)
+	if err != nil {
+		return nil, err
+	}
+
+	return local.New(dir, repoRefs, mergeTypes)
+}
+
+func createPlainGitClient(flag *flag.FlagSet) (multigitter.VersionController, error) {
+	urls, _ := flag.GetStringSlice("repo")
+	return plaingit.New(urls)
+}
+
 // versionControllerCompletion is a helper function to allow for easier implementation of Cobra autocompletions that depend on a version controller
 func versionControllerCompletion(cmd *cobra.Command, flagName string, fn func(vc multigitter.VersionController, toComplete string) ([]string, error)) {
 	_ = cmd.RegisterFlagCompletionFunc(flagName, func(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
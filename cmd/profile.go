@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/lindell/multi-gitter/internal/multigitter/multiplex"
+)
+
+// profileFlags are the flags that are set per profile, these flags are never taken from the command when profiles are used
+var profileFlags = []string{
+	"platform", "base-url", "insecure", "username", "token", "auth-type",
+	"org", "group", "user", "repo", "project", "repo-file", "repo-search", "code-search", "topic",
+	"include-subgroups", "ssh-auth", "skip-forks",
+}
+
+func configureProfiles(cmd *cobra.Command) {
+	cmd.Flags().StringSliceP("profile", "", nil, `The name of a profile defined in the config file. Multiple profiles can be used to target several platforms at once.
+A profile can set any of the platform and repository listing flags, and "token-env" with the name of an environment variable containing the token.
+When profiles are used, the platform and repository listing flags of the command are ignored.`)
+}
+
+// readProfiles reads the profiles section of the config files
+func readProfiles(flag *pflag.FlagSet) (map[string]interface{}, error) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	v.SetConfigName("config")
+	v.AddConfigPath("$HOME/.multi-gitter")
+	if err := v.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			return nil, err
+		}
+	}
+
+	configFiles, _ := flag.GetStringArray("config")
+	for _, file := range configFiles {
+		v.SetConfigFile(file)
+		if err := v.MergeInConfig(); err != nil {
+			return nil, err
+		}
+	}
+
+	return v.GetStringMap("profiles"), nil
+}
+
+// createMultiplexClient creates one version controller per profile, combined into one version controller
+func createMultiplexClient(flag *pflag.FlagSet, profileNames []string, verifyFlags bool, readOnly bool) (multigitter.VersionController, error) {
+	profiles, err := readProfiles(flag)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not read profiles")
+	}
+
+	var stdinRepos []string
+	sources := make([]multiplex.Source, 0, len(profileNames))
+	for _, name := range profileNames {
+		// Keys are case insensitive in config files
+		profile, ok := profiles[strings.ToLower(name)].(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf(`the profile "%s" is not defined in any config file`, name)
+		}
+
+		profileFlagSet, err := newProfileFlagSet(flag, profile)
+		if err != nil {
+			return nil, errors.WithMessagef(err, `invalid profile "%s"`, name)
+		}
+
+		// Stdin can only be read once, the repositories read from it are therefore shared by all profiles
+		if repoFile, _ := profileFlagSet.GetString("repo-file"); repoFile == "-" {
+			if stdinRepos == nil {
+				if stdinRepos, err = readRepoFile(repoFile); err != nil {
+					return nil, errors.WithMessagef(err, `profile "%s"`, name)
+				}
+			}
+			platform, _ := profileFlagSet.GetString("platform")
+			if err := addRepos(profileFlagSet, platform, repoFile, stdinRepos); err != nil {
+				return nil, errors.WithMessagef(err, `profile "%s"`, name)
+			}
+			if err := profileFlagSet.Set("repo-file", ""); err != nil {
+				return nil, err
+			}
+		}
+
+		vc, err := getVersionController(profileFlagSet, verifyFlags, readOnly)
+		if err != nil {
+			return nil, errors.WithMessagef(err, `profile "%s"`, name)
+		}
+
+		sources = append(sources, multiplex.Source{
+			Name:              name,
+			VersionController: vc,
+		})
+	}
+
+	return multiplex.New(sources...)
+}
+
+// newProfileFlagSet creates the flags used to create the version controller of a profile. Flags that are not set
+// per profile (like fork and merge-type) are copied from the command
+func newProfileFlagSet(flag *pflag.FlagSet, profile map[string]interface{}) (*pflag.FlagSet, error) {
+	cmd := &cobra.Command{}
+	configurePlatform(cmd)
+	configureRunPlatform(cmd, true)
+	configureMergeType(cmd, false)
+	flags := cmd.Flags()
+
+	var copyErr error
+	flags.VisitAll(func(f *pflag.Flag) {
+		original := flag.Lookup(f.Name)
+		if copyErr != nil || original == nil || !original.Changed || f.Name == "profile" || slices.Contains(profileFlags, f.Name) {
+			return
+		}
+		if sliceValue, ok := original.Value.(pflag.SliceValue); ok {
+			copyErr = setFlagValue(flags, f.Name, sliceValue.GetSlice())
+		} else {
+			copyErr = flags.Set(f.Name, original.Value.String())
+		}
+	})
+	if copyErr != nil {
+		return nil, copyErr
+	}
+
+	for key, value := range profile {
+		if key == "token-env" {
+			envName, _ := value.(string)
+			token := os.Getenv(envName)
+			if token == "" {
+				return nil, errors.Errorf(`the environment variable "%s" is not set`, envName)
+			}
+			value = token
+			key = "token"
+		}
+
+		if !slices.Contains(profileFlags, key) {
+			return nil, errors.Errorf(`"%s" can't be set in a profile`, key)
+		}
+		if err := setFlagValue(flags, key, value); err != nil {
+			return nil, errors.WithMessagef(err, `could not set "%s"`, key)
+		}
+	}
+
+	return flags, nil
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProfileFlagSet(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+profiles:
+  Public:
+    platform: github
+    org: [my-org]
+  internal:
+    platform: gitlab
+    base-url: https://gitlab.example.com
+    group: [team]
+    token-env: TEST_INTERNAL_TOKEN
+`), 0600))
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.StringArray("config", []string{configPath}, "")
+	fs.StringSlice("org", nil, "")
+	fs.StringSlice("merge-type", nil, "")
+	fs.StringSlice("profile", nil, "")
+	require.NoError(t, fs.Set("org", "ignored-org"))
+	require.NoError(t, fs.Set("merge-type", "squash"))
+	require.NoError(t, fs.Set("profile", "public,internal"))
+
+	profiles, err := readProfiles(fs)
+	require.NoError(t, err)
+
+	public, err := newProfileFlagSet(fs, profiles["public"].(map[string]interface{}))
+	require.NoError(t, err)
+	orgs, _ := public.GetStringSlice("org")
+	assert.Equal(t, []string{"my-org"}, orgs)
+	mergeTypes, _ := public.GetStringSlice("merge-type")
+	assert.Equal(t, []string{"squash"}, mergeTypes)
+
+	_, err = newProfileFlagSet(fs, profiles["internal"].(map[string]interface{}))
+	assert.ErrorContains(t, err, `the environment variable "TEST_INTERNAL_TOKEN" is not set`)
+
+	t.Setenv("TEST_INTERNAL_TOKEN", "secret")
+	internal, err := newProfileFlagSet(fs, profiles["internal"].(map[string]interface{}))
+	require.NoError(t, err)
+	platform, _ := internal.GetString("platform")
+	assert.Equal(t, "gitlab", platform)
+	token, _ := internal.GetString("token")
+	assert.Equal(t, "secret", token)
+	orgs, _ = internal.GetStringSlice("org")
+	assert.Empty(t, orgs)
+
+	_, err = newProfileFlagSet(fs, map[string]interface{}{"branch": "test"})
+	assert.ErrorContains(t, err, `"branch" can't be set in a profile`)
+}
+
+func TestCreateMultiplexClientRepoFileStdin(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+profiles:
+  first:
+    platform: github
+    token: first-token
+    repo-file: "-"
+  second:
+    platform: github
+    base-url: https://github.example.com/api/v3/
+    token: second-token
+    repo-file: "-"
+`), 0600))
+
+	repoFileStdin = strings.NewReader("owner/repo1\nowner/repo2\n")
+	defer func() { repoFileStdin = os.Stdin }()
+
+	fs := PrintCmd().Flags()
+	require.NoError(t, fs.Set("config", configPath))
+
+	_, err := createMultiplexClient(fs, []string{"first", "second"}, false, true)
+	require.NoError(t, err)
+}
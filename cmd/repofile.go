@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	flag "github.com/spf13/pflag"
+
+	"github.com/lindell/multi-gitter/internal/scm/azuredevops"
+	"github.com/lindell/multi-gitter/internal/scm/bitbucketserver"
+	"github.com/lindell/multi-gitter/internal/scm/gitea"
+	"github.com/lindell/multi-gitter/internal/scm/github"
+	"github.com/lindell/multi-gitter/internal/scm/gitlab"
+)
+
+// repoFileKeys are the keys (JSON) or column names (CSV) that may contain the repository name, in priority order
+var repoFileKeys = []string{"repository", "repo", "project", "full_name", "namewithowner", "path_with_namespace", "name"}
+
+// repoFileStdin is where the repository list is read from when the repo-file flag is "-"
+var repoFileStdin io.Reader = os.Stdin
+
+// addRepoFile reads the repositories defined in the file of the repo-file flag, and adds them to the
+// flag used to list specific repositories on the platform
+func addRepoFile(flag *flag.FlagSet, platform string) error {
+	path, _ := flag.GetString("repo-file")
+	if path == "" {
+		return nil
+	}
+
+	repos, err := readRepoFile(path)
+	if err != nil {
+		return err
+	}
+	return addRepos(flag, platform, path, repos)
+}
+
+// readRepoFile reads the repositories defined in a repo-file, "-" means stdin
+func readRepoFile(path string) ([]string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(repoFileStdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read repo-file %s", path)
+	}
+
+	repos, err := parseRepoList(data)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "could not parse repo-file %s", path)
+	}
+	if len(repos) == 0 {
+		return nil, errors.Errorf("the repo-file %s does not contain any repositories", path)
+	}
+	return repos, nil
+}
+
+// addRepos adds the repositories read from the repo-file at path to the flag used to list specific repositories on the platform
+func addRepos(flag *flag.FlagSet, platform string, path string, repos []string) error {
+	parse, flagName := repoReferenceParser(platform)
+	for _, repo := range repos {
+		if err := parse(repo); err != nil {
+			return errors.WithMessagef(err, "invalid repository in repo-file %s", path)
+		}
+		if err := flag.Set(flagName, repo); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// repoReferenceParser returns a function validating a repository reference in the format of the platform,
+// and the name of the flag such references are set with
+func repoReferenceParser(platform string) (func(string) error, string) {
+	switch platform {
+	case "github":
+		return func(s string) error { _, err := github.ParseRepositoryReference(s); return err }, "repo"
+	case "gitlab":
+		return func(s string) error { _, err := gitlab.ParseProjectReference(s); return err }, "project"
+	case "gitea":
+		return func(s string) error { _, err := gitea.ParseRepositoryReference(s); return err }, "repo"
+	case "bitbucket_server":
+		return func(s string) error { _, err := bitbucketserver.ParseRepositoryReference(s); return err }, "repo"
+	case "azure_devops":
+		return func(s string) error { _, err := azuredevops.ParseRepositoryReference(s); return err }, "repo"
+	default:
+		return func(string) error { return nil }, "repo"
+	}
+}
+
+// parseRepoList parses a list of repositories. The list may be a JSON array of strings or objects,
+// a CSV file, or a newline separated list where empty lines and lines starting with # are ignored
+func parseRepoList(data []byte) ([]string, error) {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case len(trimmed) == 0:
+		return nil, nil
+	case trimmed[0] == '[':
+		return parseRepoListJSON(trimmed)
+	case bytes.ContainsRune(trimmed, ','):
+		return parseRepoListCSV(trimmed)
+	}
+
+	var repos []string
+	scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		repos = append(repos, line)
+	}
+	return repos, scanner.Err()
+}
+
+func parseRepoListJSON(data []byte) ([]string, error) {
+	var items []interface{}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+
+	repos := make([]string, 0, len(items))
+	for i, item := range items {
+		switch item := item.(type) {
+		case string:
+			repos = append(repos, item)
+		case map[string]interface{}:
+			repo, ok := repoFromObject(item)
+			if !ok {
+				return nil, errors.Errorf("item %d has none of the keys %s", i, strings.Join(repoFileKeys, ", "))
+			}
+			repos = append(repos, repo)
+		default:
+			return nil, errors.Errorf("item %d is neither a string nor an object", i)
+		}
+	}
+	return repos, nil
+}
+
+func repoFromObject(obj map[string]interface{}) (string, bool) {
+	lowerObj := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		lowerObj[strings.ToLower(k)] = v
+	}
+
+	for _, key := range repoFileKeys {
+		if val, ok := lowerObj[key].(string); ok && val != "" {
+			return val, true
+		}
+	}
+	return "", false
+}
+
+// parseRepoListCSV reads the repositories from a CSV file. If the first row is a header containing one of the
+// known column names, that column is used. Otherwise, the first column of every row is used
+func parseRepoListCSV(data []byte) ([]string, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	column := 0
+	if len(records) > 0 {
+		if i := repoColumn(records[0]); i >= 0 {
+			column = i
+			records = records[1:]
+		}
+	}
+
+	repos := make([]string, 0, len(records))
+	for i, record := range records {
+		if column >= len(record) {
+			return nil, fmt.Errorf("row %d has no column %d", i+1, column+1)
+		}
+		if repo := strings.TrimSpace(record[column]); repo != "" {
+			repos = append(repos, repo)
+		}
+	}
+	return repos, nil
+}
+
+func repoColumn(header []string) int {
+	for _, key := range repoFileKeys {
+		for i, name := range header {
+			if strings.ToLower(strings.TrimSpace(name)) == key {
+				return i
+			}
+		}
+	}
+	return -1
+}
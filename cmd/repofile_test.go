@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRepoList(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected []string
+		errorMsg string
+	}{
+		{
+			name:     "newline separated",
+			data:     "owner/repo1\n\n# a comment\n  owner/repo2  \n",
+			expected: []string{"owner/repo1", "owner/repo2"},
+		},
+		{
+			name:     "empty",
+			data:     "\n\n",
+			expected: nil,
+		},
+		{
+			name:     "json strings",
+			data:     `["owner/repo1", "owner/repo2"]`,
+			expected: []string{"owner/repo1", "owner/repo2"},
+		},
+		{
+			name:     "json objects",
+			data:     `[{"nameWithOwner": "owner/repo1", "stars": 3}, {"full_name": "owner/repo2"}]`,
+			expected: []string{"owner/repo1", "owner/repo2"},
+		},
+		{
+			name:     "json objects without a known key",
+			data:     `[{"stars": 3}]`,
+			errorMsg: "item 0 has none of the keys",
+		},
+		{
+			name:     "csv with header",
+			data:     "team,Repository\nplatform,owner/repo1\ninfra,\"owner/repo2\"\n",
+			expected: []string{"owner/repo1", "owner/repo2"},
+		},
+		{
+			name:     "csv without header",
+			data:     "owner/repo1,platform\nowner/repo2,infra\n",
+			expected: []string{"owner/repo1", "owner/repo2"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repos, err := parseRepoList([]byte(test.data))
+			if test.errorMsg != "" {
+				assert.ErrorContains(t, err, test.errorMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, repos)
+		})
+	}
+}
+
+func TestAddRepoFile(t *testing.T) {
+	newFlags := func(repoFile string) *pflag.FlagSet {
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		fs.String("repo-file", repoFile, "")
+		fs.StringSlice("repo", nil, "")
+		fs.StringSlice("project", nil, "")
+		return fs
+	}
+
+	path := filepath.Join(t.TempDir(), "repos.txt")
+	require.NoError(t, os.WriteFile(path, []byte("owner/repo1\ngroup/subgroup/repo2\n"), 0600))
+
+	fs := newFlags(path)
+	require.NoError(t, addRepoFile(fs, "gitlab"))
+	projects, _ := fs.GetStringSlice("project")
+	assert.Equal(t, []string{"owner/repo1", "group/subgroup/repo2"}, projects)
+
+	// Subgroups are not a valid GitHub reference
+	fs = newFlags(path)
+	assert.ErrorContains(t, addRepoFile(fs, "github"), "invalid repository in repo-file")
+
+	// Reading from stdin, together with the repo flag
+	repoFileStdin = strings.NewReader(`["owner/repo2"]`)
+	defer func() { repoFileStdin = os.Stdin }()
+	fs = newFlags("-")
+	require.NoError(t, fs.Set("repo", "owner/repo1"))
+	require.NoError(t, addRepoFile(fs, "github"))
+	repos, _ := fs.GetStringSlice("repo")
+	assert.Equal(t, []string{"owner/repo1", "owner/repo2"}, repos)
+}
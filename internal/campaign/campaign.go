@@ -0,0 +1,178 @@
+package campaign
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+)
+
+// ErrNotFound is returned when a campaign has not been recorded
+var ErrNotFound = errors.New("campaign not found")
+
+var nameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// Campaign is the recorded state of a campaign, the pull requests that was created or updated by runs with the same campaign name
+type Campaign struct {
+	Name     string `json:"name"`
+	Branch   string `json:"branch"`
+	Platform string `json:"platform"`
+	BaseURL  string `json:"base_url,omitempty"`
+
+	PullRequests []PullRequest `json:"pull_requests"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PullRequest is a recorded pull request
+type PullRequest struct {
+	// Repository is the full name of the repository the pull request targets
+	Repository string `json:"repository"`
+	// Name is the string representation of the pull request, for example "owner/repo #1"
+	Name       string    `json:"name"`
+	URL        string    `json:"url,omitempty"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// Record adds a pull request to the campaign, replacing any previously recorded pull request in the same repository
+func (c *Campaign) Record(repo scm.Repository, pr scm.PullRequest) {
+	recorded := PullRequest{
+		Repository: repo.FullName(),
+		Name:       pr.String(),
+		RecordedAt: time.Now(),
+	}
+	if urler, ok := pr.(interface{ URL() string }); ok {
+		recorded.URL = urler.URL()
+	}
+
+	for i := range c.PullRequests {
+		if c.PullRequests[i].Repository == recorded.Repository {
+			c.PullRequests[i] = recorded
+			return
+		}
+	}
+	c.PullRequests = append(c.PullRequests, recorded)
+	sort.Slice(c.PullRequests, func(i, j int) bool {
+		return c.PullRequests[i].Repository < c.PullRequests[j].Repository
+	})
+}
+
+// Contains checks if a pull request has been recorded as part of the campaign
+func (c Campaign) Contains(pr scm.PullRequest) bool {
+	for _, recorded := range c.PullRequests {
+		if recorded.Name == pr.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// Repositories returns the full names of all repositories with a recorded pull request
+func (c Campaign) Repositories() []string {
+	repos := make([]string, len(c.PullRequests))
+	for i, pr := range c.PullRequests {
+		repos[i] = pr.Repository
+	}
+	return repos
+}
+
+// ValidateName checks that a campaign name can be used
+func ValidateName(name string) error {
+	if !nameRegex.MatchString(name) {
+		return errors.Errorf(`invalid campaign name "%s", only letters, digits, ".", "_" and "-" are allowed`, name)
+	}
+	return nil
+}
+
+// Store saves campaigns as JSON files in a directory
+type Store struct {
+	Dir string
+}
+
+// DefaultDir returns the directory campaigns are stored in if nothing else is configured
+func DefaultDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.Wrap(err, "could not find the home directory")
+	}
+	return filepath.Join(home, ".multi-gitter", "campaigns"), nil
+}
+
+// Load reads a recorded campaign
+func (s Store) Load(name string) (Campaign, error) {
+	if err := ValidateName(name); err != nil {
+		return Campaign{}, err
+	}
+
+	data, err := os.ReadFile(s.path(name))
+	if os.IsNotExist(err) {
+		return Campaign{}, errors.WithMessagef(ErrNotFound, `"%s"`, name)
+	} else if err != nil {
+		return Campaign{}, errors.Wrapf(err, "could not read campaign %s", name)
+	}
+
+	var c Campaign
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Campaign{}, errors.Wrapf(err, "could not parse campaign %s", name)
+	}
+	return c, nil
+}
+
+// Save writes a campaign to the store
+func (s Store) Save(c Campaign) error {
+	if err := ValidateName(c.Name); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return errors.Wrap(err, "could not create campaign directory")
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, to never leave a partially written campaign
+	tmpPath := s.path(c.Name) + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return errors.Wrapf(err, "could not write campaign %s", c.Name)
+	}
+	return os.Rename(tmpPath, s.path(c.Name))
+}
+
+func (s Store) path(name string) string {
+	return filepath.Join(s.Dir, name+".json")
+}
+
+// Recorder records pull requests into a campaign, the campaign is saved after each recorded pull request
+type Recorder struct {
+	store    Store
+	lock     sync.Mutex
+	campaign Campaign
+}
+
+// NewRecorder creates a recorder for a campaign
+func NewRecorder(store Store, c Campaign) *Recorder {
+	return &Recorder{
+		store:    store,
+		campaign: c,
+	}
+}
+
+// RecordPullRequest records a pull request that was created or updated
+func (r *Recorder) RecordPullRequest(repo scm.Repository, pr scm.PullRequest) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.campaign.Record(repo, pr)
+	r.campaign.UpdatedAt = time.Now()
+	return r.store.Save(r.campaign)
+}
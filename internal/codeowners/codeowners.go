@@ -0,0 +1,240 @@
+// Package codeowners parses CODEOWNERS files and finds the owners of files
+package codeowners
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Locations are the paths, relative to the root of a repository, where a CODEOWNERS file is searched for.
+// The first file that exist is used
+var Locations = []string{
+	".github/CODEOWNERS",
+	".gitlab/CODEOWNERS",
+	".gitea/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+}
+
+// File is a parsed CODEOWNERS file
+type File struct {
+	sections []section
+}
+
+// section is a group of rules where the last matching rule decides the owners. Files without sections
+// (like on GitHub) has one section, while GitLab allows several sections where the owners of all sections are used
+type section struct {
+	rules []rule
+}
+
+type rule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// Owners are the owners of some files
+type Owners struct {
+	// Users are the usernames of the owners, without the leading @
+	Users []string
+	// Teams are the team names of the owners, without the organization
+	Teams []string
+}
+
+// Find finds and parses the CODEOWNERS file of the repository in the directory.
+// If the repository does not have a CODEOWNERS file, nil is returned
+func Find(dir string) (*File, error) {
+	for _, location := range Locations {
+		f, err := os.Open(filepath.Join(dir, filepath.FromSlash(location)))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		file, err := Parse(f)
+		if err != nil {
+			return nil, errors.WithMessage(err, location)
+		}
+		return file, nil
+	}
+	return nil, nil
+}
+
+// Parse parses a CODEOWNERS file
+func Parse(r io.Reader) (*File, error) {
+	file := &File{sections: []section{{}}}
+	var sectionOwners []string
+
+	scanner := bufio.NewScanner(r)
+	for lineNr := 1; scanner.Scan(); lineNr++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// GitLab sections, for example "[Backend] @backend-team" or "^[Optional section]"
+		if header := strings.TrimPrefix(line, "^"); strings.HasPrefix(header, "[") {
+			end := strings.Index(header, "]")
+			if end < 0 {
+				return nil, errors.Errorf("line %d: unterminated section", lineNr)
+			}
+			file.sections = append(file.sections, section{})
+			sectionOwners = ownerFields(header[end+1:])
+			continue
+		}
+
+		fields := splitFields(line)
+		if strings.HasPrefix(fields[0], "!") {
+			// Negated patterns are not supported in CODEOWNERS files, and are ignored
+			continue
+		}
+		pattern, err := patternToRegexp(fields[0])
+		if err != nil {
+			return nil, errors.WithMessagef(err, "line %d", lineNr)
+		}
+		owners := ownerFields(strings.Join(fields[1:], " "))
+		if len(owners) == 0 {
+			owners = sectionOwners
+		}
+
+		current := &file.sections[len(file.sections)-1]
+		current.rules = append(current.rules, rule{
+			pattern: pattern,
+			owners:  owners,
+		})
+	}
+
+	return file, scanner.Err()
+}
+
+// ownerFields parses the owners in a string, ignoring anything after a comment
+func ownerFields(str string) []string {
+	var owners []string
+	for _, field := range strings.Fields(str) {
+		if strings.HasPrefix(field, "#") {
+			break
+		}
+		owners = append(owners, field)
+	}
+	return owners
+}
+
+// splitFields splits a line by whitespace, where whitespace escaped with \ is kept in the pattern
+func splitFields(line string) []string {
+	var fields []string
+	var current strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '\\' && i+1 < len(line) && (line[i+1] == ' ' || line[i+1] == '\t'):
+			current.WriteByte(line[i+1])
+			i++
+		case c == ' ' || c == '\t':
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteByte(c)
+		}
+	}
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+	return fields
+}
+
+// Owners returns the owners of all the files
+func (f *File) Owners(paths []string) Owners {
+	var owners Owners
+	seen := map[string]bool{}
+	for _, path := range paths {
+		for _, owner := range f.fileOwners(path) {
+			if seen[owner] {
+				continue
+			}
+			seen[owner] = true
+
+			name := strings.TrimPrefix(owner, "@")
+			switch {
+			case !strings.HasPrefix(owner, "@"):
+				// Owners defined by email can't be requested as reviewers
+			case strings.Contains(name, "/"):
+				owners.Teams = append(owners.Teams, name[strings.LastIndex(name, "/")+1:])
+			default:
+				owners.Users = append(owners.Users, name)
+			}
+		}
+	}
+	return owners
+}
+
+// fileOwners returns the owners of a single file
+func (f *File) fileOwners(path string) []string {
+	path = strings.TrimPrefix(filepath.ToSlash(path), "/")
+
+	var owners []string
+	for _, section := range f.sections {
+		// The last matching rule takes precedence
+		for i := len(section.rules) - 1; i >= 0; i-- {
+			if section.rules[i].pattern.MatchString(path) {
+				owners = append(owners, section.rules[i].owners...)
+				break
+			}
+		}
+	}
+	return owners
+}
+
+// patternToRegexp converts a pattern, that follows the rules of gitignore files, to a regular expression
+func patternToRegexp(pattern string) (*regexp.Regexp, error) {
+	// Patterns with a slash at the beginning or in the middle are relative to the root
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	directory := strings.HasSuffix(pattern, "/")
+	pattern = strings.Trim(pattern, "/")
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("(?:^|/)")
+	}
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '\\' && i+1 < len(pattern):
+			b.WriteString(regexp.QuoteMeta(string(pattern[i+1])))
+			i++
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	switch {
+	case directory:
+		// Only files within the directory
+		b.WriteString("/")
+	case strings.HasSuffix(pattern, "/*") && !strings.HasSuffix(pattern, "**"):
+		// Only files directly in the directory, not in subdirectories
+		b.WriteString("$")
+	default:
+		// The pattern may match either the file itself or a directory containing it
+		b.WriteString("(?:$|/)")
+	}
+	return regexp.Compile(b.String())
+}
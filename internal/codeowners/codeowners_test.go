@@ -0,0 +1,90 @@
+package codeowners
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const githubCodeOwners = `# Default owners
+*       @global-owner
+
+*.js    @js-owner # Comment after the owners
+**/logs @logs-owner
+/build/logs/ @doctocat
+docs/*  docs@example.com @docs-owner
+apps/   @octocat
+/scripts/ @my-org/scripts-team
+/assets\ dir/ @assets-owner
+!ignored.txt @nobody
+`
+
+func TestOwners(t *testing.T) {
+	file, err := Parse(strings.NewReader(githubCodeOwners))
+	require.NoError(t, err)
+
+	tests := []struct {
+		paths []string
+		want  Owners
+	}{
+		{paths: []string{"README.md"}, want: Owners{Users: []string{"global-owner"}}},
+		{paths: []string{"src/index.js"}, want: Owners{Users: []string{"js-owner"}}},
+		{paths: []string{"build/logs/output.txt"}, want: Owners{Users: []string{"doctocat"}}},
+		{paths: []string{"other/build/logs/output.txt"}, want: Owners{Users: []string{"logs-owner"}}},
+		{paths: []string{"docs/getting-started.md"}, want: Owners{Users: []string{"docs-owner"}}},
+		{paths: []string{"docs/build-app/troubleshooting.md"}, want: Owners{Users: []string{"global-owner"}}},
+		{paths: []string{"apps/main.go", "nested/apps/main.go"}, want: Owners{Users: []string{"octocat"}}},
+		{paths: []string{"scripts/run.sh"}, want: Owners{Teams: []string{"scripts-team"}}},
+		{paths: []string{"other/scripts/run.sh"}, want: Owners{Users: []string{"global-owner"}}},
+		{paths: []string{"assets dir/logo.png"}, want: Owners{Users: []string{"assets-owner"}}},
+		{
+			paths: []string{"README.md", "src/index.js", "scripts/run.sh", "src/other.js"},
+			want:  Owners{Users: []string{"global-owner", "js-owner"}, Teams: []string{"scripts-team"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.paths, ","), func(t *testing.T) {
+			assert.Equal(t, tt.want, file.Owners(tt.paths))
+		})
+	}
+}
+
+const gitlabCodeOwners = `* @default-owner
+
+[Backend] @backend-team-lead
+internal/
+/cmd/ @cli-owner
+
+^[Documentation][2] @tech-writer
+*.md
+`
+
+func TestOwnersSections(t *testing.T) {
+	file, err := Parse(strings.NewReader(gitlabCodeOwners))
+	require.NoError(t, err)
+
+	assert.Equal(t, Owners{Users: []string{"default-owner"}}, file.Owners([]string{"main.go"}))
+	assert.Equal(t, Owners{Users: []string{"default-owner", "backend-team-lead"}}, file.Owners([]string{"internal/scm/scm.go"}))
+	assert.Equal(t, Owners{Users: []string{"default-owner", "cli-owner", "tech-writer"}}, file.Owners([]string{"cmd/README.md"}))
+}
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+
+	file, err := Find(dir)
+	require.NoError(t, err)
+	assert.Nil(t, file)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "CODEOWNERS"), []byte("* @root-owner"), 0600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".github"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".github", "CODEOWNERS"), []byte("* @github-owner"), 0600))
+
+	file, err = Find(dir)
+	require.NoError(t, err)
+	require.NotNil(t, file)
+	assert.Equal(t, Owners{Users: []string{"github-owner"}}, file.Owners([]string{"file.txt"}))
+}
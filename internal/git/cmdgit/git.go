@@ -160,6 +160,70 @@ func (g *Git) Push(ctx context.Context, remoteName, remoteReference string, forc
 	return err
 }
 
+// UpdateBranch fetches a branch from the remote and merges it into the current branch, or rebases the current branch onto it
+func (g *Git) UpdateBranch(ctx context.Context, remoteName, branchName string, rebase bool, commitAuthor *git.CommitAuthor) error {
+	cmd := exec.CommandContext(ctx, "git", "fetch", remoteName, branchName)
+	if _, err := g.run(cmd); err != nil {
+		return err
+	}
+
+	operation := "merge"
+	cmd = exec.Command("git", "merge", "--no-edit", "FETCH_HEAD")
+	if rebase {
+		operation = "rebase"
+		cmd = exec.Command("git", "rebase", "FETCH_HEAD")
+	}
+
+	if commitAuthor != nil {
+		cmd.Env = append(cmd.Env,
+			"GIT_AUTHOR_NAME="+commitAuthor.Name,
+			"GIT_AUTHOR_EMAIL="+commitAuthor.Email,
+			"GIT_COMMITTER_NAME="+commitAuthor.Name,
+			"GIT_COMMITTER_EMAIL="+commitAuthor.Email,
+		)
+	}
+
+	_, err := g.run(cmd)
+	if err == nil {
+		return nil
+	}
+
+	// Files with unresolved conflicts are listed as unmerged
+	cmd = exec.Command("git", "diff", "--name-only", "--diff-filter=U")
+	conflicting, diffErr := g.run(cmd)
+	if diffErr != nil || strings.TrimSpace(conflicting) == "" {
+		return err
+	}
+
+	cmd = exec.Command("git", operation, "--abort")
+	if _, err := g.run(cmd); err != nil {
+		return errors.WithMessagef(err, "could not abort the %s", operation)
+	}
+
+	log.Debugf("Conflicting files: %s", strings.Join(strings.Fields(conflicting), ", "))
+	return git.ErrConflict
+}
+
+// Diff fetches only the base and head references of a repository into an empty repository,
+// and returns the changes made on the head since it diverged from the base
+func (g *Git) Diff(ctx context.Context, url, baseRef, headRef string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "init", "--quiet")
+	if _, err := g.run(cmd); err != nil {
+		return "", err
+	}
+
+	cmd = exec.CommandContext(ctx, "git", "fetch", "--no-tags", url,
+		baseRef+":refs/multi-gitter/base",
+		headRef+":refs/multi-gitter/head",
+	)
+	if _, err := g.run(cmd); err != nil {
+		return "", err
+	}
+
+	cmd = exec.CommandContext(ctx, "git", "diff", "refs/multi-gitter/base...refs/multi-gitter/head")
+	return g.run(cmd)
+}
+
 // AddRemote adds a new remote
 func (g *Git) AddRemote(name, url string) error {
 	cmd := exec.Command("git", "remote", "add", name, url)
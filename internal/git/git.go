@@ -1,5 +1,7 @@
 package git
 
+import "errors"
+
 // Config is configuration for any git implementation
 type Config struct {
 	// Absolute path to the directory
@@ -7,3 +9,6 @@ type Config struct {
 	// The fetch depth used when cloning, if set to 0, the entire history will be used
 	FetchDepth int
 }
+
+// ErrConflict is returned when a branch can't be updated with another branch because they contain conflicting changes
+var ErrConflict = errors.New("conflicting changes")
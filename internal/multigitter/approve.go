@@ -0,0 +1,58 @@
+package multigitter
+
+import (
+	"context"
+	"io"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Approver approves pull requests
+type Approver struct {
+	VersionController VersionController
+
+	// Output is where the result of approving the pull requests is written
+	Output io.Writer
+
+	FeatureBranch string
+
+	PullRequestSelection
+
+	// DryRun lists the pull requests that would be approved, without approving them
+	DryRun bool
+}
+
+// Approve approves each open pull request. Pull requests created by the authenticated user are skipped,
+// and a pull request that fails to be approved does not stop the others from being approved
+func (s Approver) Approve(ctx context.Context) error {
+	approver, ok := s.VersionController.(VersionControllerApprovePullRequest)
+	if !ok {
+		return errors.New("the platform does not support approving pull requests")
+	}
+
+	return pullRequestAction{
+		dryRun:        s.DryRun,
+		dryRunTitle:   "Pull requests that would be approved",
+		dryRunMessage: "Would approve %d pull requests",
+		message:       "Approving %d pull requests",
+		do: func(ctx context.Context, pr scm.PullRequest, log log.FieldLogger) (pullRequestOutcome, string) {
+			log.Infof("Approving")
+			err := approver.ApprovePullRequest(ctx, pr)
+			switch {
+			case errors.Is(err, scm.ErrOwnPullRequest):
+				log.Infof("Skipping, the pull request was created by the authenticated user")
+				return outcomeSkipped, "created by the authenticated user"
+			case err != nil:
+				log.Errorf("Error occurred while approving: %s", err.Error())
+				return outcomeFailed, err.Error()
+			}
+			return outcomeDone, ""
+		},
+		doneTitle:     "Approved pull requests",
+		skippedTitle:  "Skipped pull requests",
+		failedTitle:   "Pull requests that could not be approved",
+		failedMessage: "%d of %d pull requests could not be approved",
+	}.run(ctx, s.VersionController, s.Output, s.FeatureBranch, s.PullRequestSelection)
+}
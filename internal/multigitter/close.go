@@ -2,8 +2,11 @@ package multigitter
 
 import (
 	"context"
+	"fmt"
+	"io"
 
 	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -11,32 +14,76 @@ import (
 type Closer struct {
 	VersionController VersionController
 
+	// Output is where the result of closing the pull requests is written
+	Output io.Writer
+
 	FeatureBranch string
+
+	PullRequestSelection
+
+	// Comment is, if set, posted on each pull request before it's closed
+	Comment string
+	// DeleteBranch deletes the branch of each pull request after it has been closed
+	DeleteBranch bool
+
+	// DryRun lists the pull requests that would be closed, without closing them
+	DryRun bool
 }
 
-// Close closes pull requests
+// Close closes pull requests. A pull request that fails to close does not stop the others from being closed
 func (s Closer) Close(ctx context.Context) error {
-	prs, err := s.VersionController.GetPullRequests(ctx, s.FeatureBranch)
-	if err != nil {
-		return err
+	if _, canComment := s.VersionController.(VersionControllerCommentPullRequest); s.Comment != "" && !canComment {
+		return errors.New("the platform does not support commenting on pull requests")
 	}
 
-	openPRs := make([]scm.PullRequest, 0, len(prs))
-	for _, pr := range prs {
-		if pr.Status() != scm.PullRequestStatusClosed && pr.Status() != scm.PullRequestStatusMerged {
-			openPRs = append(openPRs, pr)
+	return pullRequestAction{
+		dryRun:        s.DryRun,
+		dryRunTitle:   "Pull requests that would be closed",
+		dryRunMessage: "Would close %d pull requests",
+		message:       "Closing %d pull requests",
+		do: func(ctx context.Context, pr scm.PullRequest, log log.FieldLogger) (pullRequestOutcome, string) {
+			log.Infof("Closing")
+			if err := s.closePullRequest(ctx, pr); err != nil {
+				log.Errorf("Error occurred while closing: %s", err.Error())
+				return outcomeFailed, err.Error()
+			}
+			return outcomeDone, ""
+		},
+		doneTitle:     "Closed pull requests",
+		failedTitle:   "Pull requests that failed to close",
+		failedMessage: "%d of %d pull requests could not be closed",
+	}.run(ctx, s.VersionController, s.Output, s.FeatureBranch, s.PullRequestSelection)
+}
+
+// closePullRequest comments on, closes and deletes the branch of a single pull request
+func (s Closer) closePullRequest(ctx context.Context, pr scm.PullRequest) error {
+	if s.Comment != "" {
+		err := s.VersionController.(VersionControllerCommentPullRequest).CommentPullRequest(ctx, pr, s.Comment)
+		if err != nil {
+			return errors.WithMessage(err, "could not comment")
 		}
 	}
 
-	log.Infof("Closing %d pull requests", len(openPRs))
+	if err := s.VersionController.ClosePullRequest(ctx, pr); err != nil {
+		return err
+	}
 
-	for _, pr := range openPRs {
-		log.WithField("pr", pr.String()).Infof("Closing")
-		err := s.VersionController.ClosePullRequest(ctx, pr)
-		if err != nil {
-			return err
-		}
+	// Some platforms, like Gerrit, have no branch to delete
+	branchDeleter, ok := s.VersionController.(VersionControllerDeletePullRequestBranch)
+	if !s.DeleteBranch || !ok {
+		return nil
 	}
+	return errors.WithMessage(branchDeleter.DeletePullRequestBranch(ctx, pr), "closed, but the branch could not be deleted")
+}
 
-	return nil
+// printPullRequestList prints a title followed by one pull request per line
+func printPullRequestList(w io.Writer, title string, prs []scm.PullRequest) {
+	if len(prs) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "%s:\n", title)
+	for _, pr := range prs {
+		fmt.Fprintf(w, "  %s\n", pullRequestResult{pr: pr})
+	}
 }
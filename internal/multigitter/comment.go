@@ -0,0 +1,155 @@
+package multigitter
+
+import (
+	"context"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Commenter comments on pull requests
+type Commenter struct {
+	VersionController VersionController
+
+	// Output is where the result of commenting on the pull requests is written
+	Output io.Writer
+
+	FeatureBranch string
+
+	PullRequestSelection
+
+	// Body is a template that is executed with the commentData of each pull request to create its comment
+	Body string
+	// AllowDuplicates posts the comment even on pull requests that already have an identical comment
+	AllowDuplicates bool
+
+	// DryRun lists the pull requests that would be commented on, without commenting
+	DryRun bool
+}
+
+// commentData is the data that is available in the template of a comment
+type commentData struct {
+	PullRequest string // The name of the pull request, like "owner/repo #1"
+	Repository  string // The full name of the repository, like "owner/repo"
+	Number      int
+	Title       string
+	Author      string
+	URL         string
+	Branch      string
+	Status      string
+	Labels      []string
+	CreatedAt   time.Time
+}
+
+func newCommentData(pr scm.PullRequest, branch string) commentData {
+	details := scm.GetPullRequestDetails(pr)
+
+	data := commentData{
+		PullRequest: pr.String(),
+		Number:      details.Number,
+		Title:       details.Title,
+		Author:      details.Author,
+		Branch:      branch,
+		Status:      pr.Status().String(),
+		Labels:      details.Labels,
+		CreatedAt:   details.CreatedAt,
+	}
+	if p, ok := pr.(scm.PullRequestWithRepository); ok {
+		data.Repository = p.RepositoryName()
+	}
+	if urler, hasURL := pr.(urler); hasURL {
+		data.URL = urler.URL()
+	}
+	return data
+}
+
+// Comment posts a comment on each open pull request.
+// Unless AllowDuplicates is set, pull requests that already have the same comment are skipped, which makes it safe to run again
+func (s Commenter) Comment(ctx context.Context) error {
+	commenter, ok := s.VersionController.(VersionControllerCommentPullRequest)
+	if !ok {
+		return errors.New("the platform does not support commenting on pull requests")
+	}
+
+	var commentLister VersionControllerPullRequestComments
+	if !s.AllowDuplicates {
+		commentLister, ok = s.VersionController.(VersionControllerPullRequestComments)
+		if !ok {
+			return errors.New("the platform does not support listing the comments of pull requests, so duplicates can't be detected")
+		}
+	}
+
+	if strings.TrimSpace(s.Body) == "" {
+		return errors.New("the comment can't be empty")
+	}
+	tmpl, err := template.New("comment").Option("missingkey=error").Parse(s.Body)
+	if err != nil {
+		return errors.WithMessage(err, "could not parse the comment template")
+	}
+
+	action := pullRequestAction{
+		message: "Commenting on %d pull requests",
+		do: func(ctx context.Context, pr scm.PullRequest, log log.FieldLogger) (pullRequestOutcome, string) {
+			var comment strings.Builder
+			if err := tmpl.Execute(&comment, newCommentData(pr, s.FeatureBranch)); err != nil {
+				log.Errorf("Could not create the comment: %s", err.Error())
+				return outcomeFailed, err.Error()
+			}
+
+			if commentLister != nil {
+				existing, err := commentLister.PullRequestComments(ctx, pr)
+				if err != nil {
+					log.Errorf("Could not get the comments: %s", err.Error())
+					return outcomeFailed, "could not get the comments: " + err.Error()
+				}
+				if hasComment(existing, comment.String()) {
+					log.Infof("Skipping, the comment has already been posted")
+					return outcomeSkipped, "already commented"
+				}
+			}
+
+			if s.DryRun {
+				log.Infof("Would comment:\n%s", comment.String())
+				return outcomeDone, ""
+			}
+
+			log.Infof("Commenting")
+			if err := commenter.CommentPullRequest(ctx, pr, comment.String()); err != nil {
+				log.Errorf("Error occurred while commenting: %s", err.Error())
+				return outcomeFailed, err.Error()
+			}
+			return outcomeDone, ""
+		},
+		doneTitle:     "Commented pull requests",
+		skippedTitle:  "Skipped pull requests",
+		failedTitle:   "Pull requests that could not be commented on",
+		failedMessage: "%d of %d pull requests could not be commented on",
+	}
+	// Existing comments are checked in dry runs as well, so the action is done without commenting
+	if s.DryRun {
+		action.message = "Checking %d pull requests"
+		action.doneTitle = "Pull requests that would be commented on"
+	}
+
+	return action.run(ctx, s.VersionController, s.Output, s.FeatureBranch, s.PullRequestSelection)
+}
+
+// hasComment checks if any of the comments is the same as the new comment, ignoring surrounding whitespace and line endings
+func hasComment(comments []string, comment string) bool {
+	normalize := func(str string) string {
+		return strings.TrimSpace(strings.ReplaceAll(str, "\r\n", "\n"))
+	}
+
+	comment = normalize(comment)
+	for _, c := range comments {
+		if normalize(c) == comment {
+			return true
+		}
+	}
+	return false
+}
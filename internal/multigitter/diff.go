@@ -0,0 +1,210 @@
+package multigitter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Differ shows the changes of pull requests
+type Differ struct {
+	VersionController VersionController
+
+	// Output is where the diffs are written
+	Output io.Writer
+
+	FeatureBranch string
+
+	PullRequestSelection
+
+	// GroupIdentical prints pull requests with identical diffs together, with the diff only printed once
+	GroupIdentical bool
+	// OnlyDeviating only prints the pull requests with a diff that differs from the most common one
+	OnlyDeviating bool
+
+	CloneDir  string
+	CreateGit func(dir string) Git
+}
+
+// pullRequestDiff is the diff of a pull request
+type pullRequestDiff struct {
+	pr   scm.PullRequest
+	diff string
+}
+
+// diffGroup is a number of pull requests with identical diffs
+type diffGroup struct {
+	diffs []pullRequestDiff
+}
+
+// Diff fetches the diff of each open pull request and prints them
+func (s Differ) Diff(ctx context.Context) error {
+	repoFinder := &repositoryFinder{vc: s.VersionController}
+
+	var diffs []pullRequestDiff
+	return pullRequestAction{
+		message: "Fetching the diffs of %d pull requests",
+		do: func(ctx context.Context, pr scm.PullRequest, log log.FieldLogger) (pullRequestOutcome, string) {
+			log.Infof("Fetching the diff")
+			diff, err := s.pullRequestDiff(ctx, pr, repoFinder)
+			if err != nil {
+				log.Errorf("Error occurred while fetching the diff: %s", err.Error())
+				return outcomeFailed, err.Error()
+			}
+			diffs = append(diffs, pullRequestDiff{pr: pr, diff: diff})
+			return outcomeDone, ""
+		},
+		failedTitle: "Pull requests where the diff could not be fetched",
+		print: func(w io.Writer, sections []resultSection) {
+			s.printDiffs(w, diffs, sections[outcomeFailed])
+		},
+		failedMessage: "the diff of %d of %d pull requests could not be fetched",
+	}.run(ctx, s.VersionController, s.Output, s.FeatureBranch, s.PullRequestSelection)
+}
+
+// printDiffs prints the diffs, grouped if set, followed by the pull requests where the diff could not be fetched
+func (s Differ) printDiffs(w io.Writer, diffs []pullRequestDiff, failed resultSection) {
+	slices.SortFunc(diffs, func(a, b pullRequestDiff) int {
+		return strings.Compare(a.pr.String(), b.pr.String())
+	})
+
+	groups := make([]diffGroup, 0, len(diffs))
+	if s.GroupIdentical || s.OnlyDeviating {
+		groups = groupDiffs(diffs)
+	} else {
+		for _, diff := range diffs {
+			groups = append(groups, diffGroup{diffs: []pullRequestDiff{diff}})
+		}
+	}
+
+	if s.OnlyDeviating && len(groups) > 0 {
+		log.Infof("%d of %d pull requests have the most common diff", len(groups[0].diffs), len(diffs))
+		groups = groups[1:]
+		if !s.GroupIdentical {
+			var deviating []diffGroup
+			for _, group := range groups {
+				for _, diff := range group.diffs {
+					deviating = append(deviating, diffGroup{diffs: []pullRequestDiff{diff}})
+				}
+			}
+			slices.SortFunc(deviating, func(a, b diffGroup) int {
+				return strings.Compare(a.diffs[0].pr.String(), b.diffs[0].pr.String())
+			})
+			groups = deviating
+		}
+	}
+
+	for i, group := range groups {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		printDiffGroup(w, group)
+	}
+	if len(failed.results) > 0 && len(groups) > 0 {
+		fmt.Fprintln(w)
+	}
+	printResults(w, failed)
+}
+
+// pullRequestDiff fetches the head and the base branch of a pull request from the repository it targets, and compares them
+func (s Differ) pullRequestDiff(ctx context.Context, pr scm.PullRequest, repoFinder *repositoryFinder) (string, error) {
+	if s.CreateGit == nil {
+		return "", errors.New("no git implementation to fetch the diff with")
+	}
+
+	repo, err := repoFinder.find(ctx, pr)
+	if err != nil {
+		return "", err
+	}
+
+	details := scm.GetPullRequestDetails(pr)
+	baseBranch := details.BaseBranch
+	if baseBranch == "" {
+		baseBranch = repo.DefaultBranch()
+	}
+	// Without a reference to the head in the base repository, the pull request has to be made from a branch in it
+	headRef := details.HeadRef
+	if headRef == "" {
+		headRef = s.FeatureBranch
+	}
+
+	tmpDir, err := createTempDir(s.CloneDir)
+	defer os.RemoveAll(tmpDir)
+	if err != nil {
+		return "", err
+	}
+
+	differ, ok := s.CreateGit(tmpDir).(GitDiffer)
+	if !ok {
+		return "", errors.New("the git implementation can't show diffs")
+	}
+
+	diff, err := differ.Diff(ctx, repo.CloneURL(), baseBranch, headRef)
+	if err != nil {
+		return "", errors.WithMessage(err, "could not fetch the branches")
+	}
+	return diff, nil
+}
+
+// groupDiffs groups pull requests with identical diffs, the largest group first
+func groupDiffs(diffs []pullRequestDiff) []diffGroup {
+	var groups []diffGroup
+	index := map[string]int{}
+	for _, diff := range diffs {
+		key := normalizeDiff(diff.diff)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, diffGroup{})
+		}
+		groups[i].diffs = append(groups[i].diffs, diff)
+	}
+
+	// The groups are created in the order of the pull requests, which makes the order of equally large groups stable
+	slices.SortStableFunc(groups, func(a, b diffGroup) int {
+		return len(b.diffs) - len(a.diffs)
+	})
+	return groups
+}
+
+var (
+	indexLineRegex = regexp.MustCompile(`(?m)^index [0-9a-f]+\.\.[0-9a-f]+.*\n`)
+	hunkLineRegex  = regexp.MustCompile(`(?m)^@@ -\d+(,\d+)? \+\d+(,\d+)? @@`)
+)
+
+// normalizeDiff removes the parts of a diff that differ between repositories even if the changes are the same,
+// like the hashes of the files and the line numbers of the changes
+func normalizeDiff(diff string) string {
+	diff = indexLineRegex.ReplaceAllString(diff, "")
+	return hunkLineRegex.ReplaceAllString(diff, "@@")
+}
+
+func printDiffGroup(w io.Writer, group diffGroup) {
+	if len(group.diffs) == 1 {
+		fmt.Fprintf(w, "%s:\n", group.diffs[0].pr.String())
+	} else {
+		fmt.Fprintf(w, "%d pull requests with the same diff:\n", len(group.diffs))
+		for _, diff := range group.diffs {
+			fmt.Fprintf(w, "  %s\n", diff.pr.String())
+		}
+	}
+
+	diff := group.diffs[0].diff
+	if diff == "" {
+		fmt.Fprintln(w, "No changes")
+		return
+	}
+	fmt.Fprint(w, diff)
+	if !strings.HasSuffix(diff, "\n") {
+		fmt.Fprintln(w)
+	}
+}
@@ -0,0 +1,120 @@
+package multigitter
+
+import (
+	"context"
+	"io"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Editor edits the title, body and other metadata of pull requests, without changing their commits
+type Editor struct {
+	VersionController VersionController
+
+	// Output is where the result of editing the pull requests is written
+	Output io.Writer
+
+	FeatureBranch string
+
+	PullRequestSelection
+
+	// The fields below are the changes that are made, fields that are nil are left unchanged
+
+	Title         *string
+	Body          *string
+	Draft         *bool
+	Reviewers     []string
+	TeamReviewers []string
+	Assignees     []string
+	Labels        []string
+	// AutoMerge enables auto-merge, it can't be used to disable it
+	AutoMerge bool
+
+	// DryRun lists the pull requests that would be edited, without editing them
+	DryRun bool
+}
+
+// Edit edits each open pull request. A pull request that fails to be edited does not stop the others from being edited
+func (s Editor) Edit(ctx context.Context) error {
+	if !editsPullRequests(s.VersionController) {
+		return errEditNotSupported
+	}
+
+	repoFinder := &repositoryFinder{vc: s.VersionController}
+
+	return pullRequestAction{
+		dryRun:        s.DryRun,
+		dryRunTitle:   "Pull requests that would be edited",
+		dryRunMessage: "Would edit %d pull requests",
+		message:       "Editing %d pull requests",
+		do: func(ctx context.Context, pr scm.PullRequest, log log.FieldLogger) (pullRequestOutcome, string) {
+			log.Infof("Editing")
+			if err := s.editPullRequest(ctx, pr, repoFinder); err != nil {
+				log.Errorf("Error occurred while editing: %s", err.Error())
+				return outcomeFailed, err.Error()
+			}
+			return outcomeDone, ""
+		},
+		doneTitle:     "Edited pull requests",
+		failedTitle:   "Pull requests that could not be edited",
+		failedMessage: "%d of %d pull requests could not be edited",
+	}.run(ctx, s.VersionController, s.Output, s.FeatureBranch, s.PullRequestSelection)
+}
+
+var errEditNotSupported = errors.New("the platform does not support editing pull requests")
+
+func editsPullRequests(vc VersionController) bool {
+	editor, ok := vc.(VersionControllerEditPullRequests)
+	return !ok || editor.EditsPullRequests()
+}
+
+// editPullRequest updates a single pull request, the current values are used for everything that should not be changed
+func (s Editor) editPullRequest(ctx context.Context, pr scm.PullRequest, repoFinder *repositoryFinder) error {
+	details := scm.GetPullRequestDetails(pr)
+	if s.Title == nil && details.Title == "" {
+		return errors.New("the current title of the pull request is not known")
+	}
+
+	updatedPR := scm.NewPullRequest{
+		Title:         details.Title,
+		Body:          details.Body,
+		Head:          s.FeatureBranch,
+		Draft:         details.Draft,
+		Reviewers:     s.Reviewers,
+		TeamReviewers: s.TeamReviewers,
+		Assignees:     s.Assignees,
+		Labels:        s.Labels,
+		AutoMerge:     s.AutoMerge,
+	}
+	if s.Title != nil {
+		updatedPR.Title = *s.Title
+	}
+	if s.Body != nil {
+		updatedPR.Body = *s.Body
+	}
+	if s.Draft != nil {
+		updatedPR.Draft = *s.Draft
+		updatedPR.SetDraft = s.Draft
+	}
+
+	repo, err := repoFinder.find(ctx, pr)
+	if err != nil {
+		return err
+	}
+
+	// With several platforms, only some of them might be able to edit pull requests
+	if router, ok := s.VersionController.(VersionControllerRouter); ok {
+		vc, _, err := router.RepositoryVersionController(repo)
+		if err != nil {
+			return err
+		}
+		if !editsPullRequests(vc) {
+			return errEditNotSupported
+		}
+	}
+
+	_, err = s.VersionController.UpdatePullRequest(ctx, repo, pr, updatedPR)
+	return err
+}
@@ -1,57 +1,84 @@
 package multigitter
 
 import (
-	"regexp"
+	"context"
 
+	"github.com/lindell/multi-gitter/internal/repofilter"
 	"github.com/lindell/multi-gitter/internal/scm"
 	log "github.com/sirupsen/logrus"
 )
 
-// RepoFilters contains repository filtering options shared across commands
-type RepoFilters struct {
-	// SkipRepository is a list of repositories (owner/repo) that should be skipped
-	SkipRepository []string
+// RepoFilters contains repository filters shared across commands. A repository has to pass all of them to be included
+type RepoFilters []repofilter.Filter
 
-	// RegExIncludeRepository, when set, only repositories matching it will be included
-	RegExIncludeRepository *regexp.Regexp
-
-	// RegExExcludeRepository, when set, repositories matching it will be excluded
-	RegExExcludeRepository *regexp.Regexp
-}
-
-// Determines if Repository should be excluded based on provided Regular Expression
-func excludeRepositoryFilter(repoName string, regExp *regexp.Regexp) bool {
-	if regExp == nil {
-		return false
-	}
-	return regExp.MatchString(repoName)
-}
-
-// Determines if Repository should be included based on provided Regular Expression
-func matchesRepositoryFilter(repoName string, regExp *regexp.Regexp) bool {
-	if regExp == nil {
-		return true
+// skipReason returns the reason of the first filter that skips the repository, if any
+func (filters RepoFilters) skipReason(repo scm.Repository) string {
+	for _, filter := range filters {
+		if reason := filter.SkipReason(repo); reason != "" {
+			return reason
+		}
 	}
-	return regExp.MatchString(repoName)
+	return ""
 }
 
 func filterRepositories(repos []scm.Repository, filters RepoFilters) []scm.Repository {
-	skipReposMap := map[string]struct{}{}
-	for _, skipRepo := range filters.SkipRepository {
-		skipReposMap[skipRepo] = struct{}{}
-	}
-
 	filteredRepos := make([]scm.Repository, 0, len(repos))
 	for _, r := range repos {
-		if _, shouldSkip := skipReposMap[r.FullName()]; shouldSkip {
-			log.Infof("Skipping %s since it is in exclusion list", r.FullName())
-		} else if !matchesRepositoryFilter(r.FullName(), filters.RegExIncludeRepository) {
-			log.Infof("Skipping %s since it does not match the inclusion regexp", r.FullName())
-		} else if excludeRepositoryFilter(r.FullName(), filters.RegExExcludeRepository) {
-			log.Infof("Skipping %s since it match the exclusion regexp", r.FullName())
+		if reason := filters.skipReason(r); reason != "" {
+			log.Infof("Skipping %s since %s", r.FullName(), reason)
 		} else {
 			filteredRepos = append(filteredRepos, r)
 		}
 	}
 	return filteredRepos
 }
+
+// PullRequestSelection decides which of the pull requests of a feature branch are used
+type PullRequestSelection struct {
+	// IncludePullRequest can be set to only use some of the pull requests of the feature branch
+	IncludePullRequest func(pr scm.PullRequest) bool
+
+	// RepoFilters contains repository filtering options, only pull requests targeting repositories that pass them are used
+	RepoFilters RepoFilters
+	// HasFiles, when set, only pull requests targeting repositories containing a file matching one of the patterns are used
+	HasFiles []string
+}
+
+// includeFilteredRepositories extends the include function of the selection to only include pull requests targeting
+// repositories that pass the filters and contain any of the files. The repositories are listed and filtered once,
+// so that the returned function can be used every time the pull requests are fetched
+func includeFilteredRepositories(ctx context.Context, vc VersionController, selection PullRequestSelection) (func(pr scm.PullRequest) bool, error) {
+	if len(selection.RepoFilters) == 0 && len(selection.HasFiles) == 0 {
+		return selection.IncludePullRequest, nil
+	}
+
+	repos, err := vc.GetRepositories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	repos = filterRepositories(repos, selection.RepoFilters)
+	if len(selection.HasFiles) > 0 {
+		repos, err = filterRepositoriesByFiles(ctx, vc, repos, selection.HasFiles, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	repoNames := make(map[string]bool, len(repos))
+	for _, repo := range repos {
+		repoNames[repo.FullName()] = true
+	}
+
+	return func(pr scm.PullRequest) bool {
+		if selection.IncludePullRequest != nil && !selection.IncludePullRequest(pr) {
+			return false
+		}
+		p, ok := pr.(scm.PullRequestWithRepository)
+		if !ok {
+			log.Infof("Skipping %s since the repository of it is unknown", pr.String())
+			return false
+		}
+		return repoNames[p.RepositoryName()]
+	}, nil
+}
@@ -0,0 +1,126 @@
+package multigitter
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+)
+
+// fileListingConcurrency is the number of repositories that has their files listed at the same time
+const fileListingConcurrency = 10
+
+// filterRepositoriesByFiles keeps the repositories that contain a file or directory matching at least one of the
+// patterns. The files are listed through the API of the platform, without cloning the repositories.
+// If branch is empty, the default branch of each repository is used
+func filterRepositoriesByFiles(ctx context.Context, vc VersionController, repos []scm.Repository, patterns []string, branch string) ([]scm.Repository, error) {
+	fileLister, ok := vc.(VersionControllerRepositoryFiles)
+	if !ok {
+		return nil, errors.New("the platform does not support filtering on files")
+	}
+
+	regExps := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		regExp, err := globToRegexp(pattern)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid file pattern %s", pattern)
+		}
+		regExps = append(regExps, regExp)
+	}
+
+	keep := make([]bool, len(repos))
+	runInParallel(func(i int) {
+		repo := repos[i]
+		repoBranch := branch
+		if repoBranch == "" {
+			repoBranch = repo.DefaultBranch()
+		}
+
+		files, err := fileLister.RepositoryFiles(ctx, repo, repoBranch)
+		if err != nil {
+			log.Infof("Skipping %s since its files could not be listed: %s", repo.FullName(), err)
+			return
+		}
+
+		for _, file := range withParentDirectories(files) {
+			for _, regExp := range regExps {
+				if regExp.MatchString(file) {
+					keep[i] = true
+					return
+				}
+			}
+		}
+		log.Infof("Skipping %s since it does not contain any matching file", repo.FullName())
+	}, len(repos), fileListingConcurrency)
+
+	filteredRepos := make([]scm.Repository, 0, len(repos))
+	for i, repo := range repos {
+		if keep[i] {
+			filteredRepos = append(filteredRepos, repo)
+		}
+	}
+	return filteredRepos, nil
+}
+
+// withParentDirectories adds the parent directories of all files, since not all platforms list directories
+func withParentDirectories(files []string) []string {
+	seen := make(map[string]struct{}, len(files))
+	all := make([]string, 0, len(files))
+	for _, file := range files {
+		for p := file; p != "." && p != "/"; p = path.Dir(p) {
+			if _, ok := seen[p]; ok {
+				break
+			}
+			seen[p] = struct{}{}
+			all = append(all, p)
+		}
+	}
+	return all
+}
+
+// globToRegexp converts a glob pattern to a regular expression matching the full path of a file.
+// * and ? does not match /, while ** matches any number of directories
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	pattern = strings.Trim(pattern, "/")
+	if pattern == "" {
+		return nil, errors.New("the pattern is empty")
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				return nil, errors.New("unterminated character class")
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
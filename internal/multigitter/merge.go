@@ -2,43 +2,288 @@ package multigitter
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
-	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+
+	"github.com/lindell/multi-gitter/internal/multigitter/terminal"
+	"github.com/lindell/multi-gitter/internal/scm"
 )
 
+const defaultChecksInterval = 30 * time.Second
+
 // Merger merges pull requests in an organization
 type Merger struct {
 	VersionController VersionController
 
+	// Output is where the summary of merged, skipped and failed pull requests is written, if set
+	Output io.Writer
+
 	FeatureBranch string
+
+	PullRequestSelection
+
+	// ChecksTimeout is the maximum time to wait for pending checks to complete, the pull requests are polled every ChecksInterval.
+	// If not set, pull requests with pending checks are skipped directly
+	ChecksTimeout  time.Duration
+	ChecksInterval time.Duration
+
+	// RequiredApprovals is the number of approvals a pull request needs to have to be merged
+	RequiredApprovals int
+
+	Concurrent int
+	// MergeInterval is the minimum time between the start of two merges
+	MergeInterval time.Duration
+
+	// DryRun lists the pull requests that would be merged and skipped, without merging them
+	DryRun bool
+}
+
+// pullRequestResult is what happened with a single pull request, and why
+type pullRequestResult struct {
+	pr     scm.PullRequest
+	reason string
+}
+
+func (r pullRequestResult) String() string {
+	name := r.pr.String()
+	if urler, hasURL := r.pr.(urler); hasURL && urler.URL() != "" {
+		name = terminal.Link(name, urler.URL())
+	}
+	if r.reason == "" {
+		return name
+	}
+	return fmt.Sprintf("%s: %s", name, r.reason)
 }
 
 // Merge merges pull requests in an organization
 func (s Merger) Merge(ctx context.Context) error {
-	prs, err := s.VersionController.GetPullRequests(ctx, s.FeatureBranch)
+	if s.RequiredApprovals > 0 {
+		reporter, ok := s.VersionController.(VersionControllerApprovals)
+		if !ok || !reporter.ReportsApprovals() {
+			return errors.New("the platform does not report the approvals of pull requests, so the required approvals can't be checked")
+		}
+	}
+
+	include, err := includeFilteredRepositories(ctx, s.VersionController, s.PullRequestSelection)
 	if err != nil {
 		return err
 	}
+	s.IncludePullRequest = include
 
-	successPrs := make([]scm.PullRequest, 0, len(prs))
+	prs, err := s.waitForChecks(ctx)
+	if err != nil {
+		return err
+	}
+
+	var readyPRs []scm.PullRequest
+	var skipped []pullRequestResult
 	for _, pr := range prs {
-		if pr.Status() == scm.PullRequestStatusSuccess {
-			successPrs = append(successPrs, pr)
+		if reason := s.notReadyReason(pr); reason != "" {
+			log.WithField("pr", pr.String()).Infof("Skipping pull request that is not ready to be merged: %s", reason)
+			skipped = append(skipped, pullRequestResult{pr: pr, reason: reason})
+		} else if pr.Status().IsOpen() {
+			readyPRs = append(readyPRs, pr)
+		}
+	}
+
+	if s.DryRun {
+		log.Infof("Would merge %d pull requests", len(readyPRs))
+		if s.Output != nil {
+			wouldMerge := make([]pullRequestResult, 0, len(readyPRs))
+			for _, pr := range readyPRs {
+				wouldMerge = append(wouldMerge, pullRequestResult{pr: pr})
+			}
+			printResults(s.Output, resultSection{"Pull requests that would be merged", wouldMerge}, resultSection{"Skipped pull requests", skipped})
 		}
+		return nil
 	}
 
-	log.Infof("Merging %d pull requests", len(successPrs))
+	log.Infof("Merging %d pull requests", len(readyPRs))
 
-	for _, pr := range successPrs {
+	concurrent := s.Concurrent
+	if concurrent < 1 {
+		concurrent = 1
+	}
+
+	limiter := &intervalLimiter{interval: s.MergeInterval}
+	var lock sync.Mutex
+	var merged, failed []pullRequestResult
+	runInParallel(func(i int) {
+		pr := readyPRs[i]
 		log := log.WithField("pr", pr.String())
 
+		if ctx.Err() != nil {
+			lock.Lock()
+			failed = append(failed, pullRequestResult{pr: pr, reason: "aborted"})
+			lock.Unlock()
+			return
+		}
+
+		if err := limiter.wait(ctx); err != nil {
+			lock.Lock()
+			failed = append(failed, pullRequestResult{pr: pr, reason: "aborted"})
+			lock.Unlock()
+			return
+		}
 		log.Infof("Merging")
 		err := s.VersionController.MergePullRequest(ctx, pr)
+
+		lock.Lock()
+		defer lock.Unlock()
 		if err != nil {
 			log.Errorf("Error occurred while merging: %s", err.Error())
+			failed = append(failed, pullRequestResult{pr: pr, reason: err.Error()})
+			return
+		}
+		merged = append(merged, pullRequestResult{pr: pr})
+	}, len(readyPRs), concurrent)
+
+	if s.Output != nil {
+		printResults(s.Output,
+			resultSection{"Merged pull requests", merged},
+			resultSection{"Skipped pull requests", skipped},
+			resultSection{"Pull requests that failed to merge", failed},
+		)
+	}
+
+	if len(failed) > 0 {
+		return errors.Errorf("%d of %d pull requests could not be merged", len(failed), len(readyPRs))
+	}
+	return nil
+}
+
+// waitForChecks gets the pull requests, and polls them again as long as any of them has pending checks,
+// until the ChecksTimeout has passed
+func (s Merger) waitForChecks(ctx context.Context) ([]scm.PullRequest, error) {
+	prs, err := getPullRequests(ctx, s.VersionController, s.FeatureBranch, s.IncludePullRequest)
+	if err != nil || s.ChecksTimeout <= 0 {
+		return prs, err
+	}
+
+	interval := s.ChecksInterval
+	if interval <= 0 {
+		interval = defaultChecksInterval
+	}
+
+	timer := time.NewTimer(s.ChecksTimeout)
+	defer timer.Stop()
+
+	for {
+		var pending int
+		for _, pr := range prs {
+			if pr.Status() == scm.PullRequestStatusPending {
+				pending++
+			}
+		}
+		if pending == 0 {
+			return prs, nil
+		}
+
+		log.Infof("Waiting for the checks of %d pull requests to complete", pending)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+			log.Infof("The checks of %d pull requests did not complete within %s", pending, s.ChecksTimeout)
+			return prs, nil
+		case <-time.After(interval):
+		}
+
+		prs, err = getPullRequests(ctx, s.VersionController, s.FeatureBranch, s.IncludePullRequest)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// notReadyReason returns why an open pull request can not be merged, or an empty string if it's ready
+func (s Merger) notReadyReason(pr scm.PullRequest) string {
+	switch pr.Status() {
+	case scm.PullRequestStatusSuccess:
+	case scm.PullRequestStatusMerged, scm.PullRequestStatusClosed:
+		return ""
+	case scm.PullRequestStatusPending:
+		if s.ChecksTimeout > 0 {
+			return fmt.Sprintf("checks did not complete within %s", s.ChecksTimeout)
+		}
+		return "checks are pending"
+	case scm.PullRequestStatusError:
+		return "checks failed"
+	case scm.PullRequestStatusNeedsReview:
+		return "review required"
+	case scm.PullRequestStatusChangesRequested:
+		return "changes requested"
+	case scm.PullRequestStatusConflicting:
+		return "conflicts with the base branch"
+	case scm.PullRequestStatusDraft:
+		return "draft"
+	default:
+		return fmt.Sprintf("status is %s", pr.Status())
+	}
+
+	if s.RequiredApprovals > 0 {
+		if approvals := scm.GetPullRequestDetails(pr).Approvals; approvals < s.RequiredApprovals {
+			return fmt.Sprintf("%d of %d required approvals", approvals, s.RequiredApprovals)
+		}
+	}
+
+	return ""
+}
+
+type resultSection struct {
+	title   string
+	results []pullRequestResult
+}
+
+func printResults(w io.Writer, sections ...resultSection) {
+	for _, section := range sections {
+		if len(section.results) == 0 {
+			continue
+		}
+		slices.SortFunc(section.results, func(a, b pullRequestResult) int {
+			return strings.Compare(a.pr.String(), b.pr.String())
+		})
+		fmt.Fprintf(w, "%s:\n", section.title)
+		for _, result := range section.results {
+			fmt.Fprintf(w, "  %s\n", result)
 		}
 	}
+}
 
+// intervalLimiter makes sure at least the interval has passed between each call to wait
+type intervalLimiter struct {
+	interval time.Duration
+
+	mutex sync.Mutex
+	last  time.Time
+}
+
+func (l *intervalLimiter) wait(ctx context.Context) error {
+	if l.interval <= 0 {
+		return nil
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if shouldWait := l.interval - time.Since(l.last); shouldWait > 0 {
+		log.Debugf("Waiting %s before the next merge", shouldWait)
+
+		timer := time.NewTimer(shouldWait)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	l.last = time.Now()
 	return nil
 }
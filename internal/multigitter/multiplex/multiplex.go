@@ -0,0 +1,362 @@
+// Package multiplex contains a version controller that combines several version controllers into one
+package multiplex
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/lindell/multi-gitter/internal/git"
+	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/lindell/multi-gitter/internal/scm"
+)
+
+// Source is a named version controller that is part of a multiplexer
+type Source struct {
+	Name              string
+	VersionController multigitter.VersionController
+}
+
+// Multiplexer aggregates several version controllers. Repositories and pull requests are fetched from all of them,
+// and any operation on a repository or pull request is routed to the version controller it was fetched from
+type Multiplexer struct {
+	sources []Source
+}
+
+// New creates a new multiplexer
+func New(sources ...Source) (*Multiplexer, error) {
+	if len(sources) == 0 {
+		return nil, errors.New("at least one version controller is needed")
+	}
+
+	names := map[string]bool{}
+	for _, source := range sources {
+		if names[source.Name] {
+			return nil, errors.Errorf(`the name "%s" is used more than once`, source.Name)
+		}
+		names[source.Name] = true
+	}
+
+	return &Multiplexer{
+		sources: sources,
+	}, nil
+}
+
+// repository is a repository together with the source it was fetched from
+type repository struct {
+	scm.Repository
+	source *Source
+}
+
+// Metadata returns the metadata of the underlying repository
+func (r repository) Metadata() scm.RepositoryMetadata {
+	return scm.GetRepositoryMetadata(r.Repository)
+}
+
+// pullRequest is a pull request together with the source it was fetched from
+type pullRequest struct {
+	scm.PullRequest
+	source *Source
+}
+
+func (pr pullRequest) String() string {
+	return fmt.Sprintf("[%s] %s", pr.source.Name, pr.PullRequest.String())
+}
+
+// URL returns the url of the underlying pull request, if it has one
+func (pr pullRequest) URL() string {
+	if urler, ok := pr.PullRequest.(interface{ URL() string }); ok {
+		return urler.URL()
+	}
+	return ""
+}
+
+// Details returns the details of the underlying pull request
+func (pr pullRequest) Details() scm.PullRequestDetails {
+	return scm.GetPullRequestDetails(pr.PullRequest)
+}
+
+// RepositoryName returns the full name of the repository the underlying pull request targets
+func (pr pullRequest) RepositoryName() string {
+	if p, ok := pr.PullRequest.(scm.PullRequestWithRepository); ok {
+		return p.RepositoryName()
+	}
+	return ""
+}
+
+func (m *Multiplexer) wrapPullRequest(source *Source, pr scm.PullRequest) scm.PullRequest {
+	if pr == nil {
+		return nil
+	}
+	return pullRequest{
+		PullRequest: pr,
+		source:      source,
+	}
+}
+
+func unwrapRepository(repo scm.Repository) (scm.Repository, *Source, error) {
+	r, ok := repo.(repository)
+	if !ok {
+		return nil, nil, errors.Errorf("the repository %s was not fetched through the multiplexer", repo.FullName())
+	}
+	return r.Repository, r.source, nil
+}
+
+func unwrapPullRequest(pr scm.PullRequest) (scm.PullRequest, *Source, error) {
+	p, ok := pr.(pullRequest)
+	if !ok {
+		return nil, nil, errors.Errorf("the pull request %s was not fetched through the multiplexer", pr.String())
+	}
+	return p.PullRequest, p.source, nil
+}
+
+// RepositoryVersionController returns the version controller a repository was fetched from
+func (m *Multiplexer) RepositoryVersionController(repo scm.Repository) (multigitter.VersionController, scm.Repository, error) {
+	repo, source, err := unwrapRepository(repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	return source.VersionController, repo, nil
+}
+
+// GetRepositories fetches the repositories of all version controllers
+func (m *Multiplexer) GetRepositories(ctx context.Context) ([]scm.Repository, error) {
+	var repos []scm.Repository
+	for i := range m.sources {
+		source := &m.sources[i]
+		sourceRepos, err := source.VersionController.GetRepositories(ctx)
+		if err != nil {
+			return nil, errors.WithMessage(err, source.Name)
+		}
+		for _, repo := range sourceRepos {
+			repos = append(repos, repository{
+				Repository: repo,
+				source:     source,
+			})
+		}
+	}
+	return repos, nil
+}
+
+// CreatePullRequest creates a pull request with the version controller of the repository
+func (m *Multiplexer) CreatePullRequest(ctx context.Context, repo scm.Repository, prRepo scm.Repository, newPR scm.NewPullRequest) (scm.PullRequest, error) {
+	repo, source, err := unwrapRepository(repo)
+	if err != nil {
+		return nil, err
+	}
+	prRepo, _, err = unwrapRepository(prRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, err := source.VersionController.CreatePullRequest(ctx, repo, prRepo, newPR)
+	return m.wrapPullRequest(source, pr), err
+}
+
+// UpdatePullRequest updates a pull request with the version controller of the repository
+func (m *Multiplexer) UpdatePullRequest(ctx context.Context, repo scm.Repository, pullReq scm.PullRequest, updatedPR scm.NewPullRequest) (scm.PullRequest, error) {
+	repo, source, err := unwrapRepository(repo)
+	if err != nil {
+		return nil, err
+	}
+	pullReq, _, err = unwrapPullRequest(pullReq)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, err := source.VersionController.UpdatePullRequest(ctx, repo, pullReq, updatedPR)
+	return m.wrapPullRequest(source, pr), err
+}
+
+// GetPullRequests gets the pull requests of a branch from all version controllers
+func (m *Multiplexer) GetPullRequests(ctx context.Context, branchName string) ([]scm.PullRequest, error) {
+	var prs []scm.PullRequest
+	for i := range m.sources {
+		source := &m.sources[i]
+		sourcePRs, err := source.VersionController.GetPullRequests(ctx, branchName)
+		if err != nil {
+			return nil, errors.WithMessage(err, source.Name)
+		}
+		for _, pr := range sourcePRs {
+			prs = append(prs, m.wrapPullRequest(source, pr))
+		}
+	}
+	return prs, nil
+}
+
+// GetOpenPullRequest gets an open pull request of a repository with the version controller of the repository
+func (m *Multiplexer) GetOpenPullRequest(ctx context.Context, repo scm.Repository, branchName string) (scm.PullRequest, error) {
+	repo, source, err := unwrapRepository(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, err := source.VersionController.GetOpenPullRequest(ctx, repo, branchName)
+	return m.wrapPullRequest(source, pr), err
+}
+
+// MergePullRequest merges a pull request with the version controller it was fetched from
+func (m *Multiplexer) MergePullRequest(ctx context.Context, pr scm.PullRequest) error {
+	pr, source, err := unwrapPullRequest(pr)
+	if err != nil {
+		return err
+	}
+	return source.VersionController.MergePullRequest(ctx, pr)
+}
+
+// ClosePullRequest closes a pull request with the version controller it was fetched from
+func (m *Multiplexer) ClosePullRequest(ctx context.Context, pr scm.PullRequest) error {
+	pr, source, err := unwrapPullRequest(pr)
+	if err != nil {
+		return err
+	}
+	return source.VersionController.ClosePullRequest(ctx, pr)
+}
+
+// CommentPullRequest comments on a pull request with the version controller it was fetched from
+func (m *Multiplexer) CommentPullRequest(ctx context.Context, pr scm.PullRequest, comment string) error {
+	pr, source, err := unwrapPullRequest(pr)
+	if err != nil {
+		return err
+	}
+
+	commenter, ok := source.VersionController.(multigitter.VersionControllerCommentPullRequest)
+	if !ok {
+		return errors.New("the scm implementation does not support commenting on pull requests")
+	}
+	return commenter.CommentPullRequest(ctx, pr, comment)
+}
+
+// PullRequestComments lists the comments of a pull request with the version controller it was fetched from
+func (m *Multiplexer) PullRequestComments(ctx context.Context, pr scm.PullRequest) ([]string, error) {
+	pr, source, err := unwrapPullRequest(pr)
+	if err != nil {
+		return nil, err
+	}
+
+	commentLister, ok := source.VersionController.(multigitter.VersionControllerPullRequestComments)
+	if !ok {
+		return nil, errors.New("the scm implementation does not support listing the comments of pull requests")
+	}
+	return commentLister.PullRequestComments(ctx, pr)
+}
+
+// ApprovePullRequest approves a pull request with the version controller it was fetched from
+func (m *Multiplexer) ApprovePullRequest(ctx context.Context, pr scm.PullRequest) error {
+	pr, source, err := unwrapPullRequest(pr)
+	if err != nil {
+		return err
+	}
+
+	approver, ok := source.VersionController.(multigitter.VersionControllerApprovePullRequest)
+	if !ok {
+		return errors.New("the scm implementation does not support approving pull requests")
+	}
+	return approver.ApprovePullRequest(ctx, pr)
+}
+
+// ReportsApprovals tells if all the version controllers report the number of approvals of pull requests
+func (m *Multiplexer) ReportsApprovals() bool {
+	for _, source := range m.sources {
+		reporter, ok := source.VersionController.(multigitter.VersionControllerApprovals)
+		if !ok || !reporter.ReportsApprovals() {
+			return false
+		}
+	}
+	return true
+}
+
+// RetryFailedChecks re-runs the failed checks of a pull request with the version controller it was fetched from
+func (m *Multiplexer) RetryFailedChecks(ctx context.Context, pr scm.PullRequest, maxAttempts int) error {
+	pr, source, err := unwrapPullRequest(pr)
+	if err != nil {
+		return err
+	}
+
+	retrier, ok := source.VersionController.(multigitter.VersionControllerRetryChecks)
+	if !ok {
+		return errors.New("the scm implementation does not support re-running checks")
+	}
+	return retrier.RetryFailedChecks(ctx, pr, maxAttempts)
+}
+
+// DeletePullRequestBranch deletes the branch of a pull request with the version controller it was fetched from
+func (m *Multiplexer) DeletePullRequestBranch(ctx context.Context, pr scm.PullRequest) error {
+	pr, source, err := unwrapPullRequest(pr)
+	if err != nil {
+		return err
+	}
+
+	branchDeleter, ok := source.VersionController.(multigitter.VersionControllerDeletePullRequestBranch)
+	if !ok {
+		return errors.New("the scm implementation does not support deleting the branches of pull requests")
+	}
+	return branchDeleter.DeletePullRequestBranch(ctx, pr)
+}
+
+// UpdatePullRequestBranch updates the branch of a pull request with the version controller it was fetched from
+func (m *Multiplexer) UpdatePullRequestBranch(ctx context.Context, pr scm.PullRequest, rebase bool) error {
+	pr, source, err := unwrapPullRequest(pr)
+	if err != nil {
+		return err
+	}
+
+	branchUpdater, ok := source.VersionController.(multigitter.VersionControllerUpdatePullRequestBranch)
+	if !ok {
+		return scm.ErrUpdateMethodNotSupported
+	}
+	return branchUpdater.UpdatePullRequestBranch(ctx, pr, rebase)
+}
+
+// ForkRepository forks a repository with the version controller of the repository
+func (m *Multiplexer) ForkRepository(ctx context.Context, repo scm.Repository, newOwner string) (scm.Repository, error) {
+	repo, source, err := unwrapRepository(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	fork, err := source.VersionController.ForkRepository(ctx, repo, newOwner)
+	if err != nil {
+		return nil, err
+	}
+	return repository{
+		Repository: fork,
+		source:     source,
+	}, nil
+}
+
+// Push commits changes through the API of the version controller of the repository
+func (m *Multiplexer) Push(
+	ctx context.Context,
+	repo scm.Repository,
+	changes []git.Changes,
+	featureBranch string,
+	branchExist bool,
+	forcePush bool,
+) error {
+	repo, source, err := unwrapRepository(repo)
+	if err != nil {
+		return err
+	}
+
+	changePusher, ok := source.VersionController.(scm.ChangePusher)
+	if !ok {
+		return errors.New("the scm implementation does not support committing through the API")
+	}
+	return changePusher.Push(ctx, repo, changes, featureBranch, branchExist, forcePush)
+}
+
+// RepositoryFiles lists the files of a repository with the version controller of the repository
+func (m *Multiplexer) RepositoryFiles(ctx context.Context, repo scm.Repository, branch string) ([]string, error) {
+	repo, source, err := unwrapRepository(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	fileLister, ok := source.VersionController.(multigitter.VersionControllerRepositoryFiles)
+	if !ok {
+		return nil, errors.New("the scm implementation does not support listing files")
+	}
+	return fileLister.RepositoryFiles(ctx, repo, branch)
+}
@@ -23,6 +23,8 @@ type Printer struct {
 
 	// RepoFilters contains repository filtering options
 	RepoFilters RepoFilters
+	// HasFiles, when set, only repositories containing a file matching one of the patterns are used
+	HasFiles []string
 
 	Concurrent int
 	CloneDir   string
@@ -39,6 +41,13 @@ func (r Printer) Print(ctx context.Context) error {
 
 	repos = filterRepositories(repos, r.RepoFilters)
 
+	if len(r.HasFiles) > 0 {
+		repos, err = filterRepositoriesByFiles(ctx, r.VersionController, repos, r.HasFiles, "")
+		if err != nil {
+			return err
+		}
+	}
+
 	if len(repos) == 0 {
 		log.Infof("No repositories found. Please make sure the user of the token has the correct access to the repos you want print to run on.")
 		return nil
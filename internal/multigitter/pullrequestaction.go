@@ -0,0 +1,111 @@
+package multigitter
+
+import (
+	"context"
+	"io"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// pullRequestOutcome is the outcome of an action on a single pull request, it decides which section the pull request is listed in
+type pullRequestOutcome int
+
+const (
+	outcomeDone pullRequestOutcome = iota
+	outcomeSkipped
+	outcomeFailed
+)
+
+// pullRequestAction is an action that is done on each of the selected pull requests of a feature branch.
+// A pull request that the action fails on does not stop it from being done on the others
+type pullRequestAction struct {
+	// use decides which of the pull requests the action is done on, the open pull requests are used if nil
+	use func(pr scm.PullRequest) bool
+
+	// dryRun only lists the pull requests under dryRunTitle, without doing the action
+	dryRun      bool
+	dryRunTitle string
+
+	// dryRunMessage and message are logged with the number of pull requests, like "Would close %d pull requests" and "Closing %d pull requests"
+	dryRunMessage string
+	message       string
+
+	// do does the action on a single pull request, the reason is listed together with the pull request
+	do func(ctx context.Context, pr scm.PullRequest, log log.FieldLogger) (outcome pullRequestOutcome, reason string)
+
+	// The titles of the sections the pull requests of each outcome are listed under
+	doneTitle    string
+	skippedTitle string
+	failedTitle  string
+	// print, if set, prints the result sections instead of them being listed
+	print func(w io.Writer, sections []resultSection)
+
+	// skippedFailed counts skipped pull requests as failed
+	skippedFailed bool
+	// failedMessage is the error returned if the action failed on any pull request, formatted with the number of failed and used pull requests
+	failedMessage string
+}
+
+// run does the action on the selected pull requests of the feature branch, and writes the result to output if it's set
+func (a pullRequestAction) run(ctx context.Context, vc VersionController, output io.Writer, featureBranch string, selection PullRequestSelection) error {
+	include, err := includeFilteredRepositories(ctx, vc, selection)
+	if err != nil {
+		return err
+	}
+
+	prs, err := getPullRequests(ctx, vc, featureBranch, include)
+	if err != nil {
+		return err
+	}
+
+	use := a.use
+	if use == nil {
+		use = func(pr scm.PullRequest) bool { return pr.Status().IsOpen() }
+	}
+	usedPRs := make([]scm.PullRequest, 0, len(prs))
+	for _, pr := range prs {
+		if use(pr) {
+			usedPRs = append(usedPRs, pr)
+		}
+	}
+
+	if a.dryRun {
+		log.Infof(a.dryRunMessage, len(usedPRs))
+		if output != nil {
+			printPullRequestList(output, a.dryRunTitle, usedPRs)
+		}
+		return nil
+	}
+
+	log.Infof(a.message, len(usedPRs))
+
+	sections := []resultSection{{title: a.doneTitle}, {title: a.skippedTitle}, {title: a.failedTitle}}
+	for _, pr := range usedPRs {
+		if ctx.Err() != nil {
+			sections[outcomeFailed].results = append(sections[outcomeFailed].results, pullRequestResult{pr: pr, reason: "aborted"})
+			continue
+		}
+
+		outcome, reason := a.do(ctx, pr, log.WithField("pr", pr.String()))
+		sections[outcome].results = append(sections[outcome].results, pullRequestResult{pr: pr, reason: reason})
+	}
+
+	if output != nil {
+		if a.print != nil {
+			a.print(output, sections)
+		} else {
+			printResults(output, sections...)
+		}
+	}
+
+	failed := len(sections[outcomeFailed].results)
+	if a.skippedFailed {
+		failed += len(sections[outcomeSkipped].results)
+	}
+	if failed > 0 {
+		return errors.Errorf(a.failedMessage, failed, len(usedPRs))
+	}
+	return nil
+}
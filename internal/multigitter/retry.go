@@ -0,0 +1,91 @@
+package multigitter
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// ChecksRetrier re-runs the failed checks of pull requests
+type ChecksRetrier struct {
+	VersionController VersionController
+
+	// Output is where the result of re-running the checks is written
+	Output io.Writer
+
+	FeatureBranch string
+
+	PullRequestSelection
+
+	// MaxAttempts is the maximum number of times a check is run, including its first run
+	MaxAttempts int
+
+	// DryRun lists the pull requests that would have their checks re-run, without re-running them
+	DryRun bool
+}
+
+// Retry re-runs the failed checks of each open pull request.
+// A pull request where the checks fail to be re-run does not stop the others from being re-run
+func (s ChecksRetrier) Retry(ctx context.Context) error {
+	retrier, ok := s.VersionController.(VersionControllerRetryChecks)
+	if !ok {
+		return errors.New("the platform does not support re-running checks")
+	}
+
+	if s.MaxAttempts < 2 {
+		return errors.New("the maximum number of attempts has to be at least 2")
+	}
+
+	return pullRequestAction{
+		use:           hasFailedChecks,
+		dryRun:        s.DryRun,
+		dryRunTitle:   "Pull requests that would have their checks re-run",
+		dryRunMessage: "Would re-run the checks of %d pull requests",
+		message:       "Re-running the checks of %d pull requests",
+		do: func(ctx context.Context, pr scm.PullRequest, log log.FieldLogger) (pullRequestOutcome, string) {
+			log.Infof("Re-running the failed checks")
+			err := retrier.RetryFailedChecks(ctx, pr, s.MaxAttempts)
+			switch {
+			case errors.Is(err, scm.ErrMaxAttemptsReached):
+				log.Infof("Skipping, the checks have already been run %d times", s.MaxAttempts)
+				return outcomeSkipped, fmt.Sprintf("the checks have already been run %d times", s.MaxAttempts)
+			case errors.Is(err, scm.ErrNoFailedChecks):
+				log.Infof("Skipping, there are no failed checks that can be re-run")
+				return outcomeSkipped, "no failed checks that can be re-run"
+			case err != nil:
+				log.Errorf("Error occurred while re-running the checks: %s", err.Error())
+				return outcomeFailed, err.Error()
+			}
+			return outcomeDone, ""
+		},
+		doneTitle:     "Pull requests with re-run checks",
+		skippedTitle:  "Skipped pull requests",
+		failedTitle:   "Pull requests where the checks could not be re-run",
+		failedMessage: "the checks of %d of %d pull requests could not be re-run",
+	}.run(ctx, s.VersionController, s.Output, s.FeatureBranch, s.PullRequestSelection)
+}
+
+// hasFailedChecks checks if an open pull request has failed checks. The status of the pull request can't be used,
+// since drafts and conflicting pull requests get those statuses even when their checks fail
+func hasFailedChecks(pr scm.PullRequest) bool {
+	switch pr.Status() {
+	case scm.PullRequestStatusMerged, scm.PullRequestStatusClosed:
+		return false
+	}
+
+	checks := scm.GetPullRequestDetails(pr).Checks
+	if len(checks) == 0 {
+		// The platform does not list the checks, only their combined status
+		return pr.Status() == scm.PullRequestStatusError
+	}
+	for _, check := range checks {
+		if check.Status == scm.CheckStatusFailure {
+			return true
+		}
+	}
+	return false
+}
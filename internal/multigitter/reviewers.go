@@ -0,0 +1,97 @@
+package multigitter
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/lindell/multi-gitter/internal/codeowners"
+	"github.com/lindell/multi-gitter/internal/rotation"
+)
+
+// reviewers are the users and teams that are requested to review a pull request
+type reviewers struct {
+	users []string
+	teams []string
+
+	rotation     *rotation.Rotation
+	wasRequested bool
+}
+
+// requested marks the reviewers as requested on a pull request, which keeps them counted in the rotation
+func (r *reviewers) requested() {
+	r.wasRequested = true
+}
+
+// release gives the reviewers back to the rotation, unless they have been requested on a pull request
+func (r *reviewers) release() {
+	if !r.wasRequested {
+		r.rotation.Release(r.users, r.teams)
+	}
+}
+
+// selectReviewers selects the reviewers of the pull request of a repository cloned to dir.
+// The reviewers are reserved in the rotation, and have to be released with release once the run of the repository is done
+func (r *Runner) selectReviewers(reviewerRotation *rotation.Rotation, dir string, sourceController Git, commitHashBeforeRun string) (*reviewers, error) {
+	users := r.Reviewers
+	teams := r.TeamReviewers
+
+	if r.CodeOwners {
+		owners, err := changedFileOwners(dir, sourceController, commitHashBeforeRun)
+		if err != nil {
+			return nil, err
+		}
+		users = appendUnique(users, owners.Users...)
+		teams = appendUnique(teams, owners.Teams...)
+	}
+
+	return &reviewers{
+		users: reviewerRotation.Users(users, r.MaxReviewers),
+		teams: reviewerRotation.Teams(teams, r.MaxTeamReviewers),
+
+		rotation: reviewerRotation,
+	}, nil
+}
+
+// changedFileOwners returns the code owners of the files changed since the commit
+func changedFileOwners(dir string, sourceController Git, commitHashBeforeRun string) (codeowners.Owners, error) {
+	file, err := codeowners.Find(dir)
+	if err != nil {
+		return codeowners.Owners{}, errors.WithMessage(err, "could not parse the CODEOWNERS file")
+	}
+	if file == nil {
+		return codeowners.Owners{}, nil
+	}
+
+	changes, err := sourceController.ChangesSinceCommit(commitHashBeforeRun)
+	if err != nil {
+		return codeowners.Owners{}, errors.Wrap(err, "could not get the changed files")
+	}
+
+	var paths []string
+	for _, change := range changes {
+		for path := range change.Additions {
+			paths = append(paths, path)
+		}
+		paths = append(paths, change.Deletions...)
+	}
+	sort.Strings(paths)
+
+	return file.Owners(paths), nil
+}
+
+func appendUnique(list []string, values ...string) []string {
+	seen := make(map[string]bool, len(list))
+	for _, v := range list {
+		seen[v] = true
+	}
+
+	ret := append([]string{}, list...)
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"math/rand"
 	"os"
 	"os/exec"
 	"strings"
@@ -21,6 +20,7 @@ import (
 	"github.com/lindell/multi-gitter/internal/multigitter/logger"
 	"github.com/lindell/multi-gitter/internal/multigitter/repocounter"
 	"github.com/lindell/multi-gitter/internal/multigitter/terminal"
+	"github.com/lindell/multi-gitter/internal/rotation"
 )
 
 // VersionController fetches repositories
@@ -47,6 +47,76 @@ type VersionControllerRemoteReference interface {
 	RemoteReference(baseBranch string, featureBranch string, skipPullRequest bool, pushOnly bool) string
 }
 
+// VersionControllerRouter is implemented by version controllers that combine several version controllers.
+// The interfaces that are not called with a pull request, like VersionControllerRemoteReference, are used on the version controller of the repository
+type VersionControllerRouter interface {
+	// RepositoryVersionController returns the version controller a repository was fetched from, and the repository as that version controller knows it
+	RepositoryVersionController(repo scm.Repository) (VersionController, scm.Repository, error)
+}
+
+// VersionControllerRepositoryFiles is implemented by version controllers that can list the files of a repository without cloning it
+type VersionControllerRepositoryFiles interface {
+	// RepositoryFiles returns the paths of all files in the repository on the branch, directories may also be included
+	RepositoryFiles(ctx context.Context, repo scm.Repository, branch string) ([]string, error)
+}
+
+// VersionControllerCommentPullRequest is implemented by version controllers that can comment on pull requests
+type VersionControllerCommentPullRequest interface {
+	CommentPullRequest(ctx context.Context, pr scm.PullRequest, comment string) error
+}
+
+// VersionControllerPullRequestComments is implemented by version controllers that can list the comments of pull requests
+type VersionControllerPullRequestComments interface {
+	// PullRequestComments returns the text of the comments on a pull request, comments generated by the platform itself may be left out
+	PullRequestComments(ctx context.Context, pr scm.PullRequest) ([]string, error)
+}
+
+// VersionControllerDeletePullRequestBranch is implemented by version controllers where the head branch of a pull request can be deleted
+type VersionControllerDeletePullRequestBranch interface {
+	// DeletePullRequestBranch deletes the head branch of a pull request, in the fork if the pull request was made from one
+	DeletePullRequestBranch(ctx context.Context, pr scm.PullRequest) error
+}
+
+// VersionControllerUpdatePullRequestBranch is implemented by version controllers that can update the branch of a pull request with its base branch
+type VersionControllerUpdatePullRequestBranch interface {
+	// UpdatePullRequestBranch updates the head branch of a pull request with the latest changes of its base branch, by rebasing it or by merging the base branch into it.
+	// scm.ErrUpdateMethodNotSupported is returned if the platform can't do it in the requested way, and git.ErrConflict if the branches conflict
+	UpdatePullRequestBranch(ctx context.Context, pr scm.PullRequest, rebase bool) error
+}
+
+// VersionControllerApprovePullRequest is implemented by version controllers where pull requests can be approved
+type VersionControllerApprovePullRequest interface {
+	// ApprovePullRequest approves a pull request as the authenticated user.
+	// scm.ErrOwnPullRequest is returned, without approving, if the pull request was created by the authenticated user
+	ApprovePullRequest(ctx context.Context, pr scm.PullRequest) error
+}
+
+// VersionControllerApprovals is implemented by version controllers that can report the number of approvals of pull requests
+type VersionControllerApprovals interface {
+	// ReportsApprovals tells if the details of pull requests contain the number of approvals
+	ReportsApprovals() bool
+}
+
+// VersionControllerEditPullRequests is implemented by version controllers that might not be able to edit existing pull requests
+type VersionControllerEditPullRequests interface {
+	// EditsPullRequests tells if UpdatePullRequest changes the title, body and other metadata of a pull request.
+	// Version controllers that don't implement this interface are expected to do so
+	EditsPullRequests() bool
+}
+
+// VersionControllerRetryChecks is implemented by version controllers that can re-run the failed checks of pull requests
+type VersionControllerRetryChecks interface {
+	// RetryFailedChecks re-runs the failed checks of a pull request that have been run fewer than maxAttempts times.
+	// scm.ErrNoFailedChecks is returned if there was nothing to re-run,
+	// and scm.ErrMaxAttemptsReached if all failed checks already have been run maxAttempts times
+	RetryFailedChecks(ctx context.Context, pr scm.PullRequest, maxAttempts int) error
+}
+
+// PullRequestRecorder records the pull requests that are created or updated by a run
+type PullRequestRecorder interface {
+	RecordPullRequest(repo scm.Repository, pr scm.PullRequest) error
+}
+
 // Runner contains fields to be able to do the run
 type Runner struct {
 	VersionController VersionController
@@ -62,8 +132,11 @@ type Runner struct {
 	PullRequestBody  string
 	Reviewers        []string
 	TeamReviewers    []string
-	MaxReviewers     int // If set to zero, all reviewers will be use
-	MaxTeamReviewers int // If set to zero, all team-reviewers will be used
+	MaxReviewers     int  // If set to zero, all reviewers will be use
+	MaxTeamReviewers int  // If set to zero, all team-reviewers will be used
+	CodeOwners       bool // If set, the code owners of the changed files are requested as reviewers
+	// ReviewerRotation spreads the reviews evenly over the reviewers. If not set, a new rotation is used for every run
+	ReviewerRotation *rotation.Rotation
 	DryRun           bool
 	CommitAuthor     *git.CommitAuthor
 	BaseBranch       string // The base branch of the PR, use default branch if not set
@@ -78,6 +151,8 @@ type Runner struct {
 
 	// RepoFilters contains repository filtering options
 	RepoFilters RepoFilters
+	// HasFiles, when set, only repositories containing a file matching one of the patterns are used
+	HasFiles []string
 
 	Fork      bool   // If set, create a fork and make the pull request from it
 	ForkOwner string // The owner of the new fork. If empty, the fork should happen on the logged in user
@@ -93,6 +168,8 @@ type Runner struct {
 
 	Interactive bool // If set, interactive mode is activated and the user will be asked to verify every change
 
+	Recorder PullRequestRecorder // If set, all created or updated pull requests are recorded
+
 	CreateGit func(dir string) Git
 }
 
@@ -126,6 +203,13 @@ func (r *Runner) Run(ctx context.Context) error {
 
 	repos = filterRepositories(repos, r.RepoFilters)
 
+	if len(r.HasFiles) > 0 {
+		repos, err = filterRepositoriesByFiles(ctx, r.VersionController, repos, r.HasFiles, r.BaseBranch)
+		if err != nil {
+			return err
+		}
+	}
+
 	if len(repos) == 0 {
 		log.Infof("No repositories found. Please make sure the user of the token has the correct access to the repos you want to change.")
 		return nil
@@ -139,6 +223,11 @@ func (r *Runner) Run(ctx context.Context) error {
 		}
 	}()
 
+	reviewerRotation := r.ReviewerRotation
+	if reviewerRotation == nil {
+		reviewerRotation = rotation.New(nil)
+	}
+
 	log.Infof("Running on %d repositories", len(repos))
 
 	runInParallel(func(i int) {
@@ -151,7 +240,7 @@ func (r *Runner) Run(ctx context.Context) error {
 			}
 		}()
 
-		pr, err := r.runSingleRepo(ctx, repos[i])
+		pr, err := r.runSingleRepo(ctx, repos[i], reviewerRotation)
 		if err != nil {
 			if err != errAborted {
 				logger.Info(err)
@@ -168,12 +257,23 @@ func (r *Runner) Run(ctx context.Context) error {
 		}
 
 		if pr != nil {
+			if r.Recorder != nil && !r.DryRun {
+				if err := r.Recorder.RecordPullRequest(repos[i], pr); err != nil {
+					logger.Errorf("Could not record the pull request: %s", err)
+				}
+			}
 			rc.AddSuccessPullRequest(repos[i], pr)
 		} else {
 			rc.AddSuccessRepositories(repos[i])
 		}
 	}, len(repos), r.Concurrent)
 
+	if !r.DryRun {
+		if err := reviewerRotation.Save(); err != nil {
+			return errors.WithMessage(err, "could not save the reviewer rotation")
+		}
+	}
+
 	return nil
 }
 
@@ -192,17 +292,7 @@ func runInParallel(fun func(i int), total int, maxConcurrent int) {
 	wg.Wait()
 }
 
-func getReviewers(reviewers []string, maxReviewers int) []string {
-	if maxReviewers == 0 || len(reviewers) <= maxReviewers {
-		return reviewers
-	}
-
-	rand.Shuffle(len(reviewers), func(i, j int) { reviewers[i], reviewers[j] = reviewers[j], reviewers[i] })
-
-	return reviewers[0:maxReviewers]
-}
-
-func (r *Runner) runSingleRepo(ctx context.Context, repo scm.Repository) (scm.PullRequest, error) {
+func (r *Runner) runSingleRepo(ctx context.Context, repo scm.Repository, reviewerRotation *rotation.Rotation) (scm.PullRequest, error) {
 	if ctx.Err() != nil {
 		return nil, errAborted
 	}
@@ -290,6 +380,12 @@ func (r *Runner) runSingleRepo(ctx context.Context, repo scm.Repository) (scm.Pu
 		return nil, errors.Wrap(err, "could not get pull request title and body")
 	}
 
+	reviewers, err := r.selectReviewers(reviewerRotation, tmpDir, sourceController, commitHashBeforeRun)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not select reviewers")
+	}
+	defer reviewers.release()
+
 	if r.Interactive {
 		err = r.interactive(tmpDir, repo, commitHashBeforeRun)
 		if err != nil {
@@ -328,7 +424,7 @@ func (r *Runner) runSingleRepo(ctx context.Context, repo scm.Repository) (scm.Pu
 		if err != nil {
 			return nil, errors.Wrap(err, "could not verify if branch already exists")
 		} else if featureBranchExist && r.ConflictStrategy == ConflictStrategySkip {
-			pr, err := r.ensurePullRequestExists(ctx, log, repo, prRepo, baseBranch, featureBranchExist, prTitle, prBody)
+			pr, err := r.ensurePullRequestExists(ctx, log, repo, prRepo, baseBranch, featureBranchExist, prTitle, prBody, reviewers)
 			if err != nil {
 				return nil, err
 			}
@@ -341,7 +437,7 @@ func (r *Runner) runSingleRepo(ctx context.Context, repo scm.Repository) (scm.Pu
 	forcePush := featureBranchExist && r.ConflictStrategy == ConflictStrategyReplace
 
 	if !r.APIPush {
-		remoteReference := r.remoteReference(baseBranch, r.FeatureBranch)
+		remoteReference := r.remoteReference(repo, baseBranch, r.FeatureBranch)
 		err = sourceController.Push(ctx, remoteName, remoteReference, forcePush, r.PushOptions...)
 		if err != nil {
 			return nil, errors.Wrap(err, "could not push changes")
@@ -369,11 +465,12 @@ func (r *Runner) runSingleRepo(ctx context.Context, repo scm.Repository) (scm.Pu
 		}, nil
 	}
 
-	return r.ensurePullRequestExists(ctx, log, repo, prRepo, baseBranch, featureBranchExist, prTitle, prBody)
+	return r.ensurePullRequestExists(ctx, log, repo, prRepo, baseBranch, featureBranchExist, prTitle, prBody, reviewers)
 }
 
 func (r *Runner) enhanceCommitMessage(ctx context.Context, repo scm.Repository) string {
-	vcs, ok := r.VersionController.(VersionControllerEnhanceCommit)
+	vc, repo := r.repositoryVersionController(repo)
+	vcs, ok := vc.(VersionControllerEnhanceCommit)
 	if ok {
 		commitMessage, _ := vcs.EnhanceCommit(ctx, repo, r.FeatureBranch, r.CommitMessage)
 		return commitMessage
@@ -381,6 +478,19 @@ func (r *Runner) enhanceCommitMessage(ctx context.Context, repo scm.Repository)
 	return r.CommitMessage
 }
 
+// repositoryVersionController returns the version controller that the repository belongs to
+func (r *Runner) repositoryVersionController(repo scm.Repository) (VersionController, scm.Repository) {
+	router, ok := r.VersionController.(VersionControllerRouter)
+	if !ok {
+		return r.VersionController, repo
+	}
+	vc, routedRepo, err := router.RepositoryVersionController(repo)
+	if err != nil {
+		return r.VersionController, repo
+	}
+	return vc, routedRepo
+}
+
 // Get the PR title and body
 // In the default case, this is simply the set title and body,
 // but it may also be extracted from a commit messages if manual commits are used
@@ -411,15 +521,17 @@ func (r *Runner) getPRBodyAndTitle(sourceController Git, commitHashBeforeRun str
 }
 
 func (r *Runner) featureBranchExist(ctx context.Context, repo scm.Repository, remoteName string, sourceController Git) (bool, error) {
-	vcs, ok := r.VersionController.(VersionControllerFeatureBranchExist)
+	vc, repo := r.repositoryVersionController(repo)
+	vcs, ok := vc.(VersionControllerFeatureBranchExist)
 	if ok {
 		return vcs.FeatureBranchExist(ctx, repo, r.FeatureBranch)
 	}
 	return sourceController.BranchExist(remoteName, r.FeatureBranch)
 }
 
-func (r *Runner) remoteReference(baseBranch string, featureBranch string) string {
-	vcs, ok := r.VersionController.(VersionControllerRemoteReference)
+func (r *Runner) remoteReference(repo scm.Repository, baseBranch string, featureBranch string) string {
+	vc, _ := r.repositoryVersionController(repo)
+	vcs, ok := vc.(VersionControllerRemoteReference)
 	if ok {
 		return vcs.RemoteReference(baseBranch, featureBranch, r.SkipPullRequest, r.PushOnly)
 	}
@@ -435,6 +547,7 @@ func (r *Runner) ensurePullRequestExists(
 	featureBranchExist bool,
 	prTitle string,
 	prBody string,
+	reviewers *reviewers,
 ) (scm.PullRequest, error) {
 	if r.SkipPullRequest {
 		return nil, nil
@@ -453,36 +566,48 @@ func (r *Runner) ensurePullRequestExists(
 	if existingPullRequest != nil {
 		if r.ConflictStrategy == ConflictStrategyReplace {
 			log.Info("Updating pull request since one is already open")
-			return r.VersionController.UpdatePullRequest(ctx, repo, existingPullRequest, scm.NewPullRequest{
+			pr, err := r.VersionController.UpdatePullRequest(ctx, repo, existingPullRequest, scm.NewPullRequest{
 				Title:         prTitle,
 				Body:          prBody,
 				Head:          r.FeatureBranch,
 				Base:          baseBranch,
-				Reviewers:     getReviewers(r.Reviewers, r.MaxReviewers),
-				TeamReviewers: getReviewers(r.TeamReviewers, r.MaxTeamReviewers),
+				Reviewers:     reviewers.users,
+				TeamReviewers: reviewers.teams,
 				Assignees:     r.Assignees,
 				Draft:         r.Draft,
 				AutoMerge:     r.AutoMerge,
 				Labels:        r.Labels,
 			})
+			if err != nil {
+				return nil, err
+			}
+			reviewers.requested()
+
+			return pr, nil
 		}
 		log.Info("Skip creating pull requests since one is already open")
 		return existingPullRequest, nil
 	}
 
 	log.Info("Creating pull request")
-	return r.VersionController.CreatePullRequest(ctx, repo, prRepo, scm.NewPullRequest{
+	pr, err := r.VersionController.CreatePullRequest(ctx, repo, prRepo, scm.NewPullRequest{
 		Title:         prTitle,
 		Body:          prBody,
 		Head:          r.FeatureBranch,
 		Base:          baseBranch,
-		Reviewers:     getReviewers(r.Reviewers, r.MaxReviewers),
-		TeamReviewers: getReviewers(r.TeamReviewers, r.MaxTeamReviewers),
+		Reviewers:     reviewers.users,
+		TeamReviewers: reviewers.teams,
 		Assignees:     r.Assignees,
 		Draft:         r.Draft,
 		AutoMerge:     r.AutoMerge,
 		Labels:        r.Labels,
 	})
+	if err != nil {
+		return nil, err
+	}
+	reviewers.requested()
+
+	return pr, nil
 }
 
 var interactiveInfo = `(V)iew changes. (A)ccept or (R)eject`
@@ -8,6 +8,7 @@ import (
 	"syscall"
 
 	"github.com/lindell/multi-gitter/internal/git"
+	"github.com/lindell/multi-gitter/internal/scm"
 	"github.com/pkg/errors"
 )
 
@@ -38,6 +39,69 @@ type Git interface {
 	ChangesSinceCommit(sinceCommitHash string) ([]git.Changes, error)
 }
 
+// GitBranchUpdater is implemented by git implementations that can update the current branch with the changes of a remote branch
+type GitBranchUpdater interface {
+	// UpdateBranch fetches a branch from the remote and merges it into the current branch, or rebases the current branch onto it.
+	// git.ErrConflict is returned if the branches conflict, in which case the current branch is left unchanged
+	UpdateBranch(ctx context.Context, remoteName, branchName string, rebase bool, commitAuthor *git.CommitAuthor) error
+}
+
+// GitDiffer is implemented by git implementations that can show the changes between two references of a remote repository
+type GitDiffer interface {
+	// Diff fetches the base and head references of the repository at url, and returns the changes made on the head since it diverged from the base
+	Diff(ctx context.Context, url, baseRef, headRef string) (string, error)
+}
+
+// getPullRequests fetches all pull requests of a feature branch, if include is set, only
+// the pull requests it returns true for are returned
+func getPullRequests(ctx context.Context, vc VersionController, featureBranch string, include func(pr scm.PullRequest) bool) ([]scm.PullRequest, error) {
+	prs, err := vc.GetPullRequests(ctx, featureBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	if include == nil {
+		return prs, nil
+	}
+
+	included := make([]scm.PullRequest, 0, len(prs))
+	for _, pr := range prs {
+		if include(pr) {
+			included = append(included, pr)
+		}
+	}
+	return included, nil
+}
+
+// repositoryFinder finds the repositories that pull requests target. The repositories are only fetched when they are first needed
+type repositoryFinder struct {
+	vc    VersionController
+	repos map[string]scm.Repository
+}
+
+func (f *repositoryFinder) find(ctx context.Context, pr scm.PullRequest) (scm.Repository, error) {
+	if f.repos == nil {
+		repos, err := f.vc.GetRepositories(ctx)
+		if err != nil {
+			return nil, errors.WithMessage(err, "could not get the repositories")
+		}
+		f.repos = map[string]scm.Repository{}
+		for _, repo := range repos {
+			f.repos[repo.FullName()] = repo
+		}
+	}
+
+	p, ok := pr.(scm.PullRequestWithRepository)
+	if !ok {
+		return nil, errors.New("the repository of the pull request is not known")
+	}
+	repo, ok := f.repos[p.RepositoryName()]
+	if !ok {
+		return nil, errors.Errorf("could not find the repository %s", p.RepositoryName())
+	}
+	return repo, nil
+}
+
 type stackTracer interface {
 	StackTrace() errors.StackTrace
 }
@@ -2,35 +2,290 @@ package multigitter
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/lindell/multi-gitter/internal/multigitter/terminal"
+	"github.com/lindell/multi-gitter/internal/scm"
 )
 
+// StatusFormat is the format the statuses of pull requests are printed in
+type StatusFormat int
+
+const (
+	// StatusFormatText prints one line with the name and status of each pull request
+	StatusFormatText StatusFormat = iota
+	// StatusFormatTable prints a table with the details of each pull request
+	StatusFormatTable
+	// StatusFormatJSON prints a JSON array with the details of each pull request
+	StatusFormatJSON
+	// StatusFormatCSV prints a CSV file with the details of each pull request
+	StatusFormatCSV
+)
+
+// ParseStatusFormat parses a status format from a string
+func ParseStatusFormat(str string) (StatusFormat, error) {
+	switch strings.ToLower(str) {
+	case "", "text":
+		return StatusFormatText, nil
+	case "table":
+		return StatusFormatTable, nil
+	case "json":
+		return StatusFormatJSON, nil
+	case "csv":
+		return StatusFormatCSV, nil
+	}
+	return StatusFormatText, fmt.Errorf(`not a valid status format: "%s"`, str)
+}
+
 // Statuser checks the statuses of pull requests
 type Statuser struct {
 	VersionController VersionController
 
 	Output io.Writer
+	Format StatusFormat
 
 	FeatureBranch string
+
+	PullRequestSelection
+
+	// Watch makes the statuses be polled every WatchInterval until all pull requests have settled,
+	// or until WatchTimeout has passed if it's set
+	Watch         bool
+	WatchInterval time.Duration
+	WatchTimeout  time.Duration
 }
 
 // Statuses checks the statuses of pull requests
 func (s Statuser) Statuses(ctx context.Context) error {
-	prs, err := s.VersionController.GetPullRequests(ctx, s.FeatureBranch)
+	include, err := includeFilteredRepositories(ctx, s.VersionController, s.PullRequestSelection)
 	if err != nil {
 		return err
 	}
+	s.IncludePullRequest = include
+
+	if s.Watch {
+		return s.watch(ctx)
+	}
+
+	prs, err := getPullRequests(ctx, s.VersionController, s.FeatureBranch, s.IncludePullRequest)
+	if err != nil {
+		return err
+	}
+
+	return s.print(s.Output, prs)
+}
+
+func (s Statuser) print(w io.Writer, prs []scm.PullRequest) error {
+	switch s.Format {
+	case StatusFormatTable:
+		return printTable(w, prs)
+	case StatusFormatJSON:
+		return printJSON(w, prs)
+	case StatusFormatCSV:
+		return printCSV(w, prs)
+	}
+	return printText(w, prs)
+}
 
+func printText(w io.Writer, prs []scm.PullRequest) error {
 	for _, pr := range prs {
+		var err error
 		if urler, hasURL := pr.(urler); hasURL && urler.URL() != "" {
-			fmt.Fprintf(s.Output, "%s: %s\n", terminal.Link(pr.String(), urler.URL()), pr.Status())
+			_, err = fmt.Fprintf(w, "%s: %s\n", terminal.Link(pr.String(), urler.URL()), pr.Status())
 		} else {
-			fmt.Fprintf(s.Output, "%s: %s\n", pr.String(), pr.Status())
+			_, err = fmt.Fprintf(w, "%s: %s\n", pr.String(), pr.Status())
+		}
+		if err != nil {
+			return err
 		}
 	}
-
 	return nil
 }
+
+// pullRequestStatus is the status and details of a pull request, as it's printed in the structured formats
+type pullRequestStatus struct {
+	PullRequest    string        `json:"pull_request"`
+	Status         string        `json:"status"`
+	URL            string        `json:"url,omitempty"`
+	Number         int           `json:"number,omitempty"`
+	Title          string        `json:"title,omitempty"`
+	Author         string        `json:"author,omitempty"`
+	CreatedAt      *time.Time    `json:"created_at,omitempty"`
+	UpdatedAt      *time.Time    `json:"updated_at,omitempty"`
+	Draft          bool          `json:"draft"`
+	ReviewDecision string        `json:"review_decision,omitempty"`
+	Mergeability   string        `json:"mergeability,omitempty"`
+	Labels         []string      `json:"labels"`
+	Checks         []checkStatus `json:"checks"`
+	details        scm.PullRequestDetails
+}
+
+type checkStatus struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	URL    string `json:"url,omitempty"`
+}
+
+func newPullRequestStatus(pr scm.PullRequest) pullRequestStatus {
+	details := scm.GetPullRequestDetails(pr)
+
+	status := pullRequestStatus{
+		PullRequest:    pr.String(),
+		Status:         pr.Status().String(),
+		Number:         details.Number,
+		Title:          details.Title,
+		Author:         details.Author,
+		Draft:          details.Draft,
+		ReviewDecision: string(details.ReviewDecision),
+		Mergeability:   string(details.Mergeability),
+		Labels:         details.Labels,
+		Checks:         make([]checkStatus, 0, len(details.Checks)),
+		details:        details,
+	}
+	if urler, hasURL := pr.(urler); hasURL {
+		status.URL = urler.URL()
+	}
+	if !details.CreatedAt.IsZero() {
+		status.CreatedAt = &details.CreatedAt
+	}
+	if !details.UpdatedAt.IsZero() {
+		status.UpdatedAt = &details.UpdatedAt
+	}
+	if status.Labels == nil {
+		status.Labels = []string{}
+	}
+	for _, check := range details.Checks {
+		status.Checks = append(status.Checks, checkStatus{
+			Name:   check.Name,
+			Status: string(check.Status),
+			URL:    check.URL,
+		})
+	}
+	return status
+}
+
+func printJSON(w io.Writer, prs []scm.PullRequest) error {
+	statuses := make([]pullRequestStatus, 0, len(prs))
+	for _, pr := range prs {
+		statuses = append(statuses, newPullRequestStatus(pr))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(statuses)
+}
+
+var csvHeader = []string{
+	"pull_request", "status", "url", "number", "title", "author", "created_at", "updated_at",
+	"draft", "review_decision", "mergeability", "labels", "checks",
+}
+
+func printCSV(output io.Writer, prs []scm.PullRequest) error {
+	w := csv.NewWriter(output)
+	if err := w.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, pr := range prs {
+		status := newPullRequestStatus(pr)
+
+		checks := make([]string, 0, len(status.Checks))
+		for _, check := range status.Checks {
+			checks = append(checks, check.Name+"="+check.Status)
+		}
+
+		var number string
+		if status.Number != 0 {
+			number = strconv.Itoa(status.Number)
+		}
+
+		err := w.Write([]string{
+			status.PullRequest,
+			status.Status,
+			status.URL,
+			number,
+			status.Title,
+			status.Author,
+			formatTime(status.details.CreatedAt, time.RFC3339),
+			formatTime(status.details.UpdatedAt, time.RFC3339),
+			strconv.FormatBool(status.Draft),
+			status.ReviewDecision,
+			status.Mergeability,
+			strings.Join(status.Labels, ";"),
+			strings.Join(checks, ";"),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+func printTable(output io.Writer, prs []scm.PullRequest) error {
+	w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PULL REQUEST\tSTATUS\tTITLE\tAUTHOR\tCHECKS\tREVIEW\tMERGEABLE\tLABELS\tUPDATED\tURL")
+
+	for _, pr := range prs {
+		status := newPullRequestStatus(pr)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			status.PullRequest,
+			status.Status,
+			orDash(status.Title),
+			orDash(status.Author),
+			orDash(checkSummary(status.details.Checks)),
+			orDash(status.ReviewDecision),
+			orDash(status.Mergeability),
+			orDash(strings.Join(status.Labels, ",")),
+			orDash(formatTime(status.details.UpdatedAt, "2006-01-02 15:04")),
+			orDash(status.URL),
+		)
+	}
+
+	return w.Flush()
+}
+
+// checkSummary summarizes the results of checks, for example "3/5 passed, 1 failed"
+func checkSummary(checks []scm.Check) string {
+	if len(checks) == 0 {
+		return ""
+	}
+
+	var passed, failed int
+	for _, check := range checks {
+		switch check.Status {
+		case scm.CheckStatusSuccess:
+			passed++
+		case scm.CheckStatusFailure:
+			failed++
+		}
+	}
+
+	summary := fmt.Sprintf("%d/%d passed", passed, len(checks))
+	if failed > 0 {
+		summary += fmt.Sprintf(", %d failed", failed)
+	}
+	return summary
+}
+
+func formatTime(t time.Time, layout string) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(layout)
+}
+
+func orDash(str string) string {
+	if str == "" {
+		return "-"
+	}
+	return str
+}
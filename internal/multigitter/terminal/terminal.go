@@ -1,6 +1,10 @@
 package terminal
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+	"os"
+)
 
 // Printer formats things to the terminal
 type Printer struct {
@@ -28,6 +32,25 @@ func (t *Printer) Bold(text string) string {
 	return fmt.Sprintf("\033[1m%s\033[0m", text)
 }
 
+// ClearScreen generates the sequence that clears the terminal and moves the cursor to the top left corner
+func (t *Printer) ClearScreen() string {
+	if t.Plain {
+		return ""
+	}
+
+	return "\033[H\033[2J"
+}
+
+// IsTerminal returns true if the writer is a terminal, and not for example a file or a pipe
+func IsTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
 // Link generates a link in that can be displayed in the terminal using the default terminal printer
 func Link(text, url string) string {
 	return DefaultPrinter.Link(text, url)
@@ -37,3 +60,8 @@ func Link(text, url string) string {
 func Bold(text string) string {
 	return DefaultPrinter.Bold(text)
 }
+
+// ClearScreen generates the sequence that clears the terminal using the default terminal printer
+func ClearScreen() string {
+	return DefaultPrinter.ClearScreen()
+}
@@ -0,0 +1,123 @@
+package multigitter
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/lindell/multi-gitter/internal/git"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// BranchUpdater updates the branches of pull requests with the latest changes of their base branch
+type BranchUpdater struct {
+	VersionController VersionController
+
+	// Output is where the result of updating the pull requests is written
+	Output io.Writer
+
+	FeatureBranch string
+
+	PullRequestSelection
+
+	// Rebase rebases the branches onto the base branch instead of merging the base branch into them
+	Rebase bool
+
+	// The fields below are used when the platform can't update a pull request through its API,
+	// in which case the branch is cloned, updated and pushed
+
+	// BaseBranch is the branch the pull requests are updated with, the default branch of each repository is used if empty
+	BaseBranch   string
+	CommitAuthor *git.CommitAuthor
+	CloneDir     string
+	CreateGit    func(dir string) Git
+
+	// DryRun lists the pull requests that would be updated, without updating them
+	DryRun bool
+}
+
+// Update updates the branch of each open pull request. A pull request that fails to update does not stop the others from being updated
+func (s BranchUpdater) Update(ctx context.Context) error {
+	// The repositories are only fetched if a pull request has to be updated locally
+	repoFinder := &repositoryFinder{vc: s.VersionController}
+
+	return pullRequestAction{
+		dryRun:        s.DryRun,
+		dryRunTitle:   "Pull requests that would be updated",
+		dryRunMessage: "Would update %d pull requests",
+		message:       "Updating %d pull requests",
+		do: func(ctx context.Context, pr scm.PullRequest, log log.FieldLogger) (pullRequestOutcome, string) {
+			log.Infof("Updating")
+			err := s.updatePullRequest(ctx, pr, repoFinder)
+			switch {
+			case errors.Is(err, git.ErrConflict):
+				log.Infof("The branch conflicts with the base branch")
+				return outcomeSkipped, ""
+			case err != nil:
+				log.Errorf("Error occurred while updating: %s", err.Error())
+				return outcomeFailed, err.Error()
+			}
+			return outcomeDone, ""
+		},
+		doneTitle:     "Updated pull requests",
+		skippedTitle:  "Pull requests with conflicts",
+		failedTitle:   "Pull requests that could not be updated",
+		skippedFailed: true,
+		failedMessage: "%d of %d pull requests could not be updated",
+	}.run(ctx, s.VersionController, s.Output, s.FeatureBranch, s.PullRequestSelection)
+}
+
+// updatePullRequest updates a single pull request through the API of the platform, or locally if the platform can't do it
+func (s BranchUpdater) updatePullRequest(ctx context.Context, pr scm.PullRequest, repoFinder *repositoryFinder) error {
+	if branchUpdater, ok := s.VersionController.(VersionControllerUpdatePullRequestBranch); ok {
+		err := branchUpdater.UpdatePullRequestBranch(ctx, pr, s.Rebase)
+		if !errors.Is(err, scm.ErrUpdateMethodNotSupported) {
+			return err
+		}
+	}
+
+	log.WithField("pr", pr.String()).Debug("The platform can't update the pull request, updating it locally")
+
+	repo, err := repoFinder.find(ctx, pr)
+	if err != nil {
+		return err
+	}
+	return s.updateBranchLocally(ctx, repo)
+}
+
+// updateBranchLocally clones the feature branch of a repository, updates it with the base branch and pushes it
+func (s BranchUpdater) updateBranchLocally(ctx context.Context, repo scm.Repository) error {
+	if s.CreateGit == nil {
+		return errors.New("the platform can't update the pull request")
+	}
+
+	tmpDir, err := createTempDir(s.CloneDir)
+	defer os.RemoveAll(tmpDir)
+	if err != nil {
+		return err
+	}
+
+	sourceController := s.CreateGit(tmpDir)
+	branchUpdater, ok := sourceController.(GitBranchUpdater)
+	if !ok {
+		return errors.New("the git implementation can't update branches")
+	}
+
+	if err := sourceController.Clone(ctx, repo.CloneURL(), s.FeatureBranch); err != nil {
+		return errors.WithMessage(err, "could not clone the branch")
+	}
+
+	baseBranch := s.BaseBranch
+	if baseBranch == "" {
+		baseBranch = repo.DefaultBranch()
+	}
+
+	if err := branchUpdater.UpdateBranch(ctx, "origin", baseBranch, s.Rebase, s.CommitAuthor); err != nil {
+		return err
+	}
+
+	// A rebased branch has a rewritten history, and has to be force pushed
+	return sourceController.Push(ctx, "origin", s.FeatureBranch, s.Rebase)
+}
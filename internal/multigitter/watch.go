@@ -0,0 +1,163 @@
+package multigitter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/lindell/multi-gitter/internal/multigitter/terminal"
+	"github.com/lindell/multi-gitter/internal/scm"
+)
+
+const defaultWatchInterval = time.Minute
+
+// settledStatus returns true if a pull request with the status will not change without any manual action
+func settledStatus(status scm.PullRequestStatus) bool {
+	switch status {
+	case scm.PullRequestStatusMerged, scm.PullRequestStatusClosed:
+		return true
+	}
+	return failedStatus(status)
+}
+
+// failedStatus returns true if a pull request with the status can not be merged without changes to it
+func failedStatus(status scm.PullRequestStatus) bool {
+	switch status {
+	case scm.PullRequestStatusError, scm.PullRequestStatusConflicting, scm.PullRequestStatusChangesRequested:
+		return true
+	}
+	return false
+}
+
+// watch polls the pull requests and prints a summary of them until they have all settled
+func (s Statuser) watch(ctx context.Context) error {
+	if s.Format != StatusFormatText && s.Format != StatusFormatTable {
+		return errors.New("watching statuses is only supported with the text and table formats")
+	}
+
+	interval := s.WatchInterval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+
+	var timeout <-chan time.Time
+	if s.WatchTimeout > 0 {
+		timer := time.NewTimer(s.WatchTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var previous map[string]scm.PullRequestStatus
+	for {
+		prs, err := getPullRequests(ctx, s.VersionController, s.FeatureBranch, s.IncludePullRequest)
+		if err != nil && previous == nil {
+			return err
+		} else if err != nil {
+			// Keep the last summary and try again, a failed poll should not abort a long-running watch
+			log.Warnf("Could not get the pull requests: %s", err)
+		} else {
+			if err := s.printWatchSummary(prs, previous); err != nil {
+				return err
+			}
+
+			previous = make(map[string]scm.PullRequestStatus, len(prs))
+			for _, pr := range prs {
+				previous[pr.String()] = pr.Status()
+			}
+
+			if allSettled(prs) {
+				return watchResult(prs)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout:
+			var unsettled int
+			for _, status := range previous {
+				if !settledStatus(status) {
+					unsettled++
+				}
+			}
+			return errors.Errorf("timed out after %s with %d pull requests that have not settled", s.WatchTimeout, unsettled)
+		case <-time.After(interval):
+		}
+	}
+}
+
+// printWatchSummary prints the number of pull requests in each status, followed by each pull request.
+// Pull requests that have changed status since the previous poll are highlighted
+func (s Statuser) printWatchSummary(prs []scm.PullRequest, previous map[string]scm.PullRequestStatus) error {
+	counts := map[scm.PullRequestStatus]int{}
+	for _, pr := range prs {
+		counts[pr.Status()]++
+	}
+	var summary []string
+	for _, status := range scm.AllPullRequestStatuses {
+		if counts[status] > 0 {
+			summary = append(summary, fmt.Sprintf("%s: %d", status, counts[status]))
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := s.print(buf, prs); err != nil {
+		return err
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+
+	// The table format has a header line before the pull requests
+	offset := 0
+	if s.Format == StatusFormatTable {
+		offset = 1
+	}
+	for i, pr := range prs {
+		status, ok := previous[pr.String()]
+		if previous != nil && (!ok || status != pr.Status()) && i+offset < len(lines) {
+			lines[i+offset] = terminal.Bold(lines[i+offset])
+		}
+	}
+
+	// The previous summary is only replaced in a terminal, written to a file all summaries are kept
+	clearScreen := ""
+	if terminal.IsTerminal(s.Output) {
+		clearScreen = terminal.ClearScreen()
+	}
+
+	_, err := fmt.Fprintf(s.Output, "%s%d pull requests at %s (%s)\n\n%s\n\n",
+		clearScreen,
+		len(prs),
+		time.Now().Format("15:04:05"),
+		strings.Join(summary, ", "),
+		strings.Join(lines, "\n"),
+	)
+	return err
+}
+
+func allSettled(prs []scm.PullRequest) bool {
+	for _, pr := range prs {
+		if !settledStatus(pr.Status()) {
+			return false
+		}
+	}
+	return true
+}
+
+// watchResult returns an error if any of the settled pull requests has failed
+func watchResult(prs []scm.PullRequest) error {
+	var failed []string
+	for _, pr := range prs {
+		if failedStatus(pr.Status()) {
+			failed = append(failed, pr.String())
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("%d pull requests failed: %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,453 @@
+package repofilter
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+)
+
+// Expression is a filter defined by a boolean expression, for example:
+//
+//	language == go && topics contains backend && !archived && owner != team-x
+//
+// Conditions can be combined with && (and), || (or), ! (not) and parentheses. The available fields are:
+//
+//	name        string  the full name of the repository, usually owner/repo
+//	owner       string  everything before the last / of the full name
+//	repo        string  everything after the last / of the full name
+//	language    string  the primary language
+//	visibility  string  public, private or internal
+//	topics      list    the topics of the repository
+//	archived    bool
+//	fork        bool
+//	pushed      time    the last time something was pushed to the repository
+//	size        size    the size of the repository, for example 500 or 20MB
+//
+// Strings can be compared with ==, != (case insensitive), =~ and !~ (regular expressions). Lists can be
+// checked with contains. Booleans can be used by themselves or compared with == and !=. Times and sizes
+// can be compared with ==, !=, <, <=, > and >=, a comparison with an unknown time or size never matches.
+// Values can be quoted with " or ' if they contain spaces or operators.
+type Expression struct {
+	root node
+}
+
+// ParseExpression parses a filter expression
+func ParseExpression(expr string) (*Expression, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.typ != tokenEOF {
+		return nil, errors.Errorf(`unexpected "%s" at position %d`, tok.value, tok.pos)
+	}
+
+	return &Expression{root: root}, nil
+}
+
+// Match returns true if the repository matches the expression
+func (e *Expression) Match(repo scm.Repository) bool {
+	return e.root.match(newRepoValues(repo))
+}
+
+// SkipReason returns why the repository does not match the expression, which makes Expression usable as a Filter
+func (e *Expression) SkipReason(repo scm.Repository) string {
+	if !e.Match(repo) {
+		return "it does not match the filter"
+	}
+	return ""
+}
+
+// repoValues are the values of a repository that can be used in an expression
+type repoValues struct {
+	name  string
+	owner string
+	repo  string
+	scm.RepositoryMetadata
+}
+
+func newRepoValues(repo scm.Repository) repoValues {
+	values := repoValues{
+		name:               repo.FullName(),
+		repo:               repo.FullName(),
+		RepositoryMetadata: scm.GetRepositoryMetadata(repo),
+	}
+	if i := strings.LastIndex(values.name, "/"); i >= 0 {
+		values.owner = values.name[:i]
+		values.repo = values.name[i+1:]
+	}
+	return values
+}
+
+type fieldType int
+
+const (
+	fieldString fieldType = iota
+	fieldList
+	fieldBool
+	fieldTime
+	fieldSize
+)
+
+type field struct {
+	typ   fieldType
+	value func(v repoValues) interface{}
+}
+
+var fields = map[string]field{
+	"name":       {fieldString, func(v repoValues) interface{} { return v.name }},
+	"owner":      {fieldString, func(v repoValues) interface{} { return v.owner }},
+	"repo":       {fieldString, func(v repoValues) interface{} { return v.repo }},
+	"language":   {fieldString, func(v repoValues) interface{} { return v.Language }},
+	"visibility": {fieldString, func(v repoValues) interface{} { return string(v.Visibility) }},
+	"topics":     {fieldList, func(v repoValues) interface{} { return v.Topics }},
+	"archived":   {fieldBool, func(v repoValues) interface{} { return v.Archived }},
+	"fork":       {fieldBool, func(v repoValues) interface{} { return v.Fork }},
+	"pushed":     {fieldTime, func(v repoValues) interface{} { return v.PushedAt }},
+	"size":       {fieldSize, func(v repoValues) interface{} { return v.Size }},
+}
+
+// operatorsByType are the operators that can be used with each type of field
+var operatorsByType = map[fieldType][]string{
+	fieldString: {"==", "!=", "=~", "!~"},
+	fieldList:   {"contains"},
+	fieldBool:   {"==", "!="},
+	fieldTime:   {"==", "!=", "<", "<=", ">", ">="},
+	fieldSize:   {"==", "!=", "<", "<=", ">", ">="},
+}
+
+type node interface {
+	match(v repoValues) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) match(v repoValues) bool { return n.left.match(v) && n.right.match(v) }
+
+type orNode struct{ left, right node }
+
+func (n orNode) match(v repoValues) bool { return n.left.match(v) || n.right.match(v) }
+
+type notNode struct{ node node }
+
+func (n notNode) match(v repoValues) bool { return !n.node.match(v) }
+
+// conditionNode compares a field of the repository with a value
+type conditionNode struct {
+	field field
+	op    string
+	value interface{}
+}
+
+func (n conditionNode) match(v repoValues) bool {
+	actual := n.field.value(v)
+	switch n.field.typ {
+	case fieldString:
+		actual := actual.(string)
+		switch n.op {
+		case "==":
+			return strings.EqualFold(actual, n.value.(string))
+		case "!=":
+			return !strings.EqualFold(actual, n.value.(string))
+		case "=~":
+			return n.value.(*regexp.Regexp).MatchString(actual)
+		case "!~":
+			return !n.value.(*regexp.Regexp).MatchString(actual)
+		}
+	case fieldList:
+		return slices.ContainsFunc(actual.([]string), func(s string) bool {
+			return strings.EqualFold(s, n.value.(string))
+		})
+	case fieldBool:
+		return (actual.(bool) == n.value.(bool)) == (n.op == "==")
+	case fieldTime:
+		if actual.(time.Time).IsZero() {
+			return false
+		}
+		return compare(actual.(time.Time).Compare(n.value.(time.Time)), n.op)
+	case fieldSize:
+		actual, value := actual.(int64), n.value.(int64)
+		switch {
+		case actual == 0:
+			return false
+		case actual < value:
+			return compare(-1, n.op)
+		case actual > value:
+			return compare(1, n.op)
+		}
+		return compare(0, n.op)
+	}
+	return false
+}
+
+// compare returns if the result of a comparison (-1, 0 or 1) fulfills the operator
+func compare(result int, op string) bool {
+	switch op {
+	case "==":
+		return result == 0
+	case "!=":
+		return result != 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	}
+	return false
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.typ != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is("||", "or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is("&&", "and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.peek().is("!", "not") {
+		p.next()
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{node: n}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch {
+	case tok.typ == tokenEOF:
+		return nil, errors.New("unexpected end of the expression")
+	case tok.is("("):
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); !closing.is(")") {
+			return nil, errors.Errorf(`expected ")" at position %d`, closing.pos)
+		}
+		return n, nil
+	case tok.typ != tokenWord:
+		return nil, errors.Errorf(`unexpected "%s" at position %d`, tok.value, tok.pos)
+	}
+
+	f, ok := fields[tok.value]
+	if !ok {
+		return nil, errors.Errorf(`unknown field "%s" at position %d`, tok.value, tok.pos)
+	}
+
+	opTok := p.peek()
+	if !opTok.is(comparisonOperators...) {
+		if f.typ == fieldBool {
+			return conditionNode{field: f, op: "==", value: true}, nil
+		}
+		return nil, errors.Errorf(`the field "%s" at position %d has to be compared to a value`, tok.value, tok.pos)
+	}
+	p.next()
+	if !slices.Contains(operatorsByType[f.typ], opTok.value) {
+		return nil, errors.Errorf(`"%s" can't be used with the field "%s" at position %d, use one of %s`,
+			opTok.value, tok.value, opTok.pos, strings.Join(operatorsByType[f.typ], ", "))
+	}
+
+	valueTok := p.next()
+	if valueTok.typ != tokenWord && valueTok.typ != tokenString {
+		return nil, errors.Errorf(`expected a value at position %d`, valueTok.pos)
+	}
+	value, err := parseValue(f.typ, opTok.value, valueTok.value)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "invalid value at position %d", valueTok.pos)
+	}
+
+	return conditionNode{field: f, op: opTok.value, value: value}, nil
+}
+
+func parseValue(typ fieldType, op, value string) (interface{}, error) {
+	switch typ {
+	case fieldString:
+		if op == "=~" || op == "!~" {
+			return regexp.Compile(value)
+		}
+		return value, nil
+	case fieldBool:
+		return strconv.ParseBool(value)
+	case fieldTime:
+		return ParseTime(value)
+	case fieldSize:
+		return parseSize(value)
+	}
+	return value, nil
+}
+
+// ParseTime parses either a date or a RFC 3339 formatted time
+func ParseTime(str string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, str); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, str)
+	if err != nil {
+		return time.Time{}, errors.Errorf(`"%s" is neither a date (2006-01-02) nor a time (2006-01-02T15:04:05Z07:00)`, str)
+	}
+	return t, nil
+}
+
+var sizeUnits = map[string]int64{
+	"":   1,
+	"b":  1,
+	"kb": 1 << 10,
+	"mb": 1 << 20,
+	"gb": 1 << 30,
+}
+
+// parseSize parses a size in bytes, with an optional unit (KB, MB or GB)
+func parseSize(str string) (int64, error) {
+	i := strings.IndexFunc(str, func(r rune) bool { return !unicode.IsDigit(r) })
+	if i < 0 {
+		i = len(str)
+	}
+	unit, ok := sizeUnits[strings.ToLower(str[i:])]
+	if i == 0 || !ok {
+		return 0, errors.Errorf(`"%s" is not a size, use a number of bytes optionally followed by KB, MB or GB`, str)
+	}
+	n, err := strconv.ParseInt(str[:i], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return n * unit, nil
+}
+
+type tokenType int
+
+const (
+	tokenEOF tokenType = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenParen
+)
+
+type token struct {
+	typ   tokenType
+	value string
+	pos   int
+}
+
+// is returns true if the token is an operator, parenthesis or unquoted word with one of the values
+func (t token) is(values ...string) bool {
+	return t.typ != tokenString && t.typ != tokenEOF && slices.Contains(values, t.value)
+}
+
+// operators that can be used in expressions, longer operators has to be listed before their prefixes
+var operators = []string{"&&", "||", "==", "!=", "=~", "!~", "<=", ">=", "<", ">", "!"}
+
+// comparisonOperators are the operators that compare a field with a value
+var comparisonOperators = []string{"==", "!=", "=~", "!~", "<=", ">=", "<", ">", "contains"}
+
+// wordBreaks are the characters that end an unquoted word
+const wordBreaks = `()!=<>&|~"'`
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, token{typ: tokenParen, value: string(c), pos: i})
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(expr[i+1:], c)
+			if end < 0 {
+				return nil, errors.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, token{typ: tokenString, value: expr[i+1 : i+1+end], pos: i})
+			i += end + 2
+		default:
+			if op := operatorPrefix(expr[i:]); op != "" {
+				tokens = append(tokens, token{typ: tokenOperator, value: op, pos: i})
+				i += len(op)
+				continue
+			}
+			if strings.IndexByte(wordBreaks, c) >= 0 {
+				return nil, errors.Errorf(`unexpected "%c" at position %d`, c, i)
+			}
+			end := strings.IndexFunc(expr[i:], func(r rune) bool {
+				return unicode.IsSpace(r) || strings.ContainsRune(wordBreaks, r)
+			})
+			if end < 0 {
+				end = len(expr) - i
+			}
+			tokens = append(tokens, token{typ: tokenWord, value: expr[i : i+end], pos: i})
+			i += end
+		}
+	}
+	return append(tokens, token{typ: tokenEOF, pos: len(expr)}), nil
+}
+
+func operatorPrefix(s string) string {
+	for _, op := range operators {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
@@ -0,0 +1,157 @@
+package repofilter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+)
+
+type testRepository struct {
+	name     string
+	metadata scm.RepositoryMetadata
+}
+
+func (r testRepository) CloneURL() string                 { return "" }
+func (r testRepository) DefaultBranch() string            { return "main" }
+func (r testRepository) FullName() string                 { return r.name }
+func (r testRepository) Metadata() scm.RepositoryMetadata { return r.metadata }
+func repo(name string, metadata scm.RepositoryMetadata) scm.Repository {
+	return testRepository{name, metadata}
+}
+
+func TestExpression(t *testing.T) {
+	goBackend := repo("team-a/service", scm.RepositoryMetadata{
+		Language:   "Go",
+		Visibility: scm.VisibilityPrivate,
+		Topics:     []string{"backend", "api"},
+		PushedAt:   time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		Size:       2 << 20,
+	})
+	archivedGoBackend := repo("team-a/old-service", scm.RepositoryMetadata{
+		Language: "Go",
+		Topics:   []string{"backend"},
+		Archived: true,
+	})
+	teamXBackend := repo("team-x/service", scm.RepositoryMetadata{
+		Language: "go",
+		Topics:   []string{"backend"},
+	})
+	javaFrontend := repo("group/sub/frontend", scm.RepositoryMetadata{
+		Language: "Java",
+		Topics:   []string{"frontend"},
+		Fork:     true,
+	})
+	all := []scm.Repository{goBackend, archivedGoBackend, teamXBackend, javaFrontend}
+
+	tests := []struct {
+		expr string
+		want []scm.Repository
+	}{
+		{
+			expr: `language == go && topics contains backend && !archived && owner != team-x`,
+			want: []scm.Repository{goBackend},
+		},
+		{
+			expr: `language == "Go" and topics contains "backend" and not archived and not owner == "team-x"`,
+			want: []scm.Repository{goBackend},
+		},
+		{
+			expr: `archived || fork`,
+			want: []scm.Repository{archivedGoBackend, javaFrontend},
+		},
+		{
+			expr: `!(archived || fork)`,
+			want: []scm.Repository{goBackend, teamXBackend},
+		},
+		{
+			expr: `archived == false && fork != true`,
+			want: []scm.Repository{goBackend, teamXBackend},
+		},
+		{
+			expr: `fork || language == go && repo == service`,
+			want: []scm.Repository{goBackend, teamXBackend, javaFrontend},
+		},
+		{
+			expr: `name =~ '^team-[a-z]/service$'`,
+			want: []scm.Repository{goBackend, teamXBackend},
+		},
+		{
+			expr: `name !~ ^team-`,
+			want: []scm.Repository{javaFrontend},
+		},
+		{
+			expr: `owner == group/sub && repo == frontend`,
+			want: []scm.Repository{javaFrontend},
+		},
+		{
+			expr: `visibility == private`,
+			want: []scm.Repository{goBackend},
+		},
+		{
+			expr: `pushed > 2025-01-01`,
+			want: []scm.Repository{goBackend},
+		},
+		{
+			expr: `pushed < 2025-03-01T13:00:00+00:00`,
+			want: []scm.Repository{goBackend},
+		},
+		{
+			expr: `pushed < 2025-03-01`,
+			want: nil,
+		},
+		{
+			expr: `size >= 2MB && size < 3mb`,
+			want: []scm.Repository{goBackend},
+		},
+		{
+			expr: `size < 1KB`,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := ParseExpression(tt.expr)
+			require.NoError(t, err)
+
+			var got []scm.Repository
+			for _, r := range all {
+				if expr.Match(r) {
+					got = append(got, r)
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExpressionErrors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{expr: ``, wantErr: "unexpected end of the expression"},
+		{expr: `language ==`, wantErr: "expected a value at position 11"},
+		{expr: `colour == blue`, wantErr: `unknown field "colour" at position 0`},
+		{expr: `language`, wantErr: `the field "language" at position 0 has to be compared to a value`},
+		{expr: `topics == backend`, wantErr: `"==" can't be used with the field "topics" at position 7, use one of contains`},
+		{expr: `language < go`, wantErr: `"<" can't be used with the field "language" at position 9`},
+		{expr: `(archived || fork`, wantErr: `expected ")" at position 17`},
+		{expr: `archived fork`, wantErr: `unexpected "fork" at position 9`},
+		{expr: `name == "abc`, wantErr: "unterminated string at position 8"},
+		{expr: `name = abc`, wantErr: `unexpected "=" at position 5`},
+		{expr: `name =~ "(abc"`, wantErr: "invalid value at position 8"},
+		{expr: `pushed > yesterday`, wantErr: `"yesterday" is neither a date`},
+		{expr: `size > 5TB`, wantErr: `"5TB" is not a size`},
+		{expr: `archived == maybe`, wantErr: "invalid value at position 12"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseExpression(tt.expr)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
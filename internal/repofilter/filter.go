@@ -0,0 +1,113 @@
+// Package repofilter contains filters that decide which repositories multi-gitter should be used on
+package repofilter
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+)
+
+// Filter decides if a repository should be included
+type Filter interface {
+	// SkipReason returns why the repository should be skipped, or an empty string if it should be included.
+	// The reason is used as "Skipping <repository> since <reason>"
+	SkipReason(repo scm.Repository) string
+}
+
+// SkipRepositories excludes the repositories with the listed full names (owner/repo)
+func SkipRepositories(names []string) Filter {
+	skipReposMap := map[string]struct{}{}
+	for _, name := range names {
+		skipReposMap[name] = struct{}{}
+	}
+	return skipRepositories(skipReposMap)
+}
+
+type skipRepositories map[string]struct{}
+
+func (f skipRepositories) SkipReason(repo scm.Repository) string {
+	if _, shouldSkip := f[repo.FullName()]; shouldSkip {
+		return "it is in exclusion list"
+	}
+	return ""
+}
+
+// IncludeRegexp only includes repositories where the full name matches the regular expression
+func IncludeRegexp(regExp *regexp.Regexp) Filter {
+	return includeRegexp{regExp: regExp}
+}
+
+type includeRegexp struct {
+	regExp *regexp.Regexp
+}
+
+func (f includeRegexp) SkipReason(repo scm.Repository) string {
+	if !f.regExp.MatchString(repo.FullName()) {
+		return "it does not match the inclusion regexp"
+	}
+	return ""
+}
+
+// ExcludeRegexp excludes repositories where the full name matches the regular expression
+func ExcludeRegexp(regExp *regexp.Regexp) Filter {
+	return excludeRegexp{regExp: regExp}
+}
+
+type excludeRegexp struct {
+	regExp *regexp.Regexp
+}
+
+func (f excludeRegexp) SkipReason(repo scm.Repository) string {
+	if f.regExp.MatchString(repo.FullName()) {
+		return "it match the exclusion regexp"
+	}
+	return ""
+}
+
+// Metadata filters repositories based on their metadata. Repositories where a filtered value is unknown are skipped
+type Metadata struct {
+	// Languages, when set, only repositories with one of the primary languages will be included
+	Languages []string
+
+	// Visibilities, when set, only repositories with one of the visibilities will be included
+	Visibilities []scm.Visibility
+
+	// PushedAfter, when set, only repositories pushed to after this time will be included
+	PushedAfter time.Time
+
+	// ExcludeArchived, when set, archived repositories will be excluded
+	ExcludeArchived bool
+}
+
+// IsZero returns true if the metadata filter does not filter anything
+func (f Metadata) IsZero() bool {
+	return len(f.Languages) == 0 && len(f.Visibilities) == 0 && f.PushedAfter.IsZero() && !f.ExcludeArchived
+}
+
+// SkipReason returns why the repository does not pass the metadata filter. Repositories with unknown metadata are
+// skipped when it's filtered on, since the platform does not report it
+func (f Metadata) SkipReason(repo scm.Repository) string {
+	metadata := scm.GetRepositoryMetadata(repo)
+	switch {
+	case f.ExcludeArchived && metadata.Archived:
+		return "it is archived"
+	case len(f.Languages) > 0 && metadata.Language == "":
+		return "its language is unknown"
+	case len(f.Languages) > 0 && !slices.ContainsFunc(f.Languages, func(lang string) bool {
+		return strings.EqualFold(lang, metadata.Language)
+	}):
+		return "its language is " + metadata.Language
+	case len(f.Visibilities) > 0 && metadata.Visibility == "":
+		return "its visibility is unknown"
+	case len(f.Visibilities) > 0 && !slices.Contains(f.Visibilities, metadata.Visibility):
+		return "its visibility is " + string(metadata.Visibility)
+	case !f.PushedAfter.IsZero() && metadata.PushedAt.IsZero():
+		return "the time of the last push is unknown"
+	case !f.PushedAfter.IsZero() && !metadata.PushedAt.After(f.PushedAfter):
+		return "it was last pushed to " + metadata.PushedAt.Format(time.RFC3339)
+	}
+	return ""
+}
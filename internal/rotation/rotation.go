@@ -0,0 +1,179 @@
+// Package rotation spreads review requests evenly over a pool of reviewers
+package rotation
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Rotation selects reviewers by always picking the ones that have been requested the fewest times.
+// Reviewers with the same number of requests are picked in the order they are listed, which makes the
+// selection deterministic
+type Rotation struct {
+	path        string
+	unavailable map[string]bool
+
+	mutex sync.Mutex
+	state state
+}
+
+// state is the number of times each user and team has been requested as a reviewer
+type state struct {
+	Users map[string]int `json:"users"`
+	Teams map[string]int `json:"teams"`
+}
+
+// New creates a rotation that is only kept in memory
+func New(unavailable []string) *Rotation {
+	r := &Rotation{
+		unavailable: map[string]bool{},
+		state: state{
+			Users: map[string]int{},
+			Teams: map[string]int{},
+		},
+	}
+	for _, reviewer := range unavailable {
+		r.unavailable[normalize(reviewer)] = true
+	}
+	return r
+}
+
+// Load creates a rotation that continues from the counts stored in the file at path.
+// If the file does not exist, the rotation starts from zero and the file is created when saved
+func Load(path string, unavailable []string) (*Rotation, error) {
+	r := New(unavailable)
+	r.path = path
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return r, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "could not read the reviewer rotation file %s", path)
+	}
+
+	if err := json.Unmarshal(data, &r.state); err != nil {
+		return nil, errors.Wrapf(err, "could not parse the reviewer rotation file %s", path)
+	}
+	if r.state.Users == nil {
+		r.state.Users = map[string]int{}
+	}
+	if r.state.Teams == nil {
+		r.state.Teams = map[string]int{}
+	}
+	return r, nil
+}
+
+// Save writes the counts to the file the rotation was loaded from. A rotation only kept in memory is not saved
+func (r *Rotation) Save() error {
+	if r.path == "" {
+		return nil
+	}
+
+	r.mutex.Lock()
+	data, err := json.MarshalIndent(r.state, "", "  ")
+	r.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0700); err != nil {
+		return errors.Wrap(err, "could not create the directory of the reviewer rotation file")
+	}
+
+	// Write to a temporary file first, to never leave a partially written file
+	tmpPath := r.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return errors.Wrapf(err, "could not write the reviewer rotation file %s", r.path)
+	}
+	return os.Rename(tmpPath, r.path)
+}
+
+// Users selects at most maxReviewers of the candidate users, if maxReviewers is zero all available candidates are selected.
+// The selection is counted right away, so that concurrent selections are spread over the users.
+// It should be released with Release if the users are never requested to review a pull request
+func (r *Rotation) Users(candidates []string, maxReviewers int) []string {
+	return r.pick(r.state.Users, candidates, maxReviewers)
+}
+
+// Teams selects at most maxReviewers of the candidate teams, if maxReviewers is zero all available candidates are selected.
+// The selection is counted right away, so that concurrent selections are spread over the teams.
+// It should be released with Release if the teams are never requested to review a pull request
+func (r *Rotation) Teams(candidates []string, maxReviewers int) []string {
+	return r.pick(r.state.Teams, candidates, maxReviewers)
+}
+
+// Release stops counting users and teams that were selected, but never requested to review a pull request
+func (r *Rotation) Release(users []string, teams []string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	release(r.state.Users, users)
+	release(r.state.Teams, teams)
+}
+
+func release(counts map[string]int, reviewers []string) {
+	for _, reviewer := range reviewers {
+		if counts[reviewer] > 1 {
+			counts[reviewer]--
+		} else {
+			delete(counts, reviewer)
+		}
+	}
+}
+
+func (r *Rotation) pick(counts map[string]int, candidates []string, maxReviewers int) []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	available := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		if !r.unavailable[normalize(candidate)] {
+			available = append(available, candidate)
+		}
+	}
+
+	if maxReviewers > 0 && len(available) > maxReviewers {
+		sort.SliceStable(available, func(i, j int) bool {
+			return counts[available[i]] < counts[available[j]]
+		})
+		available = available[:maxReviewers]
+	}
+
+	for _, reviewer := range available {
+		counts[reviewer]++
+	}
+	return available
+}
+
+// ReadUnavailable reads a file with reviewers that should not be requested, one user or team per line.
+// Empty lines and lines starting with # are ignored
+func ReadUnavailable(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read the unavailable reviewers file %s", path)
+	}
+
+	var reviewers []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		reviewers = append(reviewers, line)
+	}
+	return reviewers, scanner.Err()
+}
+
+// normalize makes it possible to list reviewers with or without a leading @ and with any casing
+func normalize(reviewer string) string {
+	return strings.ToLower(strings.TrimPrefix(reviewer, "@"))
+}
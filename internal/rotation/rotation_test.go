@@ -0,0 +1,121 @@
+package rotation
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvenSpread(t *testing.T) {
+	r := New(nil)
+	candidates := []string{"alice", "bob", "carol"}
+
+	assert.Equal(t, []string{"alice", "bob"}, r.Users(candidates, 2))
+	assert.Equal(t, []string{"carol", "alice"}, r.Users(candidates, 2))
+	assert.Equal(t, []string{"bob", "carol"}, r.Users(candidates, 2))
+
+	counts := map[string]int{}
+	for i := 0; i < 100; i++ {
+		for _, reviewer := range r.Users(candidates, 1) {
+			counts[reviewer]++
+		}
+	}
+	assert.Equal(t, map[string]int{"alice": 34, "bob": 33, "carol": 33}, counts)
+
+	// The candidates are not modified
+	assert.Equal(t, []string{"alice", "bob", "carol"}, candidates)
+}
+
+func TestReleasedReviewersAreNotCounted(t *testing.T) {
+	r := New(nil)
+	assert.Equal(t, []string{"alice"}, r.Users([]string{"alice", "bob"}, 1))
+	assert.Equal(t, []string{"team-a"}, r.Teams([]string{"team-a", "team-b"}, 1))
+	r.Release([]string{"alice"}, []string{"team-a"})
+
+	assert.Equal(t, []string{"alice"}, r.Users([]string{"alice", "bob"}, 1))
+	assert.Equal(t, []string{"bob"}, r.Users([]string{"alice", "bob"}, 1))
+	assert.Equal(t, []string{"team-a"}, r.Teams([]string{"team-a", "team-b"}, 1))
+}
+
+func TestConcurrentSelections(t *testing.T) {
+	r := New(nil)
+	candidates := []string{"alice", "bob", "carol", "dave"}
+
+	var wg sync.WaitGroup
+	selected := make([][]string, 4)
+	for i := range selected {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			selected[i] = r.Users(candidates, 1)
+		}(i)
+	}
+	wg.Wait()
+
+	// Selections made before any review was requested are still spread over the reviewers
+	var all []string
+	for _, users := range selected {
+		all = append(all, users...)
+	}
+	assert.ElementsMatch(t, candidates, all)
+}
+
+func TestAllReviewers(t *testing.T) {
+	r := New(nil)
+	assert.Equal(t, []string{"alice", "bob"}, r.Users([]string{"alice", "bob"}, 0))
+	assert.Equal(t, []string{"alice", "bob"}, r.Users([]string{"alice", "bob"}, 5))
+
+	// Reviewers requested on every pull request still count, and are not preferred in other selections
+	assert.Equal(t, []string{"carol"}, r.Users([]string{"alice", "carol"}, 1))
+}
+
+func TestUsersAndTeamsAreCountedSeparately(t *testing.T) {
+	r := New(nil)
+	assert.Equal(t, []string{"alpha"}, r.Users([]string{"alpha"}, 0))
+	assert.Equal(t, []string{"beta"}, r.Users([]string{"alpha", "beta"}, 1))
+	assert.Equal(t, []string{"alpha"}, r.Teams([]string{"alpha", "beta"}, 1))
+}
+
+func TestUnavailable(t *testing.T) {
+	r := New([]string{"@Bob", "team-b"})
+	assert.Equal(t, []string{"alice", "carol"}, r.Users([]string{"alice", "bob", "carol"}, 0))
+	assert.Equal(t, []string{"alice"}, r.Users([]string{"alice", "bob"}, 1))
+	assert.Equal(t, []string{"alice"}, r.Users([]string{"alice", "bob"}, 1))
+	assert.Equal(t, []string{"team-a"}, r.Teams([]string{"team-a", "team-b"}, 0))
+	assert.Empty(t, r.Users([]string{"bob"}, 1))
+}
+
+func TestPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "rotation.json")
+
+	r, err := Load(path, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice"}, r.Users([]string{"alice", "bob"}, 1))
+	assert.Equal(t, []string{"team-a"}, r.Teams([]string{"team-a", "team-b"}, 1))
+	require.NoError(t, r.Save())
+
+	r, err = Load(path, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob"}, r.Users([]string{"alice", "bob"}, 1))
+	assert.Equal(t, []string{"team-b"}, r.Teams([]string{"team-a", "team-b"}, 1))
+
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0600))
+	_, err = Load(path, nil)
+	assert.ErrorContains(t, err, "could not parse the reviewer rotation file")
+}
+
+func TestReadUnavailable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "unavailable.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# On vacation\nalice\n\n  @bob  \nmy-team\n"), 0600))
+
+	reviewers, err := ReadUnavailable(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "@bob", "my-team"}, reviewers)
+
+	_, err = ReadUnavailable(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,871 @@
+// Package azuredevops contains an implementation of Azure DevOps Repos, both for the cloud version and Azure DevOps Server
+package azuredevops
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+)
+
+const (
+	statusActive    = "active"
+	statusCompleted = "completed"
+	statusAbandoned = "abandoned"
+
+	mergeStatusConflicts = "conflicts"
+
+	// commentTypeText is a comment written by a user, as opposed to one created by the system
+	commentTypeText = "text"
+
+	defaultBaseURL = "https://dev.azure.com"
+)
+
+// The names Azure DevOps use for the different merge types
+var mergeStrategies = map[scm.MergeType]string{
+	scm.MergeTypeMerge:  "noFastForward",
+	scm.MergeTypeSquash: "squash",
+	scm.MergeTypeRebase: "rebase",
+}
+
+// Config is the configuration of an Azure DevOps client
+type Config struct {
+	// BaseURL is the url of Azure DevOps, https://dev.azure.com if empty.
+	// For Azure DevOps Server, this is the url of the server, with the collection as the organization
+	BaseURL             string
+	Organization        string
+	Username            string
+	Token               string
+	Insecure            bool
+	SSHAuth             bool
+	TransportMiddleware func(http.RoundTripper) http.RoundTripper
+	RepoListing         RepositoryListing
+	MergeTypes          []scm.MergeType
+}
+
+// New creates a new Azure DevOps client
+func New(config Config) (*AzureDevOps, error) {
+	if strings.TrimSpace(config.Token) == "" {
+		return nil, errors.New("token is empty")
+	}
+
+	if config.Organization == "" {
+		return nil, errors.New("no organization set")
+	}
+
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	orgURL, err := url.Parse(strings.TrimSuffix(baseURL, "/") + "/" + url.PathEscape(config.Organization))
+	if err != nil {
+		return nil, err
+	}
+
+	transportMiddleware := config.TransportMiddleware
+	if transportMiddleware == nil {
+		transportMiddleware = func(rt http.RoundTripper) http.RoundTripper { return rt }
+	}
+
+	return &AzureDevOps{
+		RepositoryListing: config.RepoListing,
+		orgURL:            orgURL,
+		username:          config.Username,
+		token:             config.Token,
+		sshAuth:           config.SSHAuth,
+		mergeTypes:        config.MergeTypes,
+		httpClient: &http.Client{
+			Transport: transportMiddleware(&http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: config.Insecure}, // nolint: gosec
+			}),
+		},
+	}, nil
+}
+
+// AzureDevOps contains the configuration needed to use Azure DevOps Repos
+type AzureDevOps struct {
+	RepositoryListing
+
+	orgURL     *url.URL
+	username   string
+	token      string
+	sshAuth    bool
+	mergeTypes []scm.MergeType
+	httpClient *http.Client
+
+	currentUserLock sync.Mutex
+	currentUserID   string
+}
+
+// RepositoryListing contains information about which repositories that should be fetched.
+// If no projects or repositories are set, all repositories of the organization are used
+type RepositoryListing struct {
+	Projects     []string
+	Repositories []RepositoryReference
+	SkipForks    bool
+}
+
+// RepositoryReference contains information to be able to reference a repository
+type RepositoryReference struct {
+	Project string
+	Name    string
+}
+
+// String returns the string representation of a repo reference
+func (rr RepositoryReference) String() string {
+	return fmt.Sprintf("%s/%s", rr.Project, rr.Name)
+}
+
+// ParseRepositoryReference parses a repository reference from the format "project/repoName"
+func ParseRepositoryReference(val string) (RepositoryReference, error) {
+	split := strings.Split(val, "/")
+	if len(split) != 2 || split[0] == "" || split[1] == "" {
+		return RepositoryReference{}, fmt.Errorf("could not parse repository reference: %s", val)
+	}
+	return RepositoryReference{
+		Project: split[0],
+		Name:    split[1],
+	}, nil
+}
+
+// GetRepositories fetches the repositories of all projects and repository references
+func (a *AzureDevOps) GetRepositories(ctx context.Context) ([]scm.Repository, error) {
+	adoRepos, err := a.getRepositories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	repos := make([]scm.Repository, 0, len(adoRepos))
+	for _, adoRepo := range adoRepos {
+		log := log.WithField("repo", adoRepo.Project.Name+"/"+adoRepo.Name)
+
+		if adoRepo.IsDisabled {
+			log.Debug("Skipping repository since it's disabled")
+			continue
+		}
+
+		if adoRepo.DefaultBranch == "" {
+			log.Debug("Skipping repository since it's empty")
+			continue
+		}
+
+		if a.SkipForks && adoRepo.IsFork {
+			log.Debug("Skipping repository since it's a fork")
+			continue
+		}
+
+		repo, err := a.convertRepository(adoRepo)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, repo)
+	}
+
+	return repos, nil
+}
+
+func (a *AzureDevOps) getRepositories(ctx context.Context) ([]adoRepository, error) {
+	var allRepos []adoRepository
+
+	if len(a.Projects) == 0 && len(a.Repositories) == 0 {
+		var list adoRepositoryList
+		if err := a.request(ctx, http.MethodGet, "_apis/git/repositories", nil, nil, &list); err != nil {
+			return nil, errors.WithMessage(err, "could not list the repositories of the organization")
+		}
+		allRepos = list.Value
+	}
+
+	for _, project := range a.Projects {
+		var list adoRepositoryList
+		if err := a.request(ctx, http.MethodGet, url.PathEscape(project)+"/_apis/git/repositories", nil, nil, &list); err != nil {
+			return nil, errors.WithMessagef(err, "could not list the repositories of the project %s", project)
+		}
+		allRepos = append(allRepos, list.Value...)
+	}
+
+	for _, ref := range a.Repositories {
+		var repo adoRepository
+		if err := a.request(ctx, http.MethodGet, repositoryPath(ref.Project, ref.Name), nil, nil, &repo); err != nil {
+			return nil, errors.WithMessagef(err, "could not get the repository %s", ref.String())
+		}
+		allRepos = append(allRepos, repo)
+	}
+
+	// Remove duplicate repos
+	repoMap := map[string]adoRepository{}
+	for _, repo := range allRepos {
+		repoMap[repo.ID] = repo
+	}
+	allRepos = make([]adoRepository, 0, len(repoMap))
+	for _, repo := range repoMap {
+		allRepos = append(allRepos, repo)
+	}
+	sort.Slice(allRepos, func(i, j int) bool {
+		if allRepos[i].Project.Name != allRepos[j].Project.Name {
+			return allRepos[i].Project.Name < allRepos[j].Project.Name
+		}
+		return allRepos[i].Name < allRepos[j].Name
+	})
+
+	return allRepos, nil
+}
+
+// CreatePullRequest creates a pull request
+func (a *AzureDevOps) CreatePullRequest(ctx context.Context, repo scm.Repository, prRepo scm.Repository, newPR scm.NewPullRequest) (scm.PullRequest, error) {
+	r := repo.(repository)
+	prR := prRepo.(repository)
+
+	if r.id != prR.id {
+		return nil, errors.New("pull requests from forks are not supported for azure devops")
+	}
+
+	reviewers, err := a.getReviewerIdentities(ctx, newPR.Reviewers, newPR.TeamReviewers)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make([]adoLabel, len(newPR.Labels))
+	for i, label := range newPR.Labels {
+		labels[i] = adoLabel{Name: label}
+	}
+
+	var created adoPullRequest
+	err = a.request(ctx, http.MethodPost, repositoryPath(r.projectID, r.id)+"/pullrequests", nil, adoCreatePullRequest{
+		SourceRefName: "refs/heads/" + newPR.Head,
+		TargetRefName: "refs/heads/" + newPR.Base,
+		Title:         newPR.Title,
+		Description:   newPR.Body,
+		IsDraft:       newPR.Draft,
+		Reviewers:     reviewers,
+		Labels:        labels,
+	}, &created)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "could not create pull request for %s", r.FullName())
+	}
+
+	if newPR.AutoMerge {
+		if err := a.enableAutoComplete(ctx, r, created.PullRequestID); err != nil {
+			return nil, err
+		}
+	}
+
+	return a.newPullRequest(r, created, scm.PullRequestStatusPending), nil
+}
+
+// UpdatePullRequest updates an existing pull request
+func (a *AzureDevOps) UpdatePullRequest(ctx context.Context, repo scm.Repository, pullReq scm.PullRequest, updatedPR scm.NewPullRequest) (scm.PullRequest, error) {
+	r := repo.(repository)
+	pr := pullReq.(pullRequest)
+
+	prPath := fmt.Sprintf("%s/pullrequests/%d", repositoryPath(r.projectID, r.id), pr.id)
+
+	var updated adoPullRequest
+	err := a.request(ctx, http.MethodPatch, prPath, nil, adoUpdatePullRequest{
+		Title:       updatedPR.Title,
+		Description: &updatedPR.Body,
+		IsDraft:     updatedPR.SetDraft,
+	}, &updated)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "could not update %s", pr.String())
+	}
+
+	// Reviewers are only added, never removed, since reviewers might have been added by branch policies
+	reviewers, err := a.getReviewerIdentities(ctx, updatedPR.Reviewers, updatedPR.TeamReviewers)
+	if err != nil {
+		return nil, err
+	}
+	for _, reviewer := range reviewers {
+		if containsReviewer(updated.Reviewers, reviewer.ID) {
+			continue
+		}
+		err := a.request(ctx, http.MethodPut, prPath+"/reviewers/"+url.PathEscape(reviewer.ID), nil, adoReviewer{ID: reviewer.ID}, nil)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "could not add a reviewer to %s", pr.String())
+		}
+	}
+
+	if updatedPR.Labels != nil {
+		if err := a.setLabels(ctx, prPath, updated.Labels, updatedPR.Labels); err != nil {
+			return nil, errors.WithMessagef(err, "could not update the labels of %s", pr.String())
+		}
+	}
+
+	if updatedPR.AutoMerge {
+		if err := a.enableAutoComplete(ctx, r, pr.id); err != nil {
+			return nil, err
+		}
+	}
+
+	return a.newPullRequest(r, updated, pr.status), nil
+}
+
+func containsReviewer(reviewers []adoReviewer, id string) bool {
+	for _, reviewer := range reviewers {
+		if strings.EqualFold(reviewer.ID, id) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *AzureDevOps) setLabels(ctx context.Context, prPath string, existing []adoLabel, labels []string) error {
+	var existingNames []string
+	for _, label := range existing {
+		if label.Active {
+			existingNames = append(existingNames, label.Name)
+		}
+	}
+
+	added, removed := scm.Diff(existingNames, labels)
+	for _, label := range added {
+		if err := a.request(ctx, http.MethodPost, prPath+"/labels", nil, adoLabel{Name: label}, nil); err != nil {
+			return err
+		}
+	}
+	for _, label := range removed {
+		if err := a.request(ctx, http.MethodDelete, prPath+"/labels/"+url.PathEscape(label), nil, nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enableAutoComplete makes the pull request complete as soon as all policies are fulfilled
+func (a *AzureDevOps) enableAutoComplete(ctx context.Context, repo repository, prID int) error {
+	userID, err := a.getCurrentUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	mergeStrategy, err := a.mergeStrategy()
+	if err != nil {
+		return err
+	}
+
+	err = a.request(ctx, http.MethodPatch, fmt.Sprintf("%s/pullrequests/%d", repositoryPath(repo.projectID, repo.id), prID), nil, adoUpdatePullRequest{
+		AutoCompleteSetBy: &adoIdentityRef{ID: userID},
+		CompletionOptions: &adoCompletionOptions{
+			MergeStrategy:      mergeStrategy,
+			DeleteSourceBranch: true,
+		},
+	}, nil)
+	return errors.WithMessage(err, "could not enable auto-complete")
+}
+
+func (a *AzureDevOps) getCurrentUserID(ctx context.Context) (string, error) {
+	a.currentUserLock.Lock()
+	defer a.currentUserLock.Unlock()
+
+	if a.currentUserID != "" {
+		return a.currentUserID, nil
+	}
+
+	var data adoConnectionData
+	if err := a.requestVersion(ctx, http.MethodGet, "_apis/connectionData", apiVersionPreview, nil, nil, &data); err != nil {
+		return "", errors.WithMessage(err, "could not get the current user")
+	}
+	a.currentUserID = data.AuthenticatedUser.ID
+	return a.currentUserID, nil
+}
+
+// getReviewerIdentities finds the ids of users and teams
+func (a *AzureDevOps) getReviewerIdentities(ctx context.Context, reviewers, teamReviewers []string) ([]adoIdentityRef, error) {
+	var ids []adoIdentityRef
+	for _, reviewer := range reviewers {
+		id, err := a.findIdentity(ctx, reviewer, "user")
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, adoIdentityRef{ID: id})
+	}
+	for _, team := range teamReviewers {
+		id, err := a.findIdentity(ctx, team, "group")
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, adoIdentityRef{ID: id})
+	}
+	return ids, nil
+}
+
+// findIdentity finds the id of a user or group. If several identities match, an exact match of the name, email or
+// account name is preferred
+func (a *AzureDevOps) findIdentity(ctx context.Context, name string, identityType string) (string, error) {
+	var result adoIdentityResult
+	err := a.requestVersion(ctx, http.MethodPost, "_apis/IdentityPicker/Identities", apiVersionPreview, nil, adoIdentityQuery{
+		Query:           name,
+		IdentityTypes:   []string{identityType},
+		OperationScopes: []string{"ims", "source"},
+		Properties:      []string{"DisplayName", "Mail", "SignInAddress", "SamAccountName"},
+		Options:         map[string]interface{}{"MinResults": 1, "MaxResults": 20},
+	}, &result)
+	if err != nil {
+		return "", errors.WithMessagef(err, "could not find the %s %s", identityType, name)
+	}
+
+	var identities []adoIdentity
+	for _, r := range result.Results {
+		identities = append(identities, r.Identities...)
+	}
+
+	for _, identity := range identities {
+		for _, identityName := range []string{identity.DisplayName, identity.Mail, identity.SignInAddress, identity.SamAccountName} {
+			if identity.LocalID != "" && strings.EqualFold(identityName, name) {
+				return identity.LocalID, nil
+			}
+		}
+	}
+	if len(identities) == 1 && identities[0].LocalID != "" {
+		return identities[0].LocalID, nil
+	}
+	if len(identities) > 1 {
+		return "", errors.Errorf("the %s %s is ambiguous, %d identities matched", identityType, name, len(identities))
+	}
+	return "", errors.Errorf("could not find the %s %s", identityType, name)
+}
+
+// GetPullRequests gets the latest pull request of a branch in every repository
+func (a *AzureDevOps) GetPullRequests(ctx context.Context, branchName string) ([]scm.PullRequest, error) {
+	repos, err := a.GetRepositories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var prs []scm.PullRequest
+	for _, repo := range repos {
+		r := repo.(repository)
+		pr, err := a.getPullRequest(ctx, r, branchName, "all")
+		if err != nil {
+			return nil, err
+		}
+		if pr == nil {
+			continue
+		}
+
+		convertedPR, err := a.convertPullRequest(ctx, r, *pr)
+		if err != nil {
+			return nil, err
+		}
+		prs = append(prs, convertedPR)
+	}
+
+	return prs, nil
+}
+
+// GetOpenPullRequest gets an active pull request of a branch in one repository
+func (a *AzureDevOps) GetOpenPullRequest(ctx context.Context, repo scm.Repository, branchName string) (scm.PullRequest, error) {
+	r := repo.(repository)
+
+	pr, err := a.getPullRequest(ctx, r, branchName, statusActive)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return nil, nil
+	}
+
+	return a.convertPullRequest(ctx, r, *pr)
+}
+
+// getPullRequest gets the latest pull request of a branch with a specific status
+func (a *AzureDevOps) getPullRequest(ctx context.Context, repo repository, branchName string, status string) (*adoPullRequest, error) {
+	query := url.Values{}
+	query.Set("searchCriteria.sourceRefName", "refs/heads/"+branchName)
+	query.Set("searchCriteria.status", status)
+
+	var list adoPullRequestList
+	if err := a.request(ctx, http.MethodGet, repositoryPath(repo.projectID, repo.id)+"/pullrequests", query, nil, &list); err != nil {
+		return nil, errors.WithMessagef(err, "could not list the pull requests of %s", repo.FullName())
+	}
+
+	var latest *adoPullRequest
+	for i := range list.Value {
+		if latest == nil || list.Value[i].PullRequestID > latest.PullRequestID {
+			latest = &list.Value[i]
+		}
+	}
+	return latest, nil
+}
+
+func (a *AzureDevOps) convertPullRequest(ctx context.Context, repo repository, pr adoPullRequest) (pullRequest, error) {
+	status, checks, err := a.pullRequestStatus(ctx, repo, pr)
+	if err != nil {
+		return pullRequest{}, err
+	}
+	converted := a.newPullRequest(repo, pr, status)
+	converted.details.Checks = checks
+	converted.status = scm.CombinePullRequestStatus(status, converted.details)
+	return converted, nil
+}
+
+func (a *AzureDevOps) newPullRequest(repo repository, pr adoPullRequest, status scm.PullRequestStatus) pullRequest {
+	return pullRequest{
+		projectID:    repo.projectID,
+		projectName:  repo.projectName,
+		repositoryID: repo.id,
+		repoName:     repo.name,
+		branchName:   strings.TrimPrefix(pr.SourceRefName, "refs/heads/"),
+		id:           pr.PullRequestID,
+		authorID:     pr.CreatedBy.ID,
+		webURL:       fmt.Sprintf("%s/pullrequest/%d", repo.webURL, pr.PullRequestID),
+		status:       status,
+		details:      pullRequestDetails(pr),
+	}
+}
+
+func pullRequestDetails(pr adoPullRequest) scm.PullRequestDetails {
+	details := scm.PullRequestDetails{
+		Number:    pr.PullRequestID,
+		Title:     pr.Title,
+		Body:      pr.Description,
+		Author:    pr.CreatedBy.UniqueName,
+		CreatedAt: pr.CreationDate,
+		Draft:     pr.IsDraft,
+
+		BaseBranch: strings.TrimPrefix(pr.TargetRefName, "refs/heads/"),
+	}
+	for _, label := range pr.Labels {
+		details.Labels = append(details.Labels, label.Name)
+	}
+
+	// Votes are 10 for approved, 5 for approved with suggestions, 0 for no vote,
+	// -5 for waiting for the author and -10 for rejected
+	var approved, changesRequested bool
+	for _, reviewer := range pr.Reviewers {
+		switch {
+		case reviewer.Vote > 0:
+			approved = true
+			details.Approvals++
+		case reviewer.Vote < 0:
+			changesRequested = true
+		}
+	}
+	switch {
+	case changesRequested:
+		details.ReviewDecision = scm.ReviewDecisionChangesRequested
+	case approved:
+		details.ReviewDecision = scm.ReviewDecisionApproved
+	case len(pr.Reviewers) > 0:
+		details.ReviewDecision = scm.ReviewDecisionReviewRequired
+	}
+
+	switch pr.MergeStatus {
+	case mergeStatusConflicts:
+		details.Mergeability = scm.MergeabilityConflicting
+	case "succeeded":
+		details.Mergeability = scm.MergeabilityMergeable
+	}
+
+	return details
+}
+
+// pullRequestStatus determines the status of a pull request from its state and the evaluations of its blocking policies.
+// The evaluations of all policies are also returned as checks
+func (a *AzureDevOps) pullRequestStatus(ctx context.Context, repo repository, pr adoPullRequest) (scm.PullRequestStatus, []scm.Check, error) {
+	switch pr.Status {
+	case statusCompleted:
+		return scm.PullRequestStatusMerged, nil, nil
+	case statusAbandoned:
+		return scm.PullRequestStatusClosed, nil, nil
+	}
+
+	if pr.MergeStatus == mergeStatusConflicts {
+		return scm.PullRequestStatusError, nil, nil
+	}
+
+	query := url.Values{}
+	query.Set("artifactId", fmt.Sprintf("vstfs:///CodeReview/CodeReviewId/%s/%d", repo.projectID, pr.PullRequestID))
+
+	var evaluations adoPolicyEvaluationList
+	err := a.requestVersion(ctx, http.MethodGet, url.PathEscape(repo.projectID)+"/_apis/policy/evaluations", apiVersionPreview, query, nil, &evaluations)
+	if err != nil {
+		return scm.PullRequestStatusUnknown, nil, errors.WithMessagef(err, "could not get the policy evaluations of %s/%s #%d", repo.projectName, repo.name, pr.PullRequestID)
+	}
+
+	status := scm.PullRequestStatusSuccess
+	var checks []scm.Check
+	for _, evaluation := range evaluations.Value {
+		if !evaluation.Configuration.IsEnabled {
+			continue
+		}
+
+		check := scm.Check{Name: evaluation.Configuration.Type.DisplayName}
+		switch evaluation.Status {
+		case "approved", "notApplicable":
+			check.Status = scm.CheckStatusSuccess
+		case "rejected", "broken":
+			check.Status = scm.CheckStatusFailure
+		default:
+			check.Status = scm.CheckStatusPending
+		}
+		checks = append(checks, check)
+
+		if !evaluation.Configuration.IsBlocking || status == scm.PullRequestStatusError {
+			continue
+		}
+		switch check.Status {
+		case scm.CheckStatusFailure:
+			status = scm.PullRequestStatusError
+		case scm.CheckStatusPending:
+			status = scm.PullRequestStatusPending
+		}
+	}
+	return status, checks, nil
+}
+
+// RetryFailedChecks re-queues the rejected build policies of a pull request. The number of attempts of a build policy
+// is the number of builds of its pipeline that have been run for the latest commit of the pull request
+func (a *AzureDevOps) RetryFailedChecks(ctx context.Context, pullReq scm.PullRequest, maxAttempts int) error {
+	pr := pullReq.(pullRequest)
+
+	var current adoPullRequest
+	if err := a.request(ctx, http.MethodGet, fmt.Sprintf("%s/pullrequests/%d", repositoryPath(pr.projectID, pr.repositoryID), pr.id), nil, nil, &current); err != nil {
+		return errors.WithMessagef(err, "could not get %s", pr.String())
+	}
+	if current.LastMergeSourceCommit == nil {
+		return errors.Errorf("could not find the latest commit of %s", pr.String())
+	}
+
+	query := url.Values{}
+	query.Set("artifactId", fmt.Sprintf("vstfs:///CodeReview/CodeReviewId/%s/%d", pr.projectID, pr.id))
+
+	var evaluations adoPolicyEvaluationList
+	err := a.requestVersion(ctx, http.MethodGet, url.PathEscape(pr.projectID)+"/_apis/policy/evaluations", apiVersionPreview, query, nil, &evaluations)
+	if err != nil {
+		return errors.WithMessagef(err, "could not get the policy evaluations of %s", pr.String())
+	}
+
+	retried, maxedOut := 0, 0
+	for _, evaluation := range evaluations.Value {
+		if evaluation.Status != "rejected" || evaluation.Context.BuildDefinitionID == 0 {
+			continue
+		}
+
+		attempts, err := a.buildCount(ctx, pr, evaluation.Context.BuildDefinitionID, current.LastMergeSourceCommit.CommitID)
+		if err != nil {
+			return err
+		}
+		if attempts >= maxAttempts {
+			maxedOut++
+			continue
+		}
+
+		evaluationPath := url.PathEscape(pr.projectID) + "/_apis/policy/evaluations/" + url.PathEscape(evaluation.EvaluationID)
+		if err := a.requestVersion(ctx, http.MethodPatch, evaluationPath, apiVersionPreview, nil, nil, nil); err != nil {
+			return errors.WithMessagef(err, "could not re-queue %s of %s", evaluation.Configuration.Type.DisplayName, pr.String())
+		}
+		retried++
+	}
+
+	return scm.RetryResult(retried, maxedOut)
+}
+
+// buildCount returns how many builds of a pipeline that have been run for a specific commit of a pull request
+func (a *AzureDevOps) buildCount(ctx context.Context, pr pullRequest, definitionID int, commitID string) (int, error) {
+	query := url.Values{}
+	query.Set("definitions", strconv.Itoa(definitionID))
+	query.Set("branchName", fmt.Sprintf("refs/pull/%d/merge", pr.id))
+
+	var builds adoBuildList
+	if err := a.request(ctx, http.MethodGet, url.PathEscape(pr.projectID)+"/_apis/build/builds", query, nil, &builds); err != nil {
+		return 0, errors.WithMessagef(err, "could not get the builds of %s", pr.String())
+	}
+
+	count := 0
+	for _, build := range builds.Value {
+		if build.TriggerInfo["pr.sourceSha"] == commitID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// mergeStrategy returns the Azure DevOps name of the first configured merge type
+func (a *AzureDevOps) mergeStrategy() (string, error) {
+	if len(a.mergeTypes) == 0 {
+		return mergeStrategies[scm.MergeTypeMerge], nil
+	}
+	strategy, ok := mergeStrategies[a.mergeTypes[0]]
+	if !ok {
+		return "", errors.New("the merge type is not supported by azure devops")
+	}
+	return strategy, nil
+}
+
+// MergePullRequest completes a pull request
+func (a *AzureDevOps) MergePullRequest(ctx context.Context, pullReq scm.PullRequest) error {
+	pr := pullReq.(pullRequest)
+	prPath := fmt.Sprintf("%s/pullrequests/%d", repositoryPath(pr.projectID, pr.repositoryID), pr.id)
+
+	var current adoPullRequest
+	if err := a.request(ctx, http.MethodGet, prPath, nil, nil, &current); err != nil {
+		return errors.WithMessagef(err, "could not get %s", pr.String())
+	}
+	if current.Status != statusActive {
+		return nil
+	}
+	if current.LastMergeSourceCommit == nil {
+		return errors.Errorf("could not find the latest commit of %s", pr.String())
+	}
+
+	mergeStrategy, err := a.mergeStrategy()
+	if err != nil {
+		return err
+	}
+
+	var completed adoPullRequest
+	err = a.request(ctx, http.MethodPatch, prPath, nil, adoUpdatePullRequest{
+		Status:                statusCompleted,
+		LastMergeSourceCommit: current.LastMergeSourceCommit,
+		CompletionOptions: &adoCompletionOptions{
+			MergeStrategy:      mergeStrategy,
+			DeleteSourceBranch: true,
+		},
+	}, &completed)
+	if err != nil {
+		return errors.WithMessagef(err, "could not merge %s", pr.String())
+	}
+
+	if completed.MergeStatus == mergeStatusConflicts {
+		return errors.Errorf("could not merge %s, there are conflicts", pr.String())
+	}
+
+	return nil
+}
+
+// ClosePullRequest abandons a pull request
+func (a *AzureDevOps) ClosePullRequest(ctx context.Context, pullReq scm.PullRequest) error {
+	pr := pullReq.(pullRequest)
+	repoPath := repositoryPath(pr.projectID, pr.repositoryID)
+
+	err := a.request(ctx, http.MethodPatch, fmt.Sprintf("%s/pullrequests/%d", repoPath, pr.id), nil, adoUpdatePullRequest{
+		Status: statusAbandoned,
+	}, nil)
+	return errors.WithMessagef(err, "could not abandon %s", pr.String())
+}
+
+// DeletePullRequestBranch deletes the source branch of a pull request
+func (a *AzureDevOps) DeletePullRequestBranch(ctx context.Context, pullReq scm.PullRequest) error {
+	pr := pullReq.(pullRequest)
+	repoPath := repositoryPath(pr.projectID, pr.repositoryID)
+
+	return errors.WithMessagef(a.deleteBranch(ctx, repoPath, pr.branchName), "could not delete the branch of %s", pr.String())
+}
+
+// PullRequestComments returns the comments of all threads of a pull request, comments created by the system are left out
+func (a *AzureDevOps) PullRequestComments(ctx context.Context, pullReq scm.PullRequest) ([]string, error) {
+	pr := pullReq.(pullRequest)
+	repoPath := repositoryPath(pr.projectID, pr.repositoryID)
+
+	var threads adoCommentThreadList
+	err := a.request(ctx, http.MethodGet, fmt.Sprintf("%s/pullrequests/%d/threads", repoPath, pr.id), nil, nil, &threads)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "could not get the comments of %s", pr.String())
+	}
+
+	var comments []string
+	for _, thread := range threads.Value {
+		for _, comment := range thread.Comments {
+			if comment.CommentType == commentTypeText {
+				comments = append(comments, comment.Content)
+			}
+		}
+	}
+	return comments, nil
+}
+
+// CommentPullRequest adds a comment to a pull request, in a new thread
+func (a *AzureDevOps) CommentPullRequest(ctx context.Context, pullReq scm.PullRequest, comment string) error {
+	pr := pullReq.(pullRequest)
+	repoPath := repositoryPath(pr.projectID, pr.repositoryID)
+
+	err := a.request(ctx, http.MethodPost, fmt.Sprintf("%s/pullrequests/%d/threads", repoPath, pr.id), nil, adoCommentThread{
+		Comments: []adoComment{{Content: comment, CommentType: commentTypeText}},
+	}, nil)
+	return errors.WithMessagef(err, "could not comment on %s", pr.String())
+}
+
+// ReportsApprovals tells that the details of pull requests contain the number of approvals
+func (*AzureDevOps) ReportsApprovals() bool {
+	return true
+}
+
+// ApprovePullRequest votes to approve a pull request, which adds the authenticated user as a reviewer if it's not already one
+func (a *AzureDevOps) ApprovePullRequest(ctx context.Context, pullReq scm.PullRequest) error {
+	pr := pullReq.(pullRequest)
+
+	userID, err := a.getCurrentUserID(ctx)
+	if err != nil {
+		return err
+	}
+	if userID == pr.authorID {
+		return scm.ErrOwnPullRequest
+	}
+
+	prPath := fmt.Sprintf("%s/pullrequests/%d", repositoryPath(pr.projectID, pr.repositoryID), pr.id)
+	err = a.request(ctx, http.MethodPut, prPath+"/reviewers/"+url.PathEscape(userID), nil, adoReviewer{ID: userID, Vote: 10}, nil) // 10 is an approving vote
+	return errors.WithMessagef(err, "could not approve %s", pr.String())
+}
+
+func (a *AzureDevOps) deleteBranch(ctx context.Context, repoPath, branchName string) error {
+	refName := "refs/heads/" + branchName
+
+	query := url.Values{}
+	query.Set("filter", "heads/"+branchName)
+	var refs adoRefList
+	if err := a.request(ctx, http.MethodGet, repoPath+"/refs", query, nil, &refs); err != nil {
+		return err
+	}
+
+	for _, ref := range refs.Value {
+		if ref.Name != refName {
+			continue
+		}
+		return a.request(ctx, http.MethodPost, repoPath+"/refs", nil, []adoRefUpdate{{
+			Name:        refName,
+			OldObjectID: ref.ObjectID,
+			NewObjectID: "0000000000000000000000000000000000000000",
+		}}, nil)
+	}
+
+	// The branch is already deleted
+	return nil
+}
+
+// RepositoryFiles returns the paths of all files and directories in the repository on the branch
+func (a *AzureDevOps) RepositoryFiles(ctx context.Context, repo scm.Repository, branch string) ([]string, error) {
+	r := repo.(repository)
+
+	query := url.Values{}
+	query.Set("recursionLevel", "Full")
+	query.Set("versionDescriptor.version", branch)
+	query.Set("versionDescriptor.versionType", "branch")
+
+	var list adoItemList
+	if err := a.request(ctx, http.MethodGet, repositoryPath(r.projectID, r.id)+"/items", query, nil, &list); err != nil {
+		return nil, errors.WithMessagef(err, "could not list the files of %s", r.FullName())
+	}
+
+	files := make([]string, 0, len(list.Value))
+	for _, item := range list.Value {
+		if path := strings.TrimPrefix(item.Path, "/"); path != "" {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+// ForkRepository is not supported for azure devops
+func (a *AzureDevOps) ForkRepository(_ context.Context, _ scm.Repository, _ string) (scm.Repository, error) {
+	return nil, errors.New("forking is not implemented for azure devops")
+}
@@ -0,0 +1,270 @@
+package azuredevops
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	apiVersion        = "7.1"
+	apiVersionPreview = "7.1-preview.1"
+)
+
+// request makes a request to the REST API of the organization. The path is relative to the organization URL,
+// and the body (if not nil) and response (if out is not nil) are encoded as json
+func (a *AzureDevOps) request(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	return a.requestVersion(ctx, method, path, apiVersion, query, body, out)
+}
+
+func (a *AzureDevOps) requestVersion(ctx context.Context, method, path, version string, query url.Values, body, out interface{}) error {
+	u := *a.orgURL
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + strings.TrimPrefix(path, "/")
+
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("api-version", version)
+	u.RawQuery = query.Encode()
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		bodyReader = bytes.NewReader(bodyBytes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bodyReader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	// Personal access tokens are used as the password with basic authentication, the username is ignored
+	req.SetBasicAuth("", a.token)
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 400 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Message != "" {
+			return errors.Errorf("%s %s: status code %d: %s", method, path, resp.StatusCode, apiErr.Message)
+		}
+		return errors.Errorf("%s %s: status code %d", method, path, resp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return errors.Wrapf(json.Unmarshal(respBody, out), "could not parse the response of %s %s", method, path)
+}
+
+// repositoryPath returns the path of a repository, relative to the organization URL
+func repositoryPath(project, repositoryID string) string {
+	return url.PathEscape(project) + "/_apis/git/repositories/" + url.PathEscape(repositoryID)
+}
+
+type adoProject struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Visibility string `json:"visibility"`
+}
+
+type adoRepository struct {
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	DefaultBranch string     `json:"defaultBranch"`
+	RemoteURL     string     `json:"remoteUrl"`
+	SSHURL        string     `json:"sshUrl"`
+	Size          int64      `json:"size"`
+	WebURL        string     `json:"webUrl"`
+	IsDisabled    bool       `json:"isDisabled"`
+	IsFork        bool       `json:"isFork"`
+	Project       adoProject `json:"project"`
+}
+
+type adoRepositoryList struct {
+	Value []adoRepository `json:"value"`
+}
+
+type adoIdentityRef struct {
+	ID string `json:"id"`
+}
+
+type adoReviewer struct {
+	ID          string `json:"id"`
+	Vote        int    `json:"vote"`
+	IsContainer bool   `json:"isContainer,omitempty"`
+}
+
+type adoLabel struct {
+	ID     string `json:"id,omitempty"`
+	Name   string `json:"name"`
+	Active bool   `json:"active,omitempty"`
+}
+
+type adoCommitRef struct {
+	CommitID string `json:"commitId"`
+}
+
+type adoCompletionOptions struct {
+	MergeStrategy      string `json:"mergeStrategy,omitempty"`
+	DeleteSourceBranch bool   `json:"deleteSourceBranch"`
+}
+
+type adoPullRequest struct {
+	PullRequestID         int           `json:"pullRequestId"`
+	Status                string        `json:"status"`
+	Title                 string        `json:"title"`
+	Description           string        `json:"description"`
+	SourceRefName         string        `json:"sourceRefName"`
+	TargetRefName         string        `json:"targetRefName"`
+	IsDraft               bool          `json:"isDraft"`
+	MergeStatus           string        `json:"mergeStatus"`
+	Repository            adoRepository `json:"repository"`
+	LastMergeSourceCommit *adoCommitRef `json:"lastMergeSourceCommit"`
+	Reviewers             []adoReviewer `json:"reviewers"`
+	Labels                []adoLabel    `json:"labels"`
+	CreationDate          time.Time     `json:"creationDate"`
+	CreatedBy             struct {
+		ID         string `json:"id"`
+		UniqueName string `json:"uniqueName"`
+	} `json:"createdBy"`
+}
+
+type adoPullRequestList struct {
+	Value []adoPullRequest `json:"value"`
+}
+
+type adoCreatePullRequest struct {
+	SourceRefName string           `json:"sourceRefName"`
+	TargetRefName string           `json:"targetRefName"`
+	Title         string           `json:"title"`
+	Description   string           `json:"description"`
+	IsDraft       bool             `json:"isDraft"`
+	Reviewers     []adoIdentityRef `json:"reviewers,omitempty"`
+	Labels        []adoLabel       `json:"labels,omitempty"`
+}
+
+type adoUpdatePullRequest struct {
+	Title                 string                `json:"title,omitempty"`
+	Description           *string               `json:"description,omitempty"`
+	IsDraft               *bool                 `json:"isDraft,omitempty"`
+	Status                string                `json:"status,omitempty"`
+	LastMergeSourceCommit *adoCommitRef         `json:"lastMergeSourceCommit,omitempty"`
+	AutoCompleteSetBy     *adoIdentityRef       `json:"autoCompleteSetBy,omitempty"`
+	CompletionOptions     *adoCompletionOptions `json:"completionOptions,omitempty"`
+}
+
+type adoComment struct {
+	Content     string `json:"content"`
+	CommentType string `json:"commentType"`
+}
+
+type adoCommentThread struct {
+	Comments []adoComment `json:"comments"`
+}
+
+type adoCommentThreadList struct {
+	Value []adoCommentThread `json:"value"`
+}
+
+type adoPolicyEvaluation struct {
+	EvaluationID string `json:"evaluationId"`
+	Status       string `json:"status"`
+	Context      struct {
+		// BuildDefinitionID is only set for build policies
+		BuildDefinitionID int `json:"buildDefinitionId"`
+	} `json:"context"`
+	Configuration struct {
+		IsBlocking bool `json:"isBlocking"`
+		IsEnabled  bool `json:"isEnabled"`
+		Type       struct {
+			DisplayName string `json:"displayName"`
+		} `json:"type"`
+	} `json:"configuration"`
+}
+
+type adoPolicyEvaluationList struct {
+	Value []adoPolicyEvaluation `json:"value"`
+}
+
+type adoBuild struct {
+	ID          int               `json:"id"`
+	TriggerInfo map[string]string `json:"triggerInfo"`
+}
+
+type adoBuildList struct {
+	Value []adoBuild `json:"value"`
+}
+
+type adoRef struct {
+	Name     string `json:"name"`
+	ObjectID string `json:"objectId"`
+}
+
+type adoRefList struct {
+	Value []adoRef `json:"value"`
+}
+
+type adoRefUpdate struct {
+	Name        string `json:"name"`
+	OldObjectID string `json:"oldObjectId"`
+	NewObjectID string `json:"newObjectId"`
+}
+
+type adoItem struct {
+	Path string `json:"path"`
+}
+
+type adoItemList struct {
+	Value []adoItem `json:"value"`
+}
+
+type adoConnectionData struct {
+	AuthenticatedUser adoIdentityRef `json:"authenticatedUser"`
+}
+
+type adoIdentityQuery struct {
+	Query           string                 `json:"query"`
+	IdentityTypes   []string               `json:"identityTypes"`
+	OperationScopes []string               `json:"operationScopes"`
+	Properties      []string               `json:"properties"`
+	Options         map[string]interface{} `json:"options"`
+}
+
+type adoIdentity struct {
+	LocalID        string `json:"localId"`
+	DisplayName    string `json:"displayName"`
+	SignInAddress  string `json:"signInAddress"`
+	Mail           string `json:"mail"`
+	SamAccountName string `json:"samAccountName"`
+}
+
+type adoIdentityResult struct {
+	Results []struct {
+		Identities []adoIdentity `json:"identities"`
+	} `json:"results"`
+}
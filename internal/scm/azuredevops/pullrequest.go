@@ -0,0 +1,41 @@
+package azuredevops
+
+import (
+	"fmt"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+)
+
+type pullRequest struct {
+	projectID    string
+	projectName  string
+	repositoryID string
+	repoName     string
+	branchName   string
+	id           int
+	authorID     string
+	webURL       string
+	status       scm.PullRequestStatus
+	details      scm.PullRequestDetails
+}
+
+func (pr pullRequest) String() string {
+	return fmt.Sprintf("%s/%s #%d", pr.projectName, pr.repoName, pr.id)
+}
+
+// RepositoryName returns the full name of the repository the pull request targets
+func (pr pullRequest) RepositoryName() string {
+	return pr.projectName + "/" + pr.repoName
+}
+
+func (pr pullRequest) Status() scm.PullRequestStatus {
+	return pr.status
+}
+
+func (pr pullRequest) URL() string {
+	return pr.webURL
+}
+
+func (pr pullRequest) Details() scm.PullRequestDetails {
+	return pr.details
+}
@@ -0,0 +1,74 @@
+package azuredevops
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+)
+
+func (a *AzureDevOps) convertRepository(repo adoRepository) (repository, error) {
+	var cloneURL string
+	if a.sshAuth {
+		cloneURL = repo.SSHURL
+		if cloneURL == "" {
+			return repository{}, errors.Errorf("unable to find the ssh clone url of %s/%s", repo.Project.Name, repo.Name)
+		}
+	} else {
+		u, err := url.Parse(repo.RemoteURL)
+		if err != nil {
+			return repository{}, err
+		}
+		// Any username can be used together with a personal access token
+		username := a.username
+		if username == "" {
+			username = "pat"
+		}
+		u.User = url.UserPassword(username, a.token)
+		cloneURL = u.String()
+	}
+
+	return repository{
+		id:            repo.ID,
+		name:          repo.Name,
+		projectID:     repo.Project.ID,
+		projectName:   repo.Project.Name,
+		defaultBranch: strings.TrimPrefix(repo.DefaultBranch, "refs/heads/"),
+		cloneURL:      cloneURL,
+		webURL:        repo.WebURL,
+		metadata: scm.RepositoryMetadata{
+			Visibility: scm.Visibility(repo.Project.Visibility),
+			Size:       repo.Size,
+			Fork:       repo.IsFork,
+		},
+	}, nil
+}
+
+type repository struct {
+	id            string
+	name          string
+	projectID     string
+	projectName   string
+	defaultBranch string
+	cloneURL      string
+	webURL        string
+	metadata      scm.RepositoryMetadata
+}
+
+func (r repository) CloneURL() string {
+	return r.cloneURL
+}
+
+func (r repository) DefaultBranch() string {
+	return r.defaultBranch
+}
+
+func (r repository) FullName() string {
+	return r.projectName + "/" + r.name
+}
+
+func (r repository) Metadata() scm.RepositoryMetadata {
+	return r.metadata
+}
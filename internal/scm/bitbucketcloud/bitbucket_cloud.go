@@ -222,7 +222,8 @@ func (bbc *BitbucketCloud) getPullRequests(_ context.Context, repoName string) (
 }
 
 func (bbc *BitbucketCloud) convertPullRequest(project, repoName string, pr *bbPullRequest) pullRequest {
-	status := bbc.pullRequestStatus(pr)
+	details := pullRequestDetails(pr)
+	status := scm.CombinePullRequestStatus(bbc.pullRequestStatus(pr), details)
 
 	return pullRequest{
 		repoName:   repoName,
@@ -232,12 +233,48 @@ func (bbc *BitbucketCloud) convertPullRequest(project, repoName string, pr *bbPu
 		prProject:  pr.Source.Repository.Project.Key,
 		prRepoName: pr.Source.Repository.Slug,
 		number:     pr.ID,
+		authorUUID: pr.Author.UUID,
 
-		guiURL: pr.Links.HTML.Href,
-		status: status,
+		guiURL:  pr.Links.HTML.Href,
+		status:  status,
+		details: details,
 	}
 }
 
+func pullRequestDetails(pr *bbPullRequest) scm.PullRequestDetails {
+	details := scm.PullRequestDetails{
+		Number:    pr.ID,
+		Title:     pr.Title,
+		Body:      pr.Description,
+		Author:    pr.Author.Nickname,
+		CreatedAt: pr.CreatedOn,
+		UpdatedAt: pr.UpdatedOn,
+		Draft:     pr.Draft,
+
+		BaseBranch: pr.Destination.Branch.Name,
+	}
+
+	var approved, changesRequested bool
+	for _, participant := range pr.Participants {
+		switch {
+		case participant.State == "changes_requested":
+			changesRequested = true
+		case participant.Approved:
+			approved = true
+			details.Approvals++
+		}
+	}
+	// The branch restrictions that decide if an approval is required are not known, so a review is never marked as required
+	switch {
+	case changesRequested:
+		details.ReviewDecision = scm.ReviewDecisionChangesRequested
+	case approved:
+		details.ReviewDecision = scm.ReviewDecisionApproved
+	}
+
+	return details
+}
+
 func (bbc *BitbucketCloud) pullRequestStatus(pr *bbPullRequest) scm.PullRequestStatus {
 	switch pr.State {
 	case stateMerged:
@@ -295,6 +332,94 @@ func (bbc *BitbucketCloud) ClosePullRequest(_ context.Context, pr scm.PullReques
 	return err
 }
 
+// DeletePullRequestBranch deletes the source branch of a pull request, in the fork if forking is used
+func (bbc *BitbucketCloud) DeletePullRequestBranch(_ context.Context, pr scm.PullRequest) error {
+	bbcPR := pr.(pullRequest)
+
+	owner := bbc.workspaces[0]
+	if bbc.fork {
+		owner = bbc.newOwner
+		if owner == "" {
+			owner = bbc.username
+		}
+	}
+
+	return bbc.bbClient.Repositories.Repository.DeleteBranch(&bitbucket.RepositoryBranchDeleteOptions{
+		Owner:    owner,
+		RepoSlug: bbcPR.prRepoName,
+		RefName:  bbcPR.branchName,
+	})
+}
+
+// PullRequestComments returns the comments of a pull request
+func (bbc *BitbucketCloud) PullRequestComments(_ context.Context, pr scm.PullRequest) ([]string, error) {
+	bbcPR := pr.(pullRequest)
+	response, err := bbc.bbClient.Repositories.PullRequests.GetComments(&bitbucket.PullRequestsOptions{
+		ID:       fmt.Sprintf("%d", bbcPR.number),
+		RepoSlug: extractRepoSlug(bbcPR),
+		Owner:    bbc.workspaces[0],
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	commentBytes, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+	bbComments := &bitbucketComments{}
+	if err := json.Unmarshal(commentBytes, bbComments); err != nil {
+		return nil, err
+	}
+
+	comments := make([]string, 0, len(bbComments.Values))
+	for _, comment := range bbComments.Values {
+		if !comment.Deleted {
+			comments = append(comments, comment.Content.Raw)
+		}
+	}
+	return comments, nil
+}
+
+// CommentPullRequest adds a comment to a pull request
+func (bbc *BitbucketCloud) CommentPullRequest(ctx context.Context, pr scm.PullRequest, comment string) error {
+	bbcPR := pr.(pullRequest)
+	commentOptions := &bitbucket.PullRequestCommentOptions{
+		Owner:         bbc.workspaces[0],
+		RepoSlug:      extractRepoSlug(bbcPR),
+		PullRequestID: fmt.Sprintf("%d", bbcPR.number),
+		Content:       comment,
+	}
+	_, err := bbc.bbClient.Repositories.PullRequests.AddComment(commentOptions.WithContext(ctx))
+	return err
+}
+
+// ReportsApprovals tells that the details of pull requests contain the number of approvals
+func (*BitbucketCloud) ReportsApprovals() bool {
+	return true
+}
+
+// ApprovePullRequest approves a pull request
+func (bbc *BitbucketCloud) ApprovePullRequest(_ context.Context, pr scm.PullRequest) error {
+	bbcPR := pr.(pullRequest)
+
+	currentUser, err := bbc.bbClient.User.Profile()
+	if err != nil {
+		return errors.WithMessage(err, "could not get the authenticated user")
+	}
+	if currentUser.Uuid == bbcPR.authorUUID {
+		return scm.ErrOwnPullRequest
+	}
+
+	prOptions := &bitbucket.PullRequestsOptions{
+		ID:       fmt.Sprintf("%d", bbcPR.number),
+		RepoSlug: extractRepoSlug(bbcPR),
+		Owner:    bbc.workspaces[0],
+	}
+	_, err = bbc.bbClient.Repositories.PullRequests.Approve(prOptions)
+	return err
+}
+
 func (bbc *BitbucketCloud) GetRepositories(_ context.Context) ([]scm.Repository, error) {
 	repoOptions := &bitbucket.RepositoriesOptions{
 		Role:  "member",
@@ -321,6 +446,27 @@ func (bbc *BitbucketCloud) GetRepositories(_ context.Context) ([]scm.Repository,
 	return repositories, nil
 }
 
+// RepositoryFiles returns the paths of all files and directories in the repository on the branch
+func (bbc *BitbucketCloud) RepositoryFiles(_ context.Context, repo scm.Repository, branch string) ([]string, error) {
+	bbcRepo := repo.(repository)
+
+	repoFiles, err := bbc.bbClient.Repositories.Repository.ListFiles(&bitbucket.RepositoryFilesOptions{
+		Owner:    bbc.workspaces[0],
+		RepoSlug: bbcRepo.name,
+		Ref:      branch,
+		MaxDepth: maxFileListingDepth,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(repoFiles))
+	for _, file := range repoFiles {
+		files = append(files, file.Path)
+	}
+	return files, nil
+}
+
 func (bbc *BitbucketCloud) ForkRepository(_ context.Context, repo scm.Repository, newOwner string) (scm.Repository, error) {
 	bbcRepo := repo.(repository)
 	if newOwner == "" {
@@ -378,11 +524,24 @@ func (bbc *BitbucketCloud) convertRepository(repo bitbucket.Repository) (*reposi
 		cloneURL = parsedURL.String()
 	}
 
+	metadata := scm.RepositoryMetadata{
+		Visibility: scm.VisibilityPublic,
+		Language:   repo.Language,
+		Fork:       repo.Parent != nil,
+	}
+	if repo.Is_private {
+		metadata.Visibility = scm.VisibilityPrivate
+	}
+	if repo.UpdatedOnTime != nil {
+		metadata.PushedAt = *repo.UpdatedOnTime
+	}
+
 	return &repository{
 		name:          repo.Slug,
 		project:       repo.Project.Name,
 		defaultBranch: repo.Mainbranch.Name,
 		cloneURL:      cloneURL,
+		metadata:      metadata,
 	}, nil
 }
 
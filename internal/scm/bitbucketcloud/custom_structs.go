@@ -2,6 +2,7 @@ package bitbucketcloud
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ktrysmt/go-bitbucket"
 	"github.com/lindell/multi-gitter/internal/scm"
@@ -12,6 +13,9 @@ const (
 	cloneSSHType  = "ssh"
 	stateMerged   = "MERGED"
 	stateDeclined = "DECLINED"
+
+	// maxFileListingDepth is the number of directory levels that are included when listing the files of a repository
+	maxFileListingDepth = 100
 )
 
 type newPrResponse struct {
@@ -34,8 +38,34 @@ type bbPullRequest struct {
 	Destination pullRequestRef `json:"destination"`
 	Links       links          `json:"links"`
 	Title       string         `json:"title"`
+	Description string         `json:"description"`
 	Type        string         `json:"type"`
 	ID          int            `json:"id"`
+	Draft       bool           `json:"draft"`
+	CreatedOn   time.Time      `json:"created_on"`
+	UpdatedOn   time.Time      `json:"updated_on"`
+	Author      struct {
+		Nickname string `json:"nickname"`
+		UUID     string `json:"uuid"`
+	} `json:"author"`
+	Participants []participant `json:"participants"`
+}
+
+type bitbucketComments struct {
+	Values []bbComment `json:"values"`
+}
+
+type bbComment struct {
+	Deleted bool `json:"deleted"`
+	Content struct {
+		Raw string `json:"raw"`
+	} `json:"content"`
+}
+
+type participant struct {
+	Role     string `json:"role"`
+	Approved bool   `json:"approved"`
+	State    string `json:"state"`
 }
 
 type pullRequestRef struct {
@@ -77,14 +107,21 @@ type pullRequest struct {
 	prProject  string
 	prRepoName string
 	number     int
+	authorUUID string
 	guiURL     string
 	status     scm.PullRequestStatus
+	details    scm.PullRequestDetails
 }
 
 func (pr pullRequest) String() string {
 	return fmt.Sprintf("%s/%s #%d", pr.project, pr.repoName, pr.number)
 }
 
+// RepositoryName returns the full name of the repository the pull request targets
+func (pr pullRequest) RepositoryName() string {
+	return pr.project + "/" + pr.repoName
+}
+
 func (pr pullRequest) Status() scm.PullRequestStatus {
 	return pr.status
 }
@@ -93,12 +130,17 @@ func (pr pullRequest) URL() string {
 	return pr.guiURL
 }
 
+func (pr pullRequest) Details() scm.PullRequestDetails {
+	return pr.details
+}
+
 // repository contains information about a bitbucket repository
 type repository struct {
 	name          string
 	project       string
 	defaultBranch string
 	cloneURL      string
+	metadata      scm.RepositoryMetadata
 }
 
 func (r repository) CloneURL() string {
@@ -112,3 +154,7 @@ func (r repository) DefaultBranch() string {
 func (r repository) FullName() string {
 	return r.project + "/" + r.name
 }
+
+func (r repository) Metadata() scm.RepositoryMetadata {
+	return r.metadata
+}
@@ -347,11 +347,20 @@ func (b *BitbucketServer) GetPullRequests(ctx context.Context, branchName string
 }
 
 func (b *BitbucketServer) convertPullRequest(client *bitbucketv1.APIClient, project, repoName, branchName string, pr *bitbucketv1.PullRequest) (pullRequest, error) {
-	status, err := b.pullRequestStatus(client, project, repoName, pr)
+	status, mergeability, err := b.pullRequestStatus(client, project, repoName, pr)
 	if err != nil {
 		return pullRequest{}, err
 	}
 
+	details := pullRequestDetails(pr)
+	details.Mergeability = mergeability
+	if pr.Open {
+		details.Checks, err = b.buildStatuses(client, pr.FromRef.LatestCommit)
+		if err != nil {
+			return pullRequest{}, err
+		}
+	}
+
 	return pullRequest{
 		repoName:   repoName,
 		project:    project,
@@ -361,38 +370,84 @@ func (b *BitbucketServer) convertPullRequest(client *bitbucketv1.APIClient, proj
 		number:     pr.ID,
 		version:    pr.Version,
 		guiURL:     pr.Links.Self[0].Href,
-		status:     status,
+		status:     scm.CombinePullRequestStatus(status, details),
+		details:    details,
 	}, nil
 }
 
-func (b *BitbucketServer) pullRequestStatus(client *bitbucketv1.APIClient, project, repoName string, pr *bitbucketv1.PullRequest) (scm.PullRequestStatus, error) {
+func (b *BitbucketServer) pullRequestStatus(client *bitbucketv1.APIClient, project, repoName string, pr *bitbucketv1.PullRequest) (scm.PullRequestStatus, scm.Mergeability, error) {
 	switch pr.State {
 	case stateMerged:
-		return scm.PullRequestStatusMerged, nil
+		return scm.PullRequestStatusMerged, "", nil
 	case stateDeclined:
-		return scm.PullRequestStatusClosed, nil
+		return scm.PullRequestStatusClosed, "", nil
 	}
 
 	response, err := client.DefaultApi.CanMerge(project, repoName, pr.ID)
 	if err != nil {
-		return scm.PullRequestStatusUnknown, err
+		return scm.PullRequestStatusUnknown, "", err
 	}
 
 	var merge bitbucketv1.MergeGetResponse
 	err = mapstructure.Decode(response.Values, &merge)
 	if err != nil {
-		return scm.PullRequestStatusUnknown, err
+		return scm.PullRequestStatusUnknown, "", err
+	}
+
+	mergeability := scm.MergeabilityMergeable
+	if merge.Conflicted {
+		mergeability = scm.MergeabilityConflicting
 	}
 
 	if !merge.CanMerge {
-		return scm.PullRequestStatusPending, nil
+		return scm.PullRequestStatusPending, mergeability, nil
 	}
 
 	if merge.Conflicted {
-		return scm.PullRequestStatusError, nil
+		return scm.PullRequestStatusError, mergeability, nil
+	}
+
+	return scm.PullRequestStatusSuccess, mergeability, nil
+}
+
+// buildStatuses gets the build statuses of a commit
+func (b *BitbucketServer) buildStatuses(client *bitbucketv1.APIClient, commit string) ([]scm.Check, error) {
+	if commit == "" {
+		return nil, nil
 	}
 
-	return scm.PullRequestStatusSuccess, nil
+	response, err := client.DefaultApi.GetCommitBuildStatuses(commit)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := bitbucketv1.GetBuildStatusesResponse(response)
+	if err != nil {
+		return nil, err
+	}
+
+	checks := make([]scm.Check, 0, len(statuses))
+	for _, status := range statuses {
+		name := status.Name
+		if name == "" {
+			name = status.Key
+		}
+
+		check := scm.Check{
+			Name: name,
+			URL:  status.Url,
+		}
+		switch status.State {
+		case "SUCCESSFUL":
+			check.Status = scm.CheckStatusSuccess
+		case "FAILED":
+			check.Status = scm.CheckStatusFailure
+		default:
+			check.Status = scm.CheckStatusPending
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
 }
 
 func (b *BitbucketServer) getPullRequest(client *bitbucketv1.APIClient, branchName, project, repoName string) (*bitbucketv1.PullRequest, error) {
@@ -493,11 +548,82 @@ func (b *BitbucketServer) ClosePullRequest(ctx context.Context, pr scm.PullReque
 	client := newClient(ctx, b.config)
 
 	_, err := client.DefaultApi.DeleteWithVersion(bitbucketPR.project, bitbucketPR.repoName, bitbucketPR.number, int(bitbucketPR.version))
-	if err != nil {
-		return err
+	return err
+}
+
+// DeletePullRequestBranch deletes the source branch of a pull request
+func (b *BitbucketServer) DeletePullRequestBranch(ctx context.Context, pr scm.PullRequest) error {
+	return b.deleteBranch(ctx, pr.(pullRequest))
+}
+
+// PullRequestComments returns the top level comments of a pull request
+func (b *BitbucketServer) PullRequestComments(ctx context.Context, pr scm.PullRequest) ([]string, error) {
+	bitbucketPR := pr.(pullRequest)
+
+	client := newClient(ctx, b.config)
+
+	params := map[string]interface{}{"start": 0, "limit": 25}
+
+	var comments []string
+	for {
+		response, err := client.DefaultApi.GetActivities(bitbucketPR.project, bitbucketPR.repoName, bitbucketPR.number, params)
+		if err != nil {
+			return nil, err
+		}
+
+		activities, err := bitbucketv1.GetActivitiesResponse(response)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, activity := range activities.Values {
+			if activity.Action == bitbucketv1.ActionCommented && activity.CommentAction == "ADDED" {
+				comments = append(comments, activity.Comment.Text)
+			}
+		}
+
+		if activities.IsLastPage {
+			break
+		}
+
+		params["start"] = activities.NextPageStart
 	}
+	return comments, nil
+}
 
-	return b.deleteBranch(ctx, bitbucketPR)
+// CommentPullRequest adds a comment to a pull request
+func (b *BitbucketServer) CommentPullRequest(ctx context.Context, pr scm.PullRequest, comment string) error {
+	bitbucketPR := pr.(pullRequest)
+
+	client := newClient(ctx, b.config)
+
+	_, err := client.DefaultApi.CreatePullRequestComment(bitbucketPR.project, bitbucketPR.repoName, bitbucketPR.number, bitbucketv1.Comment{
+		Text: comment,
+	}, []string{"application/json"})
+	return err
+}
+
+// ReportsApprovals tells that the details of pull requests contain the number of approvals
+func (*BitbucketServer) ReportsApprovals() bool {
+	return true
+}
+
+// ApprovePullRequest approves a pull request
+func (b *BitbucketServer) ApprovePullRequest(ctx context.Context, pr scm.PullRequest) error {
+	bitbucketPR := pr.(pullRequest)
+
+	// The slug of a user is the lowercase version of the username
+	if b.username == "" {
+		return errors.New("the username has to be set to approve pull requests")
+	}
+	if strings.EqualFold(b.username, bitbucketPR.details.Author) {
+		return scm.ErrOwnPullRequest
+	}
+
+	client := newClient(ctx, b.config)
+
+	_, err := client.DefaultApi.Approve(bitbucketPR.project, bitbucketPR.repoName, bitbucketPR.number)
+	return err
 }
 
 func (b *BitbucketServer) deleteBranch(ctx context.Context, pr pullRequest) error {
@@ -540,6 +666,39 @@ func (b *BitbucketServer) deleteBranch(ctx context.Context, pr pullRequest) erro
 	return nil
 }
 
+// RepositoryFiles returns the paths of all files in the repository on the branch
+func (b *BitbucketServer) RepositoryFiles(ctx context.Context, repo scm.Repository, branch string) ([]string, error) {
+	r := repo.(repository)
+
+	client := newClient(ctx, b.config)
+
+	params := map[string]interface{}{"start": 0, "limit": 1000, "at": "refs/heads/" + branch}
+
+	var files []string
+	for {
+		response, err := client.DefaultApi.StreamFiles(r.project, r.name, params)
+		if err != nil {
+			return nil, err
+		}
+
+		var pager bitbucketFilePager
+		err = mapstructure.Decode(response.Values, &pager)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, pager.Values...)
+
+		if pager.IsLastPage {
+			break
+		}
+
+		params["start"] = pager.NextPageStart
+	}
+
+	return files, nil
+}
+
 // ForkRepository forks a repository. If newOwner is set, use it, otherwise fork to the current user
 func (b *BitbucketServer) ForkRepository(_ context.Context, _ scm.Repository, _ string) (scm.Repository, error) {
 	return nil, errors.New("forking not implemented for bitbucket server")
@@ -563,6 +722,15 @@ type bitbucketPullRequestPager struct {
 	Values        []bitbucketv1.PullRequest `json:"values"`
 }
 
+type bitbucketFilePager struct {
+	Size          int      `json:"size"`
+	Limit         int      `json:"limit"`
+	Start         int      `json:"start"`
+	NextPageStart int      `json:"nextPageStart"`
+	IsLastPage    bool     `json:"isLastPage"`
+	Values        []string `json:"values"`
+}
+
 type bitbucketDeleteBranch struct {
 	Name   string `json:"name"`
 	DryRun bool   `json:"dryRun"`
@@ -2,6 +2,7 @@ package bitbucketserver
 
 import (
 	"fmt"
+	"time"
 
 	bitbucketv1 "github.com/gfleury/go-bitbucket-v1"
 
@@ -17,9 +18,50 @@ func newPullRequest(pr bitbucketv1.PullRequest) pullRequest {
 		prRepoName: pr.FromRef.Repository.Slug,
 		number:     pr.ID,
 		guiURL:     pr.Links.Self[0].Href,
+		details:    pullRequestDetails(&pr),
 	}
 }
 
+func pullRequestDetails(pr *bitbucketv1.PullRequest) scm.PullRequestDetails {
+	details := scm.PullRequestDetails{
+		Number: pr.ID,
+		Title:  pr.Title,
+		Body:   pr.Description,
+
+		BaseBranch: pr.ToRef.DisplayID,
+		HeadRef:    fmt.Sprintf("refs/pull-requests/%d/from", pr.ID),
+	}
+	if pr.Author != nil {
+		details.Author = pr.Author.User.Slug
+	}
+	if pr.CreatedDate != 0 {
+		details.CreatedAt = time.UnixMilli(pr.CreatedDate)
+	}
+	if pr.UpdatedDate != 0 {
+		details.UpdatedAt = time.UnixMilli(pr.UpdatedDate)
+	}
+
+	var approved, needsWork bool
+	for _, reviewer := range pr.Reviewers {
+		switch reviewer.Status {
+		case "APPROVED":
+			approved = true
+			details.Approvals++
+		case "NEEDS_WORK":
+			needsWork = true
+		}
+	}
+	// The merge checks that decide if an approval is required are not known, so a review is never marked as required
+	switch {
+	case needsWork:
+		details.ReviewDecision = scm.ReviewDecisionChangesRequested
+	case approved:
+		details.ReviewDecision = scm.ReviewDecisionApproved
+	}
+
+	return details
+}
+
 type pullRequest struct {
 	project    string
 	repoName   string
@@ -30,12 +72,18 @@ type pullRequest struct {
 	version    int32
 	guiURL     string
 	status     scm.PullRequestStatus
+	details    scm.PullRequestDetails
 }
 
 func (pr pullRequest) String() string {
 	return fmt.Sprintf("%s/%s #%d", pr.project, pr.repoName, pr.number)
 }
 
+// RepositoryName returns the full name of the repository the pull request targets
+func (pr pullRequest) RepositoryName() string {
+	return pr.project + "/" + pr.repoName
+}
+
 func (pr pullRequest) Status() scm.PullRequestStatus {
 	return pr.status
 }
@@ -43,3 +91,7 @@ func (pr pullRequest) Status() scm.PullRequestStatus {
 func (pr pullRequest) URL() string {
 	return pr.guiURL
 }
+
+func (pr pullRequest) Details() scm.PullRequestDetails {
+	return pr.details
+}
@@ -6,6 +6,8 @@ import (
 
 	bitbucketv1 "github.com/gfleury/go-bitbucket-v1"
 	"github.com/pkg/errors"
+
+	"github.com/lindell/multi-gitter/internal/scm"
 )
 
 func (b *BitbucketServer) convertRepository(bitbucketRepository *bitbucketv1.Repository, defaultBranch bitbucketv1.Branch) (*repository, error) {
@@ -35,6 +37,13 @@ func (b *BitbucketServer) convertRepository(bitbucketRepository *bitbucketv1.Rep
 		project:       bitbucketRepository.Project.Key,
 		defaultBranch: defaultBranch.DisplayID,
 		cloneURL:      cloneURL,
+		metadata: scm.RepositoryMetadata{
+			Visibility: scm.VisibilityPrivate,
+			Fork:       bitbucketRepository.Origin != nil,
+		},
+	}
+	if bitbucketRepository.Public {
+		repo.metadata.Visibility = scm.VisibilityPublic
 	}
 
 	return &repo, nil
@@ -56,6 +65,7 @@ type repository struct {
 	project       string
 	defaultBranch string
 	cloneURL      string
+	metadata      scm.RepositoryMetadata
 }
 
 func (r repository) CloneURL() string {
@@ -69,3 +79,7 @@ func (r repository) DefaultBranch() string {
 func (r repository) FullName() string {
 	return r.project + "/" + r.name
 }
+
+func (r repository) Metadata() scm.RepositoryMetadata {
+	return r.metadata
+}
@@ -15,12 +15,18 @@ type change struct {
 	changeID string
 	status   scm.PullRequestStatus
 	webURL   string
+	details  scm.PullRequestDetails
 }
 
 func (r change) String() string {
 	return fmt.Sprintf("%d: %s", r.number, r.project)
 }
 
+// RepositoryName returns the name of the project the change targets
+func (r change) RepositoryName() string {
+	return r.project
+}
+
 func (r change) Status() scm.PullRequestStatus {
 	return r.status
 }
@@ -29,7 +35,12 @@ func (r change) URL() string {
 	return r.webURL
 }
 
+func (r change) Details() scm.PullRequestDetails {
+	return r.details
+}
+
 func convertChange(changeInfo gogerrit.ChangeInfo, baseURL string) scm.PullRequest {
+	details := changeDetails(changeInfo)
 	status := scm.PullRequestStatusUnknown
 
 	if changeInfo.Submittable {
@@ -37,7 +48,11 @@ func convertChange(changeInfo gogerrit.ChangeInfo, baseURL string) scm.PullReque
 	} else {
 		switch changeInfo.Status {
 		case "NEW":
-			status = scm.PullRequestStatusPending
+			// A change that is not submittable is blocked by its reviews, or by something that has not completed yet
+			status = scm.CombinePullRequestStatus(scm.PullRequestStatusSuccess, details)
+			if status == scm.PullRequestStatusSuccess {
+				status = scm.PullRequestStatusPending
+			}
 		case "MERGED":
 			status = scm.PullRequestStatusMerged
 		case "ABANDONED":
@@ -53,5 +68,39 @@ func convertChange(changeInfo gogerrit.ChangeInfo, baseURL string) scm.PullReque
 		changeID: changeInfo.ChangeID,
 		status:   status,
 		webURL:   fmt.Sprintf("%s/c/%s/+/%d", baseURL, changeInfo.Project, changeInfo.Number),
+		details:  details,
 	}
 }
+
+func changeDetails(changeInfo gogerrit.ChangeInfo) scm.PullRequestDetails {
+	details := scm.PullRequestDetails{
+		Number:    changeInfo.Number,
+		Title:     changeInfo.Subject,
+		Author:    changeInfo.Owner.Username,
+		CreatedAt: changeInfo.Created.Time,
+		UpdatedAt: changeInfo.Updated.Time,
+		Labels:    changeInfo.Hashtags,
+		Draft:     changeInfo.WorkInProgress,
+
+		BaseBranch: changeInfo.Branch,
+		HeadRef:    changeInfo.Revisions[changeInfo.CurrentRevision].Ref,
+	}
+
+	if codeReview, ok := changeInfo.Labels["Code-Review"]; ok {
+		switch {
+		case codeReview.Rejected.AccountID != 0:
+			details.ReviewDecision = scm.ReviewDecisionChangesRequested
+		case codeReview.Approved.AccountID != 0:
+			details.ReviewDecision = scm.ReviewDecisionApproved
+		default:
+			details.ReviewDecision = scm.ReviewDecisionReviewRequired
+		}
+		for _, vote := range codeReview.All {
+			if vote.Value > 0 {
+				details.Approvals++
+			}
+		}
+	}
+
+	return details
+}
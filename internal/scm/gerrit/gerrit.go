@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"os"
 	"os/user"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -25,6 +26,9 @@ const QueryChangesLimit = 100
 const QueryProjectsLimit = 100
 const RefHeadsPrefix = "refs/heads/"
 
+// patchSetPrefix is the prefix Gerrit adds to messages posted on a change, like "Patch Set 2:\n\n"
+var patchSetPrefix = regexp.MustCompile(`^Patch Set \d+:\s*`)
+
 type Gerrit struct {
 	client GoGerritClient
 	config Config
@@ -284,6 +288,58 @@ func (g Gerrit) ClosePullRequest(ctx context.Context, pr scm.PullRequest) error
 	return nil
 }
 
+// PullRequestComments returns the messages of a change, without the patch set prefix Gerrit adds to them
+func (g Gerrit) PullRequestComments(ctx context.Context, pr scm.PullRequest) ([]string, error) {
+	change := pr.(change)
+
+	info, _, err := g.client.GetChange(ctx, change.id, &gogerrit.ChangeOptions{
+		AdditionalFields: []string{"MESSAGES"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	comments := make([]string, 0, len(info.Messages))
+	for _, message := range info.Messages {
+		comments = append(comments, patchSetPrefix.ReplaceAllString(message.Message, ""))
+	}
+	return comments, nil
+}
+
+// CommentPullRequest adds a message to the current revision of a change
+func (g Gerrit) CommentPullRequest(ctx context.Context, pr scm.PullRequest, comment string) error {
+	change := pr.(change)
+
+	_, _, err := g.client.SetReview(ctx, change.id, "current", &gogerrit.ReviewInput{
+		Message: comment,
+	})
+	return err
+}
+
+// EditsPullRequests tells that changes can't be edited, since UpdatePullRequest only fetches the change created by the push
+func (Gerrit) EditsPullRequests() bool {
+	return false
+}
+
+// ReportsApprovals tells that the details of pull requests contain the number of approvals
+func (Gerrit) ReportsApprovals() bool {
+	return true
+}
+
+// ApprovePullRequest votes Code-Review +2 on the current revision of a change
+func (g Gerrit) ApprovePullRequest(ctx context.Context, pr scm.PullRequest) error {
+	change := pr.(change)
+
+	if strings.EqualFold(g.config.Username, change.details.Author) {
+		return scm.ErrOwnPullRequest
+	}
+
+	_, _, err := g.client.SetReview(ctx, change.id, "current", &gogerrit.ReviewInput{
+		Labels: map[string]int{"Code-Review": 2},
+	})
+	return err
+}
+
 func (Gerrit) ForkRepository(_ context.Context, _ scm.Repository, _ string) (scm.Repository, error) {
 	return nil, errors.New("Forking repositories is not supported in Gerrit")
 }
@@ -313,6 +369,10 @@ func (g Gerrit) queryChanges(ctx context.Context, branchName string, filters []s
 		ChangeOptions: gogerrit.ChangeOptions{
 			AdditionalFields: []string{
 				"SUBMITTABLE",
+				"LABELS",
+				"DETAILED_LABELS",
+				"DETAILED_ACCOUNTS",
+				"CURRENT_REVISION",
 			},
 		},
 	}
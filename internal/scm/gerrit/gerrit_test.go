@@ -20,6 +20,8 @@ type goGerritClientMock struct {
 	QueryChangesFunc  func(ctx context.Context, opt *gogerrit.QueryChangeOptions) (*[]gogerrit.ChangeInfo, *gogerrit.Response, error)
 	AbandonChangeFunc func(ctx context.Context, changeID string, input *gogerrit.AbandonInput) (*gogerrit.ChangeInfo, *gogerrit.Response, error)
 	SubmitChangeFunc  func(ctx context.Context, changeID string, input *gogerrit.SubmitInput) (*gogerrit.ChangeInfo, *gogerrit.Response, error)
+	SetReviewFunc     func(ctx context.Context, changeID, revisionID string, input *gogerrit.ReviewInput) (*gogerrit.ReviewResult, *gogerrit.Response, error)
+	GetChangeFunc     func(ctx context.Context, changeID string, opt *gogerrit.ChangeOptions) (*gogerrit.ChangeInfo, *gogerrit.Response, error)
 	GetHEADFunc       func(ctx context.Context, projectName string) (string, *gogerrit.Response, error)
 }
 
@@ -51,6 +53,14 @@ func (gcm goGerritClientMock) SubmitChange(ctx context.Context, changeID string,
 	return gcm.SubmitChangeFunc(ctx, changeID, input)
 }
 
+func (gcm goGerritClientMock) SetReview(ctx context.Context, changeID, revisionID string, input *gogerrit.ReviewInput) (*gogerrit.ReviewResult, *gogerrit.Response, error) {
+	return gcm.SetReviewFunc(ctx, changeID, revisionID, input)
+}
+
+func (gcm goGerritClientMock) GetChange(ctx context.Context, changeID string, opt *gogerrit.ChangeOptions) (*gogerrit.ChangeInfo, *gogerrit.Response, error) {
+	return gcm.GetChangeFunc(ctx, changeID, opt)
+}
+
 func (gcm goGerritClientMock) GetHEAD(ctx context.Context, projectName string) (string, *gogerrit.Response, error) {
 	if gcm.GetHEADFunc != nil {
 		return gcm.GetHEADFunc(ctx, projectName)
@@ -385,6 +395,88 @@ func TestClosePullRequest(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestPullRequestComments(t *testing.T) {
+	g := &Gerrit{
+		client: goGerritClientMock{
+			GetChangeFunc: func(_ context.Context, changeID string, opt *gogerrit.ChangeOptions) (*gogerrit.ChangeInfo, *gogerrit.Response, error) {
+				require.Equal(t, "repo-active~master~Icc717a31a47beb9b5d9aeb8a1d374883afe89030", changeID)
+				require.Equal(t, []string{"MESSAGES"}, opt.AdditionalFields)
+				return &gogerrit.ChangeInfo{
+					Messages: []gogerrit.ChangeMessageInfo{
+						{Message: "Uploaded patch set 1."},
+						{Message: "Patch Set 1:\n\nPlease approve by Friday"},
+					},
+				}, nil, nil
+			},
+		},
+	}
+	pr := change{
+		id:       "repo-active~master~Icc717a31a47beb9b5d9aeb8a1d374883afe89030",
+		project:  "repo-active",
+		branch:   "master",
+		changeID: "Icc717a31a47beb9b5d9aeb8a1d374883afe89030",
+	}
+	comments, err := g.PullRequestComments(context.Background(), pr)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Uploaded patch set 1.", "Please approve by Friday"}, comments)
+}
+
+func TestApprovePullRequest(t *testing.T) {
+	var reviews []*gogerrit.ReviewInput
+	g := &Gerrit{
+		client: goGerritClientMock{
+			SetReviewFunc: func(_ context.Context, changeID, revisionID string, input *gogerrit.ReviewInput) (*gogerrit.ReviewResult, *gogerrit.Response, error) {
+				require.Equal(t, "repo-active~master~Icc717a31a47beb9b5d9aeb8a1d374883afe89030", changeID)
+				require.Equal(t, "current", revisionID)
+				reviews = append(reviews, input)
+				return &gogerrit.ReviewResult{}, nil, nil
+			},
+		},
+		config: Config{Username: "admin"},
+	}
+	pr := change{
+		id:       "repo-active~master~Icc717a31a47beb9b5d9aeb8a1d374883afe89030",
+		project:  "repo-active",
+		branch:   "master",
+		changeID: "Icc717a31a47beb9b5d9aeb8a1d374883afe89030",
+		details:  scm.PullRequestDetails{Author: "multi-gitter"},
+	}
+	require.NoError(t, g.ApprovePullRequest(context.Background(), pr))
+	require.Len(t, reviews, 1)
+	assert.Equal(t, map[string]int{"Code-Review": 2}, reviews[0].Labels)
+
+	pr.details.Author = "admin"
+	assert.ErrorIs(t, g.ApprovePullRequest(context.Background(), pr), scm.ErrOwnPullRequest)
+	assert.Len(t, reviews, 1)
+}
+
+func TestApproveOwnQueriedPullRequest(t *testing.T) {
+	g := &Gerrit{
+		client: goGerritClientMock{
+			QueryChangesFunc: func(_ context.Context, opt *gogerrit.QueryChangeOptions) (*[]gogerrit.ChangeInfo, *gogerrit.Response, error) {
+				changeInfo := gogerrit.ChangeInfo{Project: "repo-active", ChangeID: "I123", Branch: "feature", Number: 1000, Status: "NEW"}
+				// Gerrit only includes the username of the owner when detailed accounts are requested
+				for _, field := range opt.AdditionalFields {
+					if field == "DETAILED_ACCOUNTS" {
+						changeInfo.Owner = gogerrit.AccountInfo{AccountID: 1000000, Username: "admin"}
+					}
+				}
+				return &[]gogerrit.ChangeInfo{changeInfo}, nil, nil
+			},
+			SetReviewFunc: func(_ context.Context, _, _ string, _ *gogerrit.ReviewInput) (*gogerrit.ReviewResult, *gogerrit.Response, error) {
+				t.Fatal("the own change should not be reviewed")
+				return nil, nil, nil
+			},
+		},
+		config: Config{Username: "admin"},
+	}
+
+	pr, err := g.GetOpenPullRequest(context.Background(), repository{name: "repo-active"}, "feature")
+	require.NoError(t, err)
+	assert.Equal(t, "admin", pr.(change).details.Author)
+	assert.ErrorIs(t, g.ApprovePullRequest(context.Background(), pr), scm.ErrOwnPullRequest)
+}
+
 func TestGetDefaultBranch(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -18,6 +18,8 @@ type GoGerritClient interface {
 	QueryChanges(ctx context.Context, opt *gogerrit.QueryChangeOptions) (*[]gogerrit.ChangeInfo, *gogerrit.Response, error)
 	AbandonChange(ctx context.Context, changeID string, input *gogerrit.AbandonInput) (*gogerrit.ChangeInfo, *gogerrit.Response, error)
 	SubmitChange(ctx context.Context, changeID string, input *gogerrit.SubmitInput) (*gogerrit.ChangeInfo, *gogerrit.Response, error)
+	SetReview(ctx context.Context, changeID, revisionID string, input *gogerrit.ReviewInput) (*gogerrit.ReviewResult, *gogerrit.Response, error)
+	GetChange(ctx context.Context, changeID string, opt *gogerrit.ChangeOptions) (*gogerrit.ChangeInfo, *gogerrit.Response, error)
 	GetHEAD(ctx context.Context, projectName string) (string, *gogerrit.Response, error)
 }
 
@@ -41,6 +43,14 @@ func (ggc goGerritClient) SubmitChange(ctx context.Context, changeID string, inp
 	return ggc.client.Changes.SubmitChange(ctx, changeID, input)
 }
 
+func (ggc goGerritClient) SetReview(ctx context.Context, changeID, revisionID string, input *gogerrit.ReviewInput) (*gogerrit.ReviewResult, *gogerrit.Response, error) {
+	return ggc.client.Changes.SetReview(ctx, changeID, revisionID, input)
+}
+
+func (ggc goGerritClient) GetChange(ctx context.Context, changeID string, opt *gogerrit.ChangeOptions) (*gogerrit.ChangeInfo, *gogerrit.Response, error) {
+	return ggc.client.Changes.GetChange(ctx, changeID, opt)
+}
+
 func (ggc goGerritClient) GetHEAD(ctx context.Context, projectName string) (string, *gogerrit.Response, error) {
 	return ggc.client.Projects.GetHEAD(ctx, projectName)
 }
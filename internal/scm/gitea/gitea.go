@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"code.gitea.io/sdk/gitea"
+	"github.com/lindell/multi-gitter/internal/git"
 	"github.com/lindell/multi-gitter/internal/scm"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -111,8 +112,10 @@ func (g *Gitea) GetRepositories(ctx context.Context) ([]scm.Repository, error) {
 			continue
 		}
 
+		var topics []string
 		if len(g.Topics) != 0 {
-			topics, err := g.getRepoTopics(ctx, repo)
+			var err error
+			topics, err = g.getRepoTopics(ctx, repo)
 			if err != nil {
 				return repos, fmt.Errorf("could not fetch repository topics: %w", err)
 			}
@@ -127,6 +130,8 @@ func (g *Gitea) GetRepositories(ctx context.Context) ([]scm.Repository, error) {
 		if err != nil {
 			return nil, err
 		}
+		// Topics are only known if they had to be fetched for filtering
+		convertedRepo.metadata.Topics = topics
 		repos = append(repos, convertedRepo)
 	}
 
@@ -353,7 +358,8 @@ func (g *Gitea) UpdatePullRequest(ctx context.Context, repo scm.Repository, pull
 	r := repo.(repository)
 	pr := pullReq.(pullRequest)
 
-	prTitle := updatedPR.Title
+	// The title of a draft contains the draft prefix, which should not be added twice
+	prTitle := strings.TrimPrefix(updatedPR.Title, "WIP: ")
 	if updatedPR.Draft {
 		prTitle = "WIP: " + prTitle // See https://docs.gitea.io/en-us/pull-request/
 	}
@@ -417,23 +423,113 @@ func (g *Gitea) GetPullRequests(ctx context.Context, branchName string) ([]scm.P
 }
 
 func (g *Gitea) convertPullRequest(ctx context.Context, pr *gitea.PullRequest) (pullRequest, error) {
-	status, err := g.pullRequestStatus(ctx, pr)
+	status, checks, err := g.pullRequestStatus(ctx, pr)
 	if err != nil {
 		return pullRequest{}, err
 	}
 
+	details := pullRequestDetails(pr, checks)
+	if pr.State == gitea.StateOpen {
+		details.ReviewDecision, details.Approvals, err = g.reviews(ctx, pr)
+		if err != nil {
+			return pullRequest{}, err
+		}
+	}
+
 	return pullRequest{
 		repoName:    pr.Base.Repository.Name,
 		ownerName:   pr.Base.Repository.Owner.UserName,
 		branchName:  pr.Head.Name,
 		prOwnerName: pr.Head.Repository.Owner.UserName,
 		prRepoName:  pr.Head.Repository.Name,
-		status:      status,
+		status:      scm.CombinePullRequestStatus(status, details),
 		index:       pr.Index,
 		webURL:      pr.HTMLURL,
+		details:     details,
 	}, nil
 }
 
+func pullRequestDetails(pr *gitea.PullRequest, checks []scm.Check) scm.PullRequestDetails {
+	details := scm.PullRequestDetails{
+		Number: int(pr.Index),
+		Title:  pr.Title,
+		Body:   pr.Body,
+		Draft:  pr.Draft,
+		Checks: checks,
+
+		BaseBranch: pr.Base.Name,
+		HeadRef:    fmt.Sprintf("refs/pull/%d/head", pr.Index),
+	}
+	if pr.Poster != nil {
+		details.Author = pr.Poster.UserName
+	}
+	if pr.Created != nil {
+		details.CreatedAt = *pr.Created
+	}
+	if pr.Updated != nil {
+		details.UpdatedAt = *pr.Updated
+	}
+	for _, label := range pr.Labels {
+		details.Labels = append(details.Labels, label.Name)
+	}
+
+	if pr.State == gitea.StateOpen {
+		if pr.Mergeable {
+			details.Mergeability = scm.MergeabilityMergeable
+		} else {
+			details.Mergeability = scm.MergeabilityConflicting
+		}
+	}
+
+	return details
+}
+
+// reviews combines the latest review of each reviewer into a review decision and the number of approvals,
+// reviews that are stale or dismissed are ignored. A review is only required if the protection of the base branch
+// requires more approvals than the pull request has
+func (g *Gitea) reviews(ctx context.Context, pr *gitea.PullRequest) (scm.ReviewDecision, int, error) {
+	owner, repoName := pr.Base.Repository.Owner.UserName, pr.Base.Repository.Name
+	reviews, _, err := g.giteaClient(ctx).ListPullReviews(owner, repoName, pr.Index, gitea.ListPullReviewsOptions{})
+	if err != nil {
+		return "", 0, err
+	}
+
+	latest := map[string]gitea.ReviewStateType{}
+	for _, review := range reviews {
+		if review.Reviewer == nil || review.Stale || review.Dismissed {
+			continue
+		}
+		latest[review.Reviewer.UserName] = review.State
+	}
+
+	var approvals int
+	var changesRequested bool
+	for _, state := range latest {
+		switch state {
+		case gitea.ReviewStateApproved:
+			approvals++
+		case gitea.ReviewStateRequestChanges:
+			changesRequested = true
+		}
+	}
+
+	if changesRequested {
+		return scm.ReviewDecisionChangesRequested, approvals, nil
+	}
+
+	branch, _, err := g.giteaClient(ctx).GetRepoBranch(owner, repoName, pr.Base.Name)
+	if err != nil {
+		return "", 0, errors.Wrap(err, "could not get the protection of the base branch")
+	}
+	switch {
+	case int64(approvals) < branch.RequiredApprovals:
+		return scm.ReviewDecisionReviewRequired, approvals, nil
+	case approvals > 0:
+		return scm.ReviewDecisionApproved, approvals, nil
+	}
+	return "", approvals, nil
+}
+
 func (g *Gitea) getPullRequest(ctx context.Context, branchName string, owner, repoName string, state gitea.StateType) (*gitea.PullRequest, error) {
 	// We would like to be able to search for a pr with a specific head here, but current (2021-04-24), that option does not exist in the API
 	prs, _, err := g.giteaClient(ctx).ListRepoPullRequests(owner, repoName, gitea.ListPullRequestsOptions{
@@ -452,34 +548,54 @@ func (g *Gitea) getPullRequest(ctx context.Context, branchName string, owner, re
 	return nil, nil
 }
 
-func (g *Gitea) pullRequestStatus(ctx context.Context, pr *gitea.PullRequest) (scm.PullRequestStatus, error) {
+func (g *Gitea) pullRequestStatus(ctx context.Context, pr *gitea.PullRequest) (scm.PullRequestStatus, []scm.Check, error) {
 	if pr.Merged != nil {
-		return scm.PullRequestStatusMerged, nil
+		return scm.PullRequestStatusMerged, nil, nil
 	}
 
 	if pr.State == gitea.StateClosed {
-		return scm.PullRequestStatusClosed, nil
+		return scm.PullRequestStatusClosed, nil, nil
 	}
 
 	status, _, err := g.giteaClient(ctx).GetCombinedStatus(pr.Base.Repository.Owner.UserName, pr.Base.Repository.Name, pr.Head.Sha)
 	if err != nil {
-		return scm.PullRequestStatusUnknown, err
+		return scm.PullRequestStatusUnknown, nil, err
 	}
 
 	if len(status.Statuses) == 0 {
-		return scm.PullRequestStatusSuccess, nil
+		return scm.PullRequestStatusSuccess, nil, nil
+	}
+
+	checks := make([]scm.Check, 0, len(status.Statuses))
+	for _, s := range status.Statuses {
+		checks = append(checks, scm.Check{
+			Name:   s.Context,
+			Status: checkStatus(s.State),
+			URL:    s.TargetURL,
+		})
 	}
 
 	switch status.State {
 	case gitea.StatusPending:
-		return scm.PullRequestStatusPending, nil
+		return scm.PullRequestStatusPending, checks, nil
 	case gitea.StatusSuccess:
-		return scm.PullRequestStatusSuccess, nil
+		return scm.PullRequestStatusSuccess, checks, nil
 	case gitea.StatusError, gitea.StatusFailure:
-		return scm.PullRequestStatusError, nil
+		return scm.PullRequestStatusError, checks, nil
 	}
 
-	return scm.PullRequestStatusUnknown, nil
+	return scm.PullRequestStatusUnknown, checks, nil
+}
+
+func checkStatus(state gitea.StatusState) scm.CheckStatus {
+	switch state {
+	case gitea.StatusSuccess:
+		return scm.CheckStatusSuccess
+	case gitea.StatusError, gitea.StatusFailure:
+		return scm.CheckStatusFailure
+	default:
+		return scm.CheckStatusPending
+	}
 }
 
 // GetOpenPullRequest gets a pull request for one specific repository
@@ -547,13 +663,158 @@ func (g *Gitea) ClosePullRequest(ctx context.Context, pullReq scm.PullRequest) e
 		return errors.Wrapf(err, "could not close %s/%s#%d", pr.ownerName, pr.repoName, pr.index)
 	}
 
+	return nil
+}
+
+// UpdatePullRequestBranch merges the base branch into the head branch of a pull request.
+// Rebasing is not supported
+func (g *Gitea) UpdatePullRequestBranch(ctx context.Context, pullReq scm.PullRequest, rebase bool) error {
+	if rebase {
+		return scm.ErrUpdateMethodNotSupported
+	}
+
+	pr := pullReq.(pullRequest)
+
+	resp, err := g.giteaClient(ctx).UpdatePullRequest(pr.ownerName, pr.repoName, pr.index)
+	if resp != nil && resp.StatusCode == http.StatusConflict {
+		return git.ErrConflict
+	}
+	if err != nil {
+		return errors.Wrapf(err, "could not update the branch of %s/%s#%d", pr.ownerName, pr.repoName, pr.index)
+	}
+
+	return nil
+}
+
+// RetryFailedChecks re-runs the failed jobs of the failed action runs of the latest commit of a pull request
+func (g *Gitea) RetryFailedChecks(ctx context.Context, pullReq scm.PullRequest, maxAttempts int) error {
+	pr := pullReq.(pullRequest)
+	client := g.giteaClient(ctx)
+
+	giteaPR, _, err := client.GetPullRequest(pr.ownerName, pr.repoName, pr.index)
+	if err != nil {
+		return errors.Wrapf(err, "could not get %s/%s#%d", pr.ownerName, pr.repoName, pr.index)
+	}
+
+	var failedRuns []*gitea.ActionWorkflowRun
+	for i := 1; ; i++ {
+		runs, _, err := client.ListRepoActionRuns(pr.ownerName, pr.repoName, gitea.ListRepoActionRunsOptions{
+			ListOptions: gitea.ListOptions{
+				Page:     i,
+				PageSize: 100,
+			},
+			HeadSHA: giteaPR.Head.Sha,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "could not get the action runs of %s/%s#%d", pr.ownerName, pr.repoName, pr.index)
+		}
+
+		for _, run := range runs.WorkflowRuns {
+			if run.Conclusion == "failure" {
+				failedRuns = append(failedRuns, run)
+			}
+		}
+
+		if len(runs.WorkflowRuns) < 100 {
+			break
+		}
+	}
+
+	retried, maxedOut := 0, 0
+	for _, run := range failedRuns {
+		if run.RunAttempt >= int64(maxAttempts) {
+			maxedOut++
+			continue
+		}
+
+		if _, err := client.RerunRepoActionRunFailedJobs(pr.ownerName, pr.repoName, run.ID); err != nil {
+			return errors.Wrapf(err, "could not re-run %s of %s/%s#%d", run.DisplayTitle, pr.ownerName, pr.repoName, pr.index)
+		}
+		retried++
+	}
+
+	return scm.RetryResult(retried, maxedOut)
+}
+
+// DeletePullRequestBranch deletes the head branch of a pull request
+func (g *Gitea) DeletePullRequestBranch(ctx context.Context, pullReq scm.PullRequest) error {
+	pr := pullReq.(pullRequest)
+
 	deleted, _, err := g.giteaClient(ctx).DeleteRepoBranch(pr.prOwnerName, pr.prRepoName, pr.branchName)
 	if err != nil {
-		return errors.Wrapf(err, "could not delete branch after merging %s/%s", pr.ownerName, pr.repoName)
+		return errors.Wrapf(err, "could not delete the branch of %s/%s#%d", pr.ownerName, pr.repoName, pr.index)
 	}
 
 	if !deleted {
-		return errors.Errorf("could not delete branch after merging %s/%s", pr.ownerName, pr.repoName)
+		return errors.Errorf("could not delete the branch of %s/%s#%d", pr.ownerName, pr.repoName, pr.index)
+	}
+
+	return nil
+}
+
+// PullRequestComments returns the comments of a pull request
+func (g *Gitea) PullRequestComments(ctx context.Context, pullReq scm.PullRequest) ([]string, error) {
+	pr := pullReq.(pullRequest)
+
+	var comments []string
+	for i := 1; ; i++ {
+		cc, _, err := g.giteaClient(ctx).ListIssueComments(pr.ownerName, pr.repoName, pr.index, gitea.ListIssueCommentOptions{
+			ListOptions: gitea.ListOptions{
+				Page:     i,
+				PageSize: 100,
+			},
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not get the comments of %s/%s#%d", pr.ownerName, pr.repoName, pr.index)
+		}
+
+		for _, comment := range cc {
+			comments = append(comments, comment.Body)
+		}
+
+		if len(cc) < 100 {
+			break
+		}
+	}
+	return comments, nil
+}
+
+// CommentPullRequest adds a comment to a pull request
+func (g *Gitea) CommentPullRequest(ctx context.Context, pullReq scm.PullRequest, comment string) error {
+	pr := pullReq.(pullRequest)
+
+	_, _, err := g.giteaClient(ctx).CreateIssueComment(pr.ownerName, pr.repoName, pr.index, gitea.CreateIssueCommentOption{
+		Body: comment,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "could not comment on %s/%s#%d", pr.ownerName, pr.repoName, pr.index)
+	}
+
+	return nil
+}
+
+// ReportsApprovals tells that the details of pull requests contain the number of approvals
+func (*Gitea) ReportsApprovals() bool {
+	return true
+}
+
+// ApprovePullRequest submits an approving review on a pull request
+func (g *Gitea) ApprovePullRequest(ctx context.Context, pullReq scm.PullRequest) error {
+	pr := pullReq.(pullRequest)
+
+	user, err := g.getUser(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get the authenticated user")
+	}
+	if user.UserName == pr.details.Author {
+		return scm.ErrOwnPullRequest
+	}
+
+	_, _, err = g.giteaClient(ctx).CreatePullReview(pr.ownerName, pr.repoName, pr.index, gitea.CreatePullReviewOptions{
+		State: gitea.ReviewStateApproved,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "could not approve %s/%s#%d", pr.ownerName, pr.repoName, pr.index)
 	}
 
 	return nil
@@ -590,6 +851,35 @@ func (g *Gitea) ForkRepository(ctx context.Context, repo scm.Repository, newOwne
 	return g.convertRepository(createdRepo)
 }
 
+// RepositoryFiles returns the paths of all files and directories in the repository on the branch
+func (g *Gitea) RepositoryFiles(ctx context.Context, repo scm.Repository, branch string) ([]string, error) {
+	r := repo.(repository)
+
+	var files []string
+	for page := 1; ; page++ {
+		tree, _, err := g.giteaClient(ctx).GetTrees(r.ownerName, r.name, gitea.ListTreeOptions{
+			ListOptions: gitea.ListOptions{
+				Page:     page,
+				PageSize: 1000,
+			},
+			Ref:       branch,
+			Recursive: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range tree.Entries {
+			files = append(files, entry.Path)
+		}
+
+		if !tree.Truncated || len(tree.Entries) == 0 {
+			break
+		}
+	}
+	return files, nil
+}
+
 func (g *Gitea) getUser(ctx context.Context) (*gitea.User, error) {
 	if g.currentUser != nil {
 		return g.currentUser, nil
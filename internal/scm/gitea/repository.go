@@ -5,6 +5,8 @@ import (
 	"net/url"
 
 	"code.gitea.io/sdk/gitea"
+
+	"github.com/lindell/multi-gitter/internal/scm"
 )
 
 func (g *Gitea) convertRepository(repo *gitea.Repository) (repository, error) {
@@ -26,14 +28,34 @@ func (g *Gitea) convertRepository(repo *gitea.Repository) (repository, error) {
 		name:          repo.Name,
 		ownerName:     repo.Owner.UserName,
 		defaultBranch: repo.DefaultBranch,
+		metadata: scm.RepositoryMetadata{
+			Archived:   repo.Archived,
+			Visibility: giteaVisibility(repo),
+			Language:   repo.Language,
+			PushedAt:   repo.Updated,
+			Size:       int64(repo.Size) * 1024, // The size is reported in kilobytes
+			Fork:       repo.Fork,
+		},
 	}, nil
 }
 
+func giteaVisibility(repo *gitea.Repository) scm.Visibility {
+	switch {
+	case repo.Internal:
+		return scm.VisibilityInternal
+	case repo.Private:
+		return scm.VisibilityPrivate
+	default:
+		return scm.VisibilityPublic
+	}
+}
+
 type repository struct {
 	url           string
 	name          string
 	ownerName     string
 	defaultBranch string
+	metadata      scm.RepositoryMetadata
 }
 
 func (r repository) CloneURL() string {
@@ -47,3 +69,7 @@ func (r repository) DefaultBranch() string {
 func (r repository) FullName() string {
 	return fmt.Sprintf("%s/%s", r.ownerName, r.name)
 }
+
+func (r repository) Metadata() scm.RepositoryMetadata {
+	return r.metadata
+}
@@ -16,6 +16,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/oauth2"
 
+	"github.com/lindell/multi-gitter/internal/git"
 	"github.com/lindell/multi-gitter/internal/scm"
 )
 
@@ -618,6 +619,40 @@ func (g *Github) enableAutoMerge(ctx context.Context, _ repository, pr *github.P
 	return g.makeGraphQLRequestWithRetry(ctx, query, variables, &result)
 }
 
+func (g *Github) setDraft(ctx context.Context, pr *github.PullRequest, draft bool) error {
+	// The REST API can't change the draft state of an existing pull request, only the GraphQL API can
+	query := `
+		mutation markReadyForReview($pullRequestId: ID!) {
+			markPullRequestReadyForReview(input: {
+				pullRequestId: $pullRequestId
+			}) {
+				pullRequest {
+					id
+				}
+			}
+		}`
+	if draft {
+		query = `
+		mutation convertToDraft($pullRequestId: ID!) {
+			convertPullRequestToDraft(input: {
+				pullRequestId: $pullRequestId
+			}) {
+				pullRequest {
+					id
+				}
+			}
+		}`
+	}
+
+	variables := map[string]interface{}{
+		"pullRequestId": pr.GetNodeID(),
+	}
+
+	var result interface{} // We don't need to parse the result
+
+	return g.makeGraphQLRequestWithRetry(ctx, query, variables, &result)
+}
+
 // UpdatePullRequest updates an existing pull request
 func (g *Github) UpdatePullRequest(ctx context.Context, repo scm.Repository, pullReq scm.PullRequest, updatedPR scm.NewPullRequest) (scm.PullRequest, error) {
 	r := repo.(repository)
@@ -630,13 +665,19 @@ func (g *Github) UpdatePullRequest(ctx context.Context, repo scm.Repository, pul
 		return g.ghClient.PullRequests.Edit(ctx, pr.ownerName, pr.repoName, pr.number, &github.PullRequest{
 			Title: &updatedPR.Title,
 			Body:  &updatedPR.Body,
-			Draft: &updatedPR.Draft,
 		})
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	if updatedPR.SetDraft != nil && *updatedPR.SetDraft != ghPR.GetDraft() {
+		if err := g.setDraft(ctx, ghPR, *updatedPR.SetDraft); err != nil {
+			return nil, errors.WithMessage(err, "could not change the draft state")
+		}
+		ghPR.Draft = updatedPR.SetDraft
+	}
+
 	if err := g.setReviewers(ctx, r, updatedPR, ghPR); err != nil {
 		return nil, err
 	}
@@ -689,9 +730,30 @@ func (g *Github) getPullRequests(ctx context.Context, branchName string, repos [
 			nodes {
 				number
 				headRefName
+				baseRefName
 				closed
 				url
 				merged
+				title
+				body
+				isDraft
+				createdAt
+				updatedAt
+				author {
+					login
+				}
+				labels(first: 100) {
+					nodes {
+						name
+					}
+				}
+				reviewDecision
+				latestOpinionatedReviews(first: 100) {
+					nodes {
+						state
+					}
+				}
+				mergeable
 				baseRepository {
 					name
 					owner {
@@ -709,6 +771,22 @@ func (g *Github) getPullRequests(ctx context.Context, branchName string, repos [
 						commit {
 							statusCheckRollup {
 								state
+								contexts(first: 100) {
+									nodes {
+										__typename
+										... on CheckRun {
+											name
+											status
+											conclusion
+											detailsUrl
+										}
+										... on StatusContext {
+											context
+											state
+											targetUrl
+										}
+									}
+								}
 							}
 						}
 					}
@@ -897,12 +975,179 @@ func (g *Github) ClosePullRequest(ctx context.Context, pullReq scm.PullRequest)
 			State: &[]string{"closed"}[0],
 		})
 	})
+	return err
+}
+
+// DeletePullRequestBranch deletes the head branch of a pull request
+func (g *Github) DeletePullRequestBranch(ctx context.Context, pullReq scm.PullRequest) error {
+	pr := pullReq.(pullRequest)
+
+	g.modLock()
+	defer g.modUnlock()
+
+	_, err := retryWithoutReturn(ctx, func() (*github.Response, error) {
+		return g.ghClient.Git.DeleteRef(ctx, pr.prOwnerName, pr.prRepoName, fmt.Sprintf("heads/%s", pr.branchName))
+	})
+	return err
+}
+
+// UpdatePullRequestBranch merges the base branch into the head branch of a pull request.
+// GitHub can only rebase a branch when merging, so rebasing is not supported
+func (g *Github) UpdatePullRequestBranch(ctx context.Context, pullReq scm.PullRequest, rebase bool) error {
+	if rebase {
+		return scm.ErrUpdateMethodNotSupported
+	}
+
+	pr := pullReq.(pullRequest)
+
+	g.modLock()
+	defer g.modUnlock()
+
+	_, err := retryWithoutReturn(ctx, func() (*github.Response, error) {
+		_, resp, err := g.ghClient.PullRequests.UpdateBranch(ctx, pr.ownerName, pr.repoName, pr.number, nil)
+		// The update is done in the background
+		if _, ok := err.(*github.AcceptedError); ok {
+			return resp, nil
+		}
+		return resp, err
+	})
+
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response.StatusCode == http.StatusUnprocessableEntity &&
+		strings.Contains(strings.ToLower(errResp.Message), "conflict") {
+		return git.ErrConflict
+	}
+	return err
+}
+
+// ReportsApprovals tells that the details of pull requests contain the number of approvals
+func (*Github) ReportsApprovals() bool {
+	return true
+}
+
+// ApprovePullRequest submits an approving review on a pull request
+func (g *Github) ApprovePullRequest(ctx context.Context, pullReq scm.PullRequest) error {
+	pr := pullReq.(pullRequest)
+
+	user, err := g.loggedInUser(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "could not get the authenticated user")
+	}
+	if strings.EqualFold(user, pr.details.Author) {
+		return scm.ErrOwnPullRequest
+	}
+
+	g.modLock()
+	defer g.modUnlock()
+
+	_, _, err = retry(ctx, func() (*github.PullRequestReview, *github.Response, error) {
+		return g.ghClient.PullRequests.CreateReview(ctx, pr.ownerName, pr.repoName, pr.number, &github.PullRequestReviewRequest{
+			Event: &[]string{"APPROVE"}[0],
+		})
+	})
+	return err
+}
+
+// RetryFailedChecks re-runs the failed jobs of the failed workflow runs of the latest commit of a pull request
+func (g *Github) RetryFailedChecks(ctx context.Context, pullReq scm.PullRequest, maxAttempts int) error {
+	pr := pullReq.(pullRequest)
+
+	ghPR, _, err := retry(ctx, func() (*github.PullRequest, *github.Response, error) {
+		return g.ghClient.PullRequests.Get(ctx, pr.ownerName, pr.repoName, pr.number)
+	})
 	if err != nil {
 		return err
 	}
 
-	_, err = retryWithoutReturn(ctx, func() (*github.Response, error) {
-		return g.ghClient.Git.DeleteRef(ctx, pr.prOwnerName, pr.prRepoName, fmt.Sprintf("heads/%s", pr.branchName))
+	var failedRuns []*github.WorkflowRun
+	for i := 1; ; i++ {
+		runs, _, err := retry(ctx, func() (*github.WorkflowRuns, *github.Response, error) {
+			return g.ghClient.Actions.ListRepositoryWorkflowRuns(ctx, pr.ownerName, pr.repoName, &github.ListWorkflowRunsOptions{
+				HeadSHA: ghPR.GetHead().GetSHA(),
+				ListOptions: github.ListOptions{
+					Page:    i,
+					PerPage: 100,
+				},
+			})
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, run := range runs.WorkflowRuns {
+			switch run.GetConclusion() {
+			case "failure", "timed_out":
+				failedRuns = append(failedRuns, run)
+			}
+		}
+
+		if len(runs.WorkflowRuns) != 100 {
+			break
+		}
+	}
+
+	g.modLock()
+	defer g.modUnlock()
+
+	retried, maxedOut := 0, 0
+	for _, run := range failedRuns {
+		if run.GetRunAttempt() >= maxAttempts {
+			maxedOut++
+			continue
+		}
+
+		_, err := retryWithoutReturn(ctx, func() (*github.Response, error) {
+			return g.ghClient.Actions.RerunFailedJobsByID(ctx, pr.ownerName, pr.repoName, run.GetID())
+		})
+		if err != nil {
+			return errors.WithMessagef(err, "could not re-run %s", run.GetName())
+		}
+		retried++
+	}
+
+	return scm.RetryResult(retried, maxedOut)
+}
+
+// PullRequestComments returns the comments of a pull request
+func (g *Github) PullRequestComments(ctx context.Context, pullReq scm.PullRequest) ([]string, error) {
+	pr := pullReq.(pullRequest)
+
+	var comments []string
+	for i := 1; ; i++ {
+		cc, _, err := retry(ctx, func() ([]*github.IssueComment, *github.Response, error) {
+			return g.ghClient.Issues.ListComments(ctx, pr.ownerName, pr.repoName, pr.number, &github.IssueListCommentsOptions{
+				ListOptions: github.ListOptions{
+					Page:    i,
+					PerPage: 100,
+				},
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, comment := range cc {
+			comments = append(comments, comment.GetBody())
+		}
+
+		if len(cc) != 100 {
+			break
+		}
+	}
+	return comments, nil
+}
+
+// CommentPullRequest adds a comment to a pull request
+func (g *Github) CommentPullRequest(ctx context.Context, pullReq scm.PullRequest, comment string) error {
+	pr := pullReq.(pullRequest)
+
+	g.modLock()
+	defer g.modUnlock()
+
+	_, _, err := retry(ctx, func() (*github.IssueComment, *github.Response, error) {
+		return g.ghClient.Issues.CreateComment(ctx, pr.ownerName, pr.repoName, pr.number, &github.IssueComment{
+			Body: &comment,
+		})
 	})
 	return err
 }
@@ -945,6 +1190,27 @@ func (g *Github) ForkRepository(ctx context.Context, repo scm.Repository, newOwn
 	return g.convertRepo(createdRepo)
 }
 
+// RepositoryFiles returns the paths of all files and directories in the repository on the branch
+func (g *Github) RepositoryFiles(ctx context.Context, repo scm.Repository, branch string) ([]string, error) {
+	r := repo.(repository)
+
+	tree, _, err := retry(ctx, func() (*github.Tree, *github.Response, error) {
+		return g.ghClient.Git.GetTree(ctx, r.ownerName, r.name, branch, true)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if tree.GetTruncated() {
+		log.Warnf("The file listing of %s is truncated, not all files will be considered", r.FullName())
+	}
+
+	files := make([]string, 0, len(tree.Entries))
+	for _, entry := range tree.Entries {
+		files = append(files, entry.GetPath())
+	}
+	return files, nil
+}
+
 // GetAutocompleteOrganizations gets organizations for autocompletion
 func (g *Github) GetAutocompleteOrganizations(ctx context.Context, _ string) ([]string, error) {
 	orgs, _, err := retry(ctx, func() ([]*github.Organization, *github.Response, error) {
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -126,6 +127,27 @@ const (
 	graphqlPullRequestStateSuccess graphqlPullRequestState = "SUCCESS"
 )
 
+type graphqlCheckContext struct {
+	Typename string `json:"__typename"`
+
+	// Fields of check runs
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	DetailsURL string `json:"detailsUrl"`
+
+	// Fields of commit statuses
+	Context   string `json:"context"`
+	State     string `json:"state"`
+	TargetURL string `json:"targetUrl"`
+}
+
+type graphqlLabels struct {
+	Nodes []struct {
+		Name string `json:"name"`
+	} `json:"nodes"`
+}
+
 type graphqlRepo struct {
 	PullRequests struct {
 		Nodes []graphqlPR `json:"nodes"`
@@ -133,11 +155,28 @@ type graphqlRepo struct {
 }
 
 type graphqlPR struct {
-	Number         int    `json:"number"`
-	HeadRefName    string `json:"headRefName"`
-	Closed         bool   `json:"closed"`
-	URL            string `json:"url"`
-	Merged         bool   `json:"merged"`
+	Number      int       `json:"number"`
+	HeadRefName string    `json:"headRefName"`
+	BaseRefName string    `json:"baseRefName"`
+	Closed      bool      `json:"closed"`
+	URL         string    `json:"url"`
+	Merged      bool      `json:"merged"`
+	Title       string    `json:"title"`
+	Body        string    `json:"body"`
+	IsDraft     bool      `json:"isDraft"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	Author      *struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Labels         graphqlLabels `json:"labels"`
+	ReviewDecision *string       `json:"reviewDecision"`
+	Reviews        struct {
+		Nodes []struct {
+			State string `json:"state"`
+		} `json:"nodes"`
+	} `json:"latestOpinionatedReviews"`
+	Mergeable      string `json:"mergeable"`
 	BaseRepository struct {
 		Name  string `json:"name"`
 		Owner struct {
@@ -154,7 +193,10 @@ type graphqlPR struct {
 		Nodes []struct {
 			Commit struct {
 				StatusCheckRollup struct {
-					State *graphqlPullRequestState `json:"state"`
+					State    *graphqlPullRequestState `json:"state"`
+					Contexts struct {
+						Nodes []graphqlCheckContext `json:"nodes"`
+					} `json:"contexts"`
 				} `json:"statusCheckRollup"`
 			} `json:"commit"`
 		} `json:"nodes"`
@@ -17,14 +17,44 @@ func convertPullRequest(pr *github.PullRequest) pullRequest {
 		prRepoName:  pr.GetHead().GetRepo().GetName(),
 		number:      pr.GetNumber(),
 		guiURL:      pr.GetHTMLURL(),
+		details:     restPullRequestDetails(pr),
 	}
 }
 
+func restPullRequestDetails(pr *github.PullRequest) scm.PullRequestDetails {
+	labels := make([]string, 0, len(pr.Labels))
+	for _, label := range pr.Labels {
+		labels = append(labels, label.GetName())
+	}
+
+	return scm.PullRequestDetails{
+		Number:     pr.GetNumber(),
+		Title:      pr.GetTitle(),
+		Body:       pr.GetBody(),
+		Author:     pr.GetUser().GetLogin(),
+		CreatedAt:  pr.GetCreatedAt().Time,
+		UpdatedAt:  pr.GetUpdatedAt().Time,
+		Labels:     labels,
+		Draft:      pr.GetDraft(),
+		BaseBranch: pr.GetBase().GetRef(),
+		HeadRef:    pullRequestHeadRef(pr.GetNumber()),
+	}
+}
+
+// pullRequestHeadRef is the reference GitHub keeps of the head of a pull request in the base repository
+func pullRequestHeadRef(number int) string {
+	return fmt.Sprintf("refs/pull/%d/head", number)
+}
+
 func convertGraphQLPullRequest(pr graphqlPR) pullRequest {
 	var combinedStatus *graphqlPullRequestState
+	var checks []scm.Check
 	nodes := pr.Commits.Nodes
 	if len(nodes) > 0 {
 		combinedStatus = nodes[0].Commit.StatusCheckRollup.State
+		for _, context := range nodes[0].Commit.StatusCheckRollup.Contexts.Nodes {
+			checks = append(checks, convertGraphQLCheck(context))
+		}
 	}
 
 	status := scm.PullRequestStatusUnknown
@@ -46,6 +76,8 @@ func convertGraphQLPullRequest(pr graphqlPR) pullRequest {
 		}
 	}
 
+	details := graphQLPullRequestDetails(pr, checks)
+
 	return pullRequest{
 		ownerName:   pr.BaseRepository.Owner.Login,
 		repoName:    pr.BaseRepository.Name,
@@ -54,8 +86,98 @@ func convertGraphQLPullRequest(pr graphqlPR) pullRequest {
 		prRepoName:  pr.HeadRepository.Name,
 		number:      pr.Number,
 		guiURL:      pr.URL,
-		status:      status,
+		status:      scm.CombinePullRequestStatus(status, details),
+		details:     details,
+	}
+}
+
+func graphQLPullRequestDetails(pr graphqlPR, checks []scm.Check) scm.PullRequestDetails {
+	var labels []string
+	for _, label := range pr.Labels.Nodes {
+		labels = append(labels, label.Name)
+	}
+
+	var author string
+	if pr.Author != nil {
+		author = pr.Author.Login
+	}
+
+	var reviewDecision scm.ReviewDecision
+	if pr.ReviewDecision != nil {
+		switch *pr.ReviewDecision {
+		case "APPROVED":
+			reviewDecision = scm.ReviewDecisionApproved
+		case "CHANGES_REQUESTED":
+			reviewDecision = scm.ReviewDecisionChangesRequested
+		case "REVIEW_REQUIRED":
+			reviewDecision = scm.ReviewDecisionReviewRequired
+		}
+	}
+
+	var approvals int
+	for _, review := range pr.Reviews.Nodes {
+		if review.State == "APPROVED" {
+			approvals++
+		}
+	}
+
+	var mergeability scm.Mergeability
+	switch pr.Mergeable {
+	case "MERGEABLE":
+		mergeability = scm.MergeabilityMergeable
+	case "CONFLICTING":
+		mergeability = scm.MergeabilityConflicting
+	}
+
+	return scm.PullRequestDetails{
+		Number:         pr.Number,
+		Title:          pr.Title,
+		Body:           pr.Body,
+		Author:         author,
+		CreatedAt:      pr.CreatedAt,
+		UpdatedAt:      pr.UpdatedAt,
+		Labels:         labels,
+		Draft:          pr.IsDraft,
+		Checks:         checks,
+		ReviewDecision: reviewDecision,
+		Approvals:      approvals,
+		Mergeability:   mergeability,
+		BaseBranch:     pr.BaseRefName,
+		HeadRef:        pullRequestHeadRef(pr.Number),
+	}
+}
+
+// convertGraphQLCheck converts either a check run or a commit status
+func convertGraphQLCheck(context graphqlCheckContext) scm.Check {
+	if context.Typename == "StatusContext" {
+		check := scm.Check{
+			Name: context.Context,
+			URL:  context.TargetURL,
+		}
+		switch context.State {
+		case "SUCCESS":
+			check.Status = scm.CheckStatusSuccess
+		case "ERROR", "FAILURE":
+			check.Status = scm.CheckStatusFailure
+		default:
+			check.Status = scm.CheckStatusPending
+		}
+		return check
+	}
+
+	check := scm.Check{
+		Name: context.Name,
+		URL:  context.DetailsURL,
+	}
+	switch {
+	case context.Status != "COMPLETED":
+		check.Status = scm.CheckStatusPending
+	case context.Conclusion == "SUCCESS", context.Conclusion == "NEUTRAL", context.Conclusion == "SKIPPED":
+		check.Status = scm.CheckStatusSuccess
+	default:
+		check.Status = scm.CheckStatusFailure
 	}
+	return check
 }
 
 type pullRequest struct {
@@ -67,12 +189,18 @@ type pullRequest struct {
 	number      int
 	guiURL      string
 	status      scm.PullRequestStatus
+	details     scm.PullRequestDetails
 }
 
 func (pr pullRequest) String() string {
 	return fmt.Sprintf("%s/%s #%d", pr.ownerName, pr.repoName, pr.number)
 }
 
+// RepositoryName returns the full name of the repository the pull request targets
+func (pr pullRequest) RepositoryName() string {
+	return fmt.Sprintf("%s/%s", pr.ownerName, pr.repoName)
+}
+
 func (pr pullRequest) Status() scm.PullRequestStatus {
 	return pr.status
 }
@@ -80,3 +208,7 @@ func (pr pullRequest) Status() scm.PullRequestStatus {
 func (pr pullRequest) URL() string {
 	return pr.guiURL
 }
+
+func (pr pullRequest) Details() scm.PullRequestDetails {
+	return pr.details
+}
@@ -52,15 +52,47 @@ func Test_convertGraphQLPullRequest(t *testing.T) {
 			prRepoName:  "pr_owner",
 			number:      1,
 			guiURL:      "http://dummy.url",
+			details: scm.PullRequestDetails{
+				Number:  1,
+				HeadRef: "refs/pull/1/head",
+			},
 		},
 	}}
 
 	for _, scenario := range scenarios {
 		t.Run(scenario.name, func(t *testing.T) {
 			got := convertGraphQLPullRequest(scenario.pr)
-			if got != scenario.expected {
-				assert.Equal(t, scenario.expected, got)
-			}
+			assert.Equal(t, scenario.expected, got)
+		})
+	}
+}
+
+func Test_convertGraphQLCheck(t *testing.T) {
+	tests := []struct {
+		context  graphqlCheckContext
+		expected scm.Check
+	}{
+		{
+			context:  graphqlCheckContext{Typename: "CheckRun", Name: "build", Status: "COMPLETED", Conclusion: "SUCCESS", DetailsURL: "http://dummy.url"},
+			expected: scm.Check{Name: "build", Status: scm.CheckStatusSuccess, URL: "http://dummy.url"},
+		},
+		{
+			context:  graphqlCheckContext{Typename: "CheckRun", Name: "lint", Status: "COMPLETED", Conclusion: "TIMED_OUT"},
+			expected: scm.Check{Name: "lint", Status: scm.CheckStatusFailure},
+		},
+		{
+			context:  graphqlCheckContext{Typename: "CheckRun", Name: "test", Status: "IN_PROGRESS"},
+			expected: scm.Check{Name: "test", Status: scm.CheckStatusPending},
+		},
+		{
+			context:  graphqlCheckContext{Typename: "StatusContext", Context: "ci/jenkins", State: "ERROR", TargetURL: "http://dummy.url"},
+			expected: scm.Check{Name: "ci/jenkins", Status: scm.CheckStatusFailure, URL: "http://dummy.url"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected.Name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, convertGraphQLCheck(tt.context))
 		})
 	}
 }
@@ -6,6 +6,8 @@ import (
 
 	"github.com/google/go-github/v85/github"
 	"github.com/pkg/errors"
+
+	"github.com/lindell/multi-gitter/internal/scm"
 )
 
 func (g *Github) convertRepo(r *github.Repository) (repository, error) {
@@ -28,15 +30,38 @@ func (g *Github) convertRepo(r *github.Repository) (repository, error) {
 		name:          r.GetName(),
 		ownerName:     r.GetOwner().GetLogin(),
 		defaultBranch: r.GetDefaultBranch(),
+		metadata: scm.RepositoryMetadata{
+			Archived:   r.GetArchived(),
+			Visibility: githubVisibility(r),
+			Language:   r.GetLanguage(),
+			Topics:     r.Topics,
+			PushedAt:   r.GetPushedAt().Time,
+			Size:       int64(r.GetSize()) * 1024, // The size is reported in kilobytes
+			Fork:       r.GetFork(),
+		},
 	}, nil
 }
 
+func githubVisibility(r *github.Repository) scm.Visibility {
+	if r.Visibility != nil {
+		return scm.Visibility(r.GetVisibility())
+	}
+	if r.Private != nil {
+		if r.GetPrivate() {
+			return scm.VisibilityPrivate
+		}
+		return scm.VisibilityPublic
+	}
+	return ""
+}
+
 type repository struct {
 	url           string
 	id            string
 	name          string
 	ownerName     string
 	defaultBranch string
+	metadata      scm.RepositoryMetadata
 }
 
 func (r repository) CloneURL() string {
@@ -50,3 +75,7 @@ func (r repository) DefaultBranch() string {
 func (r repository) FullName() string {
 	return fmt.Sprintf("%s/%s", r.ownerName, r.name)
 }
+
+func (r repository) Metadata() scm.RepositoryMetadata {
+	return r.metadata
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/lindell/multi-gitter/internal/git"
 	internalHTTP "github.com/lindell/multi-gitter/internal/http"
 	"github.com/lindell/multi-gitter/internal/scm"
 	log "github.com/sirupsen/logrus"
@@ -318,29 +319,38 @@ func (g *Gitlab) UpdatePullRequest(ctx context.Context, repo scm.Repository, pul
 	r := repo.(repository)
 	pr := pullReq.(pullRequest)
 
-	reviewersIDs, err := g.getUserIds(ctx, updatedPR.Reviewers)
-	if err != nil {
-		return nil, err
-	}
-
-	assigneesIDs, err := g.getUserIds(ctx, updatedPR.Assignees)
-	if err != nil {
-		return nil, err
-	}
-
-	prTitle := updatedPR.Title
+	// The title of a draft contains the draft prefix, which should not be added twice
+	prTitle := strings.TrimPrefix(updatedPR.Title, "Draft: ")
 	if updatedPR.Draft {
 		prTitle = "Draft: " + prTitle // See https://docs.gitlab.com/ee/user/project/merge_requests/drafts.html#mark-merge-requests-as-drafts
 	}
 
-	labels := gitlab.LabelOptions(updatedPR.Labels)
-	mr, _, err := g.glClient.MergeRequests.UpdateMergeRequest(pr.sourcePID, pr.iid, &gitlab.UpdateMergeRequestOptions{
+	options := &gitlab.UpdateMergeRequestOptions{
 		Title:       &prTitle,
 		Description: &updatedPR.Body,
-		ReviewerIDs: &reviewersIDs,
-		AssigneeIDs: &assigneesIDs,
-		Labels:      &labels,
-	})
+	}
+
+	// Reviewers, assignees and labels that are not set are left unchanged
+	if updatedPR.Reviewers != nil {
+		reviewersIDs, err := g.getUserIds(ctx, updatedPR.Reviewers)
+		if err != nil {
+			return nil, err
+		}
+		options.ReviewerIDs = &reviewersIDs
+	}
+	if updatedPR.Assignees != nil {
+		assigneesIDs, err := g.getUserIds(ctx, updatedPR.Assignees)
+		if err != nil {
+			return nil, err
+		}
+		options.AssigneeIDs = &assigneesIDs
+	}
+	if updatedPR.Labels != nil {
+		labels := gitlab.LabelOptions(updatedPR.Labels)
+		options.Labels = &labels
+	}
+
+	mr, _, err := g.glClient.MergeRequests.UpdateMergeRequest(pr.sourcePID, pr.iid, options)
 	if err != nil {
 		return nil, err
 	}
@@ -380,23 +390,88 @@ func (g *Gitlab) GetPullRequests(ctx context.Context, branchName string) ([]scm.
 			continue
 		}
 
-		prs = append(prs, convertMergeRequest(mr, project.Path, project.Namespace.FullPath))
+		pr := convertMergeRequest(mr, project.Path, project.Namespace.FullPath)
+		if mr.State == "opened" {
+			approvals, _, err := g.glClient.MergeRequestApprovals.GetConfiguration(project.ID, mr.IID, gitlab.WithContext(ctx))
+			if err != nil {
+				return nil, fmt.Errorf("could not get the approvals of %s!%d: %w", project.PathWithNamespace, mr.IID, err)
+			}
+			pr.details.Approvals = len(approvals.ApprovedBy)
+		}
+
+		prs = append(prs, pr)
 	}
 
 	return prs, nil
 }
 
 func convertMergeRequest(mr *gitlab.MergeRequest, repoName, ownerName string) pullRequest {
+	details := mergeRequestDetails(mr)
 	return pullRequest{
 		repoName:   repoName,
 		ownerName:  ownerName,
 		targetPID:  mr.TargetProjectID,
 		sourcePID:  mr.SourceProjectID,
 		branchName: mr.SourceBranch,
-		status:     pullRequestStatus(mr),
+		status:     scm.CombinePullRequestStatus(pullRequestStatus(mr), details),
 		iid:        mr.IID,
 		webURL:     mr.WebURL,
+		details:    details,
+	}
+}
+
+func mergeRequestDetails(mr *gitlab.MergeRequest) scm.PullRequestDetails {
+	details := scm.PullRequestDetails{
+		Number: int(mr.IID),
+		Title:  mr.Title,
+		Body:   mr.Description,
+		Labels: mr.Labels,
+		Draft:  mr.Draft,
+
+		BaseBranch: mr.TargetBranch,
+		HeadRef:    fmt.Sprintf("refs/merge-requests/%d/head", mr.IID),
+	}
+	if mr.Author != nil {
+		details.Author = mr.Author.Username
+	}
+	if mr.CreatedAt != nil {
+		details.CreatedAt = *mr.CreatedAt
+	}
+	if mr.UpdatedAt != nil {
+		details.UpdatedAt = *mr.UpdatedAt
+	}
+
+	if mr.Pipeline != nil {
+		check := scm.Check{
+			Name: "pipeline",
+			URL:  mr.Pipeline.WebURL,
+		}
+		switch mr.Pipeline.Status {
+		case "success", "skipped":
+			check.Status = scm.CheckStatusSuccess
+		case "failed", "canceled":
+			check.Status = scm.CheckStatusFailure
+		default:
+			check.Status = scm.CheckStatusPending
+		}
+		details.Checks = []scm.Check{check}
+	}
+
+	switch mr.DetailedMergeStatus {
+	case "not_approved":
+		details.ReviewDecision = scm.ReviewDecisionReviewRequired
+	case "requested_changes":
+		details.ReviewDecision = scm.ReviewDecisionChangesRequested
 	}
+
+	switch {
+	case mr.HasConflicts || mr.DetailedMergeStatus == "conflict":
+		details.Mergeability = scm.MergeabilityConflicting
+	case mr.DetailedMergeStatus != "" && mr.DetailedMergeStatus != "unchecked" && mr.DetailedMergeStatus != "checking":
+		details.Mergeability = scm.MergeabilityMergeable
+	}
+
+	return details
 }
 
 func (g *Gitlab) getPullRequest(ctx context.Context, branchName string, project *gitlab.Project) (*gitlab.MergeRequest, error) {
@@ -486,16 +561,179 @@ func (g *Gitlab) ClosePullRequest(ctx context.Context, pullReq scm.PullRequest)
 	_, _, err := g.glClient.MergeRequests.UpdateMergeRequest(pr.targetPID, pr.iid, &gitlab.UpdateMergeRequestOptions{
 		StateEvent: &stateEvent,
 	}, gitlab.WithContext(ctx))
+	return err
+}
+
+// DeletePullRequestBranch deletes the source branch of a merge request
+func (g *Gitlab) DeletePullRequestBranch(ctx context.Context, pullReq scm.PullRequest) error {
+	pr := pullReq.(pullRequest)
+
+	_, err := g.glClient.Branches.DeleteBranch(pr.sourcePID, pr.branchName, gitlab.WithContext(ctx))
+	return err
+}
+
+// UpdatePullRequestBranch rebases the source branch of a merge request onto its target branch.
+// GitLab can only rebase merge requests, so merging the target branch into the source branch is not supported
+func (g *Gitlab) UpdatePullRequestBranch(ctx context.Context, pullReq scm.PullRequest, rebase bool) error {
+	if !rebase {
+		return scm.ErrUpdateMethodNotSupported
+	}
+
+	pr := pullReq.(pullRequest)
+
+	_, err := g.glClient.MergeRequests.RebaseMergeRequest(pr.targetPID, pr.iid, nil, gitlab.WithContext(ctx))
 	if err != nil {
 		return err
 	}
 
-	_, err = g.glClient.Branches.DeleteBranch(pr.sourcePID, pr.branchName, gitlab.WithContext(ctx))
+	// The rebase is done in the background, wait for it to finish to know if it succeeded
+	includeRebaseInProgress := true
+	for i := 0; i < 60; i++ {
+		mr, _, err := g.glClient.MergeRequests.GetMergeRequest(pr.targetPID, pr.iid, &gitlab.GetMergeRequestsOptions{
+			IncludeRebaseInProgress: &includeRebaseInProgress,
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+
+		if !mr.RebaseInProgress {
+			switch {
+			case mr.MergeError == "":
+				return nil
+			case mr.HasConflicts:
+				return git.ErrConflict
+			}
+			return errors.New(mr.MergeError)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+
+	return errors.New("time waiting for the rebase to complete was exceeded")
+}
+
+// ReportsApprovals tells that the details of pull requests contain the number of approvals
+func (*Gitlab) ReportsApprovals() bool {
+	return true
+}
+
+// ApprovePullRequest approves a merge request
+func (g *Gitlab) ApprovePullRequest(ctx context.Context, pullReq scm.PullRequest) error {
+	pr := pullReq.(pullRequest)
+
+	user, err := g.getCurrentUser(ctx)
+	if err != nil {
+		return fmt.Errorf("could not get the authenticated user: %w", err)
+	}
+	if user.Username == pr.details.Author {
+		return scm.ErrOwnPullRequest
+	}
+
+	_, _, err = g.glClient.MergeRequestApprovals.ApproveMergeRequest(pr.targetPID, pr.iid, nil, gitlab.WithContext(ctx))
+	return err
+}
+
+// RetryFailedChecks retries each failed job of the head pipeline of a merge request.
+// The number of attempts of a job is the number of times a job with the same name has been run in the pipeline
+func (g *Gitlab) RetryFailedChecks(ctx context.Context, pullReq scm.PullRequest, maxAttempts int) error {
+	pr := pullReq.(pullRequest)
+
+	mr, _, err := g.glClient.MergeRequests.GetMergeRequest(pr.targetPID, pr.iid, nil, gitlab.WithContext(ctx))
 	if err != nil {
 		return err
 	}
+	pipeline := mr.HeadPipeline
+	if pipeline == nil || pipeline.Status != "failed" {
+		return scm.ErrNoFailedChecks
+	}
 
-	return nil
+	includeRetried := true
+	attempts := map[string]int{}
+	latest := map[string]*gitlab.Job{}
+	for i := 1; ; i++ {
+		jobs, _, err := g.glClient.Jobs.ListPipelineJobs(pipeline.ProjectID, pipeline.ID, &gitlab.ListJobsOptions{
+			ListOptions: gitlab.ListOptions{
+				PerPage: 100,
+				Page:    int64(i),
+			},
+			IncludeRetried: &includeRetried,
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+
+		for _, job := range jobs {
+			attempts[job.Name]++
+			if prev, ok := latest[job.Name]; !ok || job.ID > prev.ID {
+				latest[job.Name] = job
+			}
+		}
+
+		if len(jobs) < 100 {
+			break
+		}
+	}
+
+	// Only the failed jobs with attempts left are retried, retrying the whole pipeline would also retry the others
+	retried, maxedOut := 0, 0
+	for name, job := range latest {
+		if job.Status != "failed" || job.AllowFailure {
+			continue
+		}
+		if attempts[name] >= maxAttempts {
+			maxedOut++
+			continue
+		}
+
+		if _, _, err := g.glClient.Jobs.RetryJob(pipeline.ProjectID, job.ID, gitlab.WithContext(ctx)); err != nil {
+			return fmt.Errorf("could not retry the job %s: %w", name, err)
+		}
+		retried++
+	}
+	return scm.RetryResult(retried, maxedOut)
+}
+
+// PullRequestComments returns the notes of a merge request, system notes are left out
+func (g *Gitlab) PullRequestComments(ctx context.Context, pullReq scm.PullRequest) ([]string, error) {
+	pr := pullReq.(pullRequest)
+
+	var comments []string
+	for i := 1; ; i++ {
+		notes, _, err := g.glClient.Notes.ListMergeRequestNotes(pr.targetPID, pr.iid, &gitlab.ListMergeRequestNotesOptions{
+			ListOptions: gitlab.ListOptions{
+				PerPage: 100,
+				Page:    int64(i),
+			},
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, note := range notes {
+			if !note.System {
+				comments = append(comments, note.Body)
+			}
+		}
+
+		if len(notes) < 100 {
+			break
+		}
+	}
+	return comments, nil
+}
+
+// CommentPullRequest adds a note to a merge request
+func (g *Gitlab) CommentPullRequest(ctx context.Context, pullReq scm.PullRequest, comment string) error {
+	pr := pullReq.(pullRequest)
+
+	_, _, err := g.glClient.Notes.CreateMergeRequestNote(pr.targetPID, pr.iid, &gitlab.CreateMergeRequestNoteOptions{
+		Body: &comment,
+	}, gitlab.WithContext(ctx))
+	return err
 }
 
 // ForkRepository forks a project
@@ -547,6 +785,36 @@ func (g *Gitlab) ForkRepository(ctx context.Context, repo scm.Repository, newOwn
 	return nil, errors.New("time waiting for fork to complete was exceeded")
 }
 
+// RepositoryFiles returns the paths of all files and directories in the repository on the branch
+func (g *Gitlab) RepositoryFiles(ctx context.Context, repo scm.Repository, branch string) ([]string, error) {
+	r := repo.(repository)
+
+	var files []string
+	recursive := true
+	for i := 1; ; i++ {
+		nodes, _, err := g.glClient.Repositories.ListTree(r.pid, &gitlab.ListTreeOptions{
+			ListOptions: gitlab.ListOptions{
+				PerPage: 100,
+				Page:    int64(i),
+			},
+			Ref:       &branch,
+			Recursive: &recursive,
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, node := range nodes {
+			files = append(files, node.Path)
+		}
+
+		if len(nodes) < 100 {
+			break
+		}
+	}
+	return files, nil
+}
+
 func (g *Gitlab) getCurrentUser(ctx context.Context) (*gitlab.User, error) {
 	if g.currentUser != nil {
 		return g.currentUser, nil
@@ -5,6 +5,8 @@ import (
 	"net/url"
 
 	gitlab "gitlab.com/gitlab-org/api/client-go/v2"
+
+	"github.com/lindell/multi-gitter/internal/scm"
 )
 
 func (g *Gitlab) convertProject(project *gitlab.Project) (repository, error) {
@@ -27,9 +29,26 @@ func (g *Gitlab) convertProject(project *gitlab.Project) (repository, error) {
 		ownerName:     project.Namespace.FullPath,
 		defaultBranch: project.DefaultBranch,
 		shouldSquash:  shouldSquash(project),
+		metadata:      projectMetadata(project),
 	}, nil
 }
 
+func projectMetadata(project *gitlab.Project) scm.RepositoryMetadata {
+	metadata := scm.RepositoryMetadata{
+		Archived:   project.Archived,
+		Visibility: scm.Visibility(project.Visibility),
+		Topics:     project.Topics,
+		Fork:       project.ForkedFromProject != nil,
+	}
+	if project.LastActivityAt != nil {
+		metadata.PushedAt = *project.LastActivityAt
+	}
+	if project.Statistics != nil {
+		metadata.Size = project.Statistics.RepositorySize
+	}
+	return metadata
+}
+
 func shouldSquash(project *gitlab.Project) bool {
 	switch project.SquashOption {
 	case gitlab.SquashOptionAlways, gitlab.SquashOptionDefaultOn:
@@ -48,6 +67,7 @@ type repository struct {
 	ownerName     string
 	defaultBranch string
 	shouldSquash  bool
+	metadata      scm.RepositoryMetadata
 }
 
 func (r repository) CloneURL() string {
@@ -61,3 +81,7 @@ func (r repository) DefaultBranch() string {
 func (r repository) FullName() string {
 	return fmt.Sprintf("%s/%s", r.ownerName, r.name)
 }
+
+func (r repository) Metadata() scm.RepositoryMetadata {
+	return r.metadata
+}
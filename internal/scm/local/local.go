@@ -0,0 +1,523 @@
+// Package local contains a platform where repositories are git repositories in a local directory.
+// Pull requests are stored as branches together with a metadata file in the git directory of each repository
+package local
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+)
+
+// metadataPath is the path of the pull request metadata file, relative to the git directory of a repository
+var metadataPath = filepath.Join("multi-gitter", "pull-requests.json")
+
+// Local is a platform where the repositories are stored in a directory
+type Local struct {
+	dir          string
+	repositories []string
+	mergeTypes   []scm.MergeType
+
+	lock sync.Mutex
+}
+
+// New creates a new local platform. Repositories are all git repositories in dir (or in sub-directories of dir).
+// If repositories is set, only the repositories with those names are used
+func New(dir string, repositories []string, mergeTypes []scm.MergeType) (*Local, error) {
+	if dir == "" {
+		return nil, errors.New("no directory set")
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if stat, err := os.Stat(absDir); err != nil {
+		return nil, errors.Wrap(err, "could not read the repository directory")
+	} else if !stat.IsDir() {
+		return nil, errors.Errorf("%s is not a directory", dir)
+	}
+
+	return &Local{
+		dir:          absDir,
+		repositories: repositories,
+		mergeTypes:   mergeTypes,
+	}, nil
+}
+
+// GetRepositories finds all repositories in the directory
+func (l *Local) GetRepositories(_ context.Context) ([]scm.Repository, error) {
+	repos, err := l.findRepositories()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]scm.Repository, len(repos))
+	for i := range repos {
+		ret[i] = repos[i]
+	}
+	return ret, nil
+}
+
+func (l *Local) findRepositories() ([]repository, error) {
+	var repos []repository
+
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		path := filepath.Join(l.dir, entry.Name())
+		if gitDir, _ := findGitDir(path); gitDir != "" {
+			repo, err := l.newRepository(path, entry.Name())
+			if err != nil {
+				return nil, err
+			}
+			repos = append(repos, repo)
+			continue
+		}
+
+		// Repositories may also be placed in directories named after their owner
+		subEntries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, subEntry := range subEntries {
+			subPath := filepath.Join(path, subEntry.Name())
+			if !subEntry.IsDir() {
+				continue
+			}
+			if gitDir, _ := findGitDir(subPath); gitDir != "" {
+				repo, err := l.newRepository(subPath, entry.Name()+"/"+subEntry.Name())
+				if err != nil {
+					return nil, err
+				}
+				repos = append(repos, repo)
+			}
+		}
+	}
+
+	if len(l.repositories) == 0 {
+		return repos, nil
+	}
+
+	filtered := make([]repository, 0, len(l.repositories))
+	for _, name := range l.repositories {
+		i := slices.IndexFunc(repos, func(r repository) bool { return r.name == name })
+		if i == -1 {
+			return nil, errors.Errorf("could not find the repository %s in %s", name, l.dir)
+		}
+		filtered = append(filtered, repos[i])
+	}
+	return filtered, nil
+}
+
+func (l *Local) newRepository(path, name string) (repository, error) {
+	gitDir, bare := findGitDir(path)
+
+	defaultBranch, err := runGit(path, nil, "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return repository{}, errors.WithMessagef(err, "could not get the default branch of %s", name)
+	}
+
+	return repository{
+		path:          path,
+		gitDir:        gitDir,
+		bare:          bare,
+		name:          name,
+		defaultBranch: defaultBranch,
+	}, nil
+}
+
+// CreatePullRequest records a new pull request
+func (l *Local) CreatePullRequest(_ context.Context, repo scm.Repository, _ scm.Repository, newPR scm.NewPullRequest) (scm.PullRequest, error) {
+	r := repo.(repository)
+
+	if _, err := runGit(r.path, nil, "rev-parse", "--verify", "refs/heads/"+newPR.Head); err != nil {
+		return nil, errors.Errorf("the branch %s does not exist", newPR.Head)
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	prs, err := readPullRequests(r)
+	if err != nil {
+		return nil, err
+	}
+
+	number := 1
+	for _, pr := range prs {
+		number = max(number, pr.Number+1)
+	}
+
+	now := time.Now()
+	data := pullRequestData{
+		Number:        number,
+		Title:         newPR.Title,
+		Body:          newPR.Body,
+		Head:          newPR.Head,
+		Base:          newPR.Base,
+		Reviewers:     newPR.Reviewers,
+		TeamReviewers: newPR.TeamReviewers,
+		Assignees:     newPR.Assignees,
+		Labels:        newPR.Labels,
+		Draft:         newPR.Draft,
+		AutoMerge:     newPR.AutoMerge,
+		State:         stateOpen,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	prs = append(prs, data)
+
+	if err := writePullRequests(r, prs); err != nil {
+		return nil, err
+	}
+
+	return pullRequest{repository: r, data: data}, nil
+}
+
+// UpdatePullRequest updates an existing pull request
+func (l *Local) UpdatePullRequest(_ context.Context, _ scm.Repository, pullReq scm.PullRequest, updatedPR scm.NewPullRequest) (scm.PullRequest, error) {
+	pr := pullReq.(pullRequest)
+
+	var updated pullRequestData
+	err := l.updatePullRequest(pr, func(data *pullRequestData) error {
+		data.Title = updatedPR.Title
+		data.Body = updatedPR.Body
+		// Like on the other platforms, reviewers, assignees, labels and the draft state that are not set are
+		// left unchanged, and auto-merge can only be enabled
+		if updatedPR.Reviewers != nil {
+			data.Reviewers = updatedPR.Reviewers
+		}
+		if updatedPR.TeamReviewers != nil {
+			data.TeamReviewers = updatedPR.TeamReviewers
+		}
+		if updatedPR.Assignees != nil {
+			data.Assignees = updatedPR.Assignees
+		}
+		if updatedPR.Labels != nil {
+			data.Labels = updatedPR.Labels
+		}
+		if updatedPR.SetDraft != nil {
+			data.Draft = *updatedPR.SetDraft
+		}
+		data.AutoMerge = data.AutoMerge || updatedPR.AutoMerge
+		updated = *data
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pullRequest{repository: pr.repository, data: updated}, nil
+}
+
+// GetPullRequests gets the latest pull request of a branch in every repository
+func (l *Local) GetPullRequests(_ context.Context, branchName string) ([]scm.PullRequest, error) {
+	repos, err := l.findRepositories()
+	if err != nil {
+		return nil, err
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	var ret []scm.PullRequest
+	for _, repo := range repos {
+		prs, err := readPullRequests(repo)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := len(prs) - 1; i >= 0; i-- {
+			if prs[i].Head == branchName {
+				ret = append(ret, pullRequest{repository: repo, data: prs[i]})
+				break
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+// GetOpenPullRequest gets an open pull request of a branch in a repository
+func (l *Local) GetOpenPullRequest(_ context.Context, repo scm.Repository, branchName string) (scm.PullRequest, error) {
+	r := repo.(repository)
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	prs, err := readPullRequests(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pr := range prs {
+		if pr.Head == branchName && pr.State == stateOpen {
+			return pullRequest{repository: r, data: pr}, nil
+		}
+	}
+	return nil, nil
+}
+
+// MergePullRequest merges the head branch of a pull request into its base branch
+func (l *Local) MergePullRequest(_ context.Context, pullReq scm.PullRequest) error {
+	pr := pullReq.(pullRequest)
+
+	return l.updatePullRequest(pr, func(data *pullRequestData) error {
+		if data.State != stateOpen {
+			return errors.Errorf("the pull request is %s", data.State)
+		}
+
+		if err := l.merge(pr.repository, *data); err != nil {
+			return err
+		}
+
+		data.State = stateMerged
+		return nil
+	})
+}
+
+func (l *Local) merge(repo repository, data pullRequestData) error {
+	mergeType, err := l.mergeType()
+	if err != nil {
+		return err
+	}
+
+	baseCommit, err := runGit(repo.path, nil, "rev-parse", "--verify", "refs/heads/"+data.Base)
+	if err != nil {
+		return errors.WithMessagef(err, "could not find the base branch %s", data.Base)
+	}
+	headCommit, err := runGit(repo.path, nil, "rev-parse", "--verify", "refs/heads/"+data.Head)
+	if err != nil {
+		return errors.WithMessagef(err, "could not find the branch %s", data.Head)
+	}
+
+	tree, err := runGit(repo.path, nil, "merge-tree", "--write-tree", "--no-messages", baseCommit, headCommit)
+	if err != nil {
+		return errors.WithMessage(err, "the pull request could not be merged, there might be conflicts")
+	}
+	tree, _, _ = strings.Cut(tree, "\n")
+
+	env, err := identityEnv(repo.path)
+	if err != nil {
+		return err
+	}
+
+	var commit string
+	switch mergeType {
+	case scm.MergeTypeSquash:
+		message := data.Title
+		if data.Body != "" {
+			message += "\n\n" + data.Body
+		}
+		commit, err = runGit(repo.path, env, "commit-tree", tree, "-p", baseCommit, "-m", message)
+	default:
+		message := fmt.Sprintf("Merge pull request #%d from %s\n\n%s", data.Number, data.Head, data.Title)
+		commit, err = runGit(repo.path, env, "commit-tree", tree, "-p", baseCommit, "-p", headCommit, "-m", message)
+	}
+	if err != nil {
+		return errors.WithMessage(err, "could not create the merge commit")
+	}
+
+	// If the base branch is checked out, the working tree has to be updated together with the branch
+	if !repo.bare {
+		if currentBranch, err := runGit(repo.path, nil, "symbolic-ref", "--short", "HEAD"); err == nil && currentBranch == data.Base {
+			_, err := runGit(repo.path, nil, "merge", "--ff-only", commit)
+			return errors.WithMessage(err, "could not update the checked out base branch")
+		}
+	}
+
+	_, err = runGit(repo.path, nil, "update-ref", "refs/heads/"+data.Base, commit, baseCommit)
+	return errors.WithMessage(err, "could not update the base branch")
+}
+
+// mergeType returns the first of the configured merge types that is supported
+func (l *Local) mergeType() (scm.MergeType, error) {
+	if len(l.mergeTypes) == 0 {
+		return scm.MergeTypeMerge, nil
+	}
+	for _, mt := range l.mergeTypes {
+		if mt == scm.MergeTypeMerge || mt == scm.MergeTypeSquash {
+			return mt, nil
+		}
+	}
+	return scm.MergeTypeUnknown, errors.New("none of the merge types are supported by the local platform, use merge or squash")
+}
+
+// ClosePullRequest closes a pull request
+func (l *Local) ClosePullRequest(_ context.Context, pullReq scm.PullRequest) error {
+	return l.updatePullRequest(pullReq.(pullRequest), func(data *pullRequestData) error {
+		data.State = stateClosed
+		return nil
+	})
+}
+
+// DeletePullRequestBranch deletes the head branch of a pull request, if it still exists
+func (l *Local) DeletePullRequestBranch(_ context.Context, pullReq scm.PullRequest) error {
+	pr := pullReq.(pullRequest)
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if _, err := runGit(pr.repository.path, nil, "rev-parse", "--verify", "refs/heads/"+pr.data.Head); err != nil {
+		return nil
+	}
+
+	_, err := runGit(pr.repository.path, nil, "branch", "-D", pr.data.Head)
+	return errors.WithMessagef(err, "could not delete the branch %s", pr.data.Head)
+}
+
+// PullRequestComments returns the comments of a pull request
+func (l *Local) PullRequestComments(_ context.Context, pullReq scm.PullRequest) ([]string, error) {
+	pr := pullReq.(pullRequest)
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	prs, err := readPullRequests(pr.repository)
+	if err != nil {
+		return nil, err
+	}
+
+	i := slices.IndexFunc(prs, func(data pullRequestData) bool { return data.Number == pr.data.Number })
+	if i == -1 {
+		return nil, errors.Errorf("could not find the pull request %s", pr.String())
+	}
+	return prs[i].Comments, nil
+}
+
+// CommentPullRequest adds a comment to a pull request
+func (l *Local) CommentPullRequest(_ context.Context, pullReq scm.PullRequest, comment string) error {
+	return l.updatePullRequest(pullReq.(pullRequest), func(data *pullRequestData) error {
+		data.Comments = append(data.Comments, comment)
+		return nil
+	})
+}
+
+// ForkRepository is not supported by the local platform
+func (l *Local) ForkRepository(_ context.Context, _ scm.Repository, _ string) (scm.Repository, error) {
+	return nil, errors.New("forking is not supported by the local platform")
+}
+
+// RepositoryFiles returns the paths of all files and directories in the repository on the branch
+func (l *Local) RepositoryFiles(_ context.Context, repo scm.Repository, branch string) ([]string, error) {
+	r := repo.(repository)
+
+	out, err := runGit(r.gitDir, nil, "ls-tree", "-r", "-t", "--name-only", "refs/heads/"+branch)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// updatePullRequest reads the current state of a pull request, changes it and stores it
+func (l *Local) updatePullRequest(pr pullRequest, fn func(data *pullRequestData) error) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	prs, err := readPullRequests(pr.repository)
+	if err != nil {
+		return err
+	}
+
+	i := slices.IndexFunc(prs, func(data pullRequestData) bool { return data.Number == pr.data.Number })
+	if i == -1 {
+		return errors.Errorf("could not find the pull request %s", pr.String())
+	}
+
+	if err := fn(&prs[i]); err != nil {
+		return err
+	}
+	prs[i].UpdatedAt = time.Now()
+
+	return writePullRequests(pr.repository, prs)
+}
+
+func readPullRequests(repo repository) ([]pullRequestData, error) {
+	data, err := os.ReadFile(filepath.Join(repo.gitDir, metadataPath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "could not read the pull requests of %s", repo.name)
+	}
+
+	var prs []pullRequestData
+	if err := json.Unmarshal(data, &prs); err != nil {
+		return nil, errors.Wrapf(err, "could not parse the pull requests of %s", repo.name)
+	}
+
+	sort.Slice(prs, func(i, j int) bool { return prs[i].Number < prs[j].Number })
+	return prs, nil
+}
+
+func writePullRequests(repo repository, prs []pullRequestData) error {
+	path := filepath.Join(repo.gitDir, metadataPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(prs, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
+
+// identityEnv returns environment variables with a committer identity, if none is configured for the repository
+func identityEnv(dir string) ([]string, error) {
+	if _, err := runGit(dir, nil, "var", "GIT_COMMITTER_IDENT"); err == nil {
+		return nil, nil
+	}
+	return []string{
+		"GIT_AUTHOR_NAME=multi-gitter",
+		"GIT_AUTHOR_EMAIL=multi-gitter@localhost",
+		"GIT_COMMITTER_NAME=multi-gitter",
+		"GIT_COMMITTER_EMAIL=multi-gitter@localhost",
+	}, nil
+}
+
+// runGit runs a git command in a directory, and returns the trimmed output
+func runGit(dir string, env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(stdout.String())
+		}
+		if msg == "" {
+			return "", errors.Wrapf(err, "git %s", args[0])
+		}
+		return "", errors.Errorf("git %s: %s", args[0], msg)
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
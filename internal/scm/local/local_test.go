@@ -0,0 +1,155 @@
+package local_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/internal/scm/local"
+)
+
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	return strings.TrimSpace(string(out))
+}
+
+// createRepo creates a non-bare repository with a commit on main and a commit on the feature branch
+func createRepo(t *testing.T, path string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(path, 0700))
+	git(t, path, "init", "--initial-branch=main")
+	require.NoError(t, os.WriteFile(filepath.Join(path, "file.txt"), []byte("apples"), 0600))
+	git(t, path, "add", ".")
+	git(t, path, "commit", "-m", "Initial commit")
+	git(t, path, "branch", "feature")
+	git(t, path, "checkout", "feature")
+	require.NoError(t, os.WriteFile(filepath.Join(path, "file.txt"), []byte("bananas"), 0600))
+	git(t, path, "commit", "-am", "Change apples to bananas")
+	git(t, path, "checkout", "main")
+}
+
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	createRepo(t, filepath.Join(dir, "repo1"))
+	createRepo(t, filepath.Join(dir, "owner", "repo2"))
+	git(t, dir, "clone", "--bare", filepath.Join(dir, "repo1"), filepath.Join(dir, "owner", "bare.git"))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "not-a-repo"), 0700))
+
+	vc, err := local.New(dir, nil, nil)
+	require.NoError(t, err)
+
+	repos, err := vc.GetRepositories(ctx)
+	require.NoError(t, err)
+	names := make([]string, len(repos))
+	for i, repo := range repos {
+		names[i] = repo.FullName()
+		assert.Equal(t, "main", repo.DefaultBranch())
+	}
+	assert.Equal(t, []string{"owner/bare.git", "owner/repo2", "repo1"}, names)
+
+	for _, repo := range repos {
+		files, err := vc.RepositoryFiles(ctx, repo, "main")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"file.txt"}, files)
+	}
+
+	for _, repo := range repos {
+		pr, err := vc.CreatePullRequest(ctx, repo, repo, scm.NewPullRequest{
+			Title: "Use bananas",
+			Head:  "feature",
+			Base:  repo.DefaultBranch(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, repo.FullName()+" #1", pr.String())
+		assert.Equal(t, scm.PullRequestStatusSuccess, pr.Status())
+
+		openPR, err := vc.GetOpenPullRequest(ctx, repo, "feature")
+		require.NoError(t, err)
+		require.NotNil(t, openPR)
+		assert.Equal(t, pr.String(), openPR.String())
+	}
+
+	prs, err := vc.GetPullRequests(ctx, "feature")
+	require.NoError(t, err)
+	require.Len(t, prs, 3)
+
+	// Merge the bare repository and the checked out repository, close the last one
+	require.NoError(t, vc.MergePullRequest(ctx, prs[0]))
+	require.NoError(t, vc.MergePullRequest(ctx, prs[2]))
+	require.NoError(t, vc.ClosePullRequest(ctx, prs[1]))
+	require.NoError(t, vc.CommentPullRequest(ctx, prs[1], "Not needed anymore"))
+	comments, err := vc.PullRequestComments(ctx, prs[1])
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Not needed anymore"}, comments)
+	require.NoError(t, vc.DeletePullRequestBranch(ctx, prs[1]))
+	require.NoError(t, vc.DeletePullRequestBranch(ctx, prs[1]), "deleting an already deleted branch should not fail")
+
+	assert.Equal(t, "bananas", git(t, filepath.Join(dir, "owner", "bare.git"), "show", "main:file.txt"))
+	content, err := os.ReadFile(filepath.Join(dir, "repo1", "file.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "bananas", string(content), "the working tree of the checked out base branch should be updated")
+	assert.Equal(t, "apples", git(t, filepath.Join(dir, "owner", "repo2"), "show", "main:file.txt"))
+	assert.Empty(t, git(t, filepath.Join(dir, "owner", "repo2"), "branch", "--list", "feature"))
+
+	prs, err = vc.GetPullRequests(ctx, "feature")
+	require.NoError(t, err)
+	require.Len(t, prs, 3)
+	assert.Equal(t, scm.PullRequestStatusMerged, prs[0].Status())
+	assert.Equal(t, scm.PullRequestStatusClosed, prs[1].Status())
+	assert.Equal(t, scm.PullRequestStatusMerged, prs[2].Status())
+
+	assert.Error(t, vc.MergePullRequest(ctx, prs[1]), "a closed pull request should not be mergeable")
+
+	openPR, err := vc.GetOpenPullRequest(ctx, repos[1], "feature")
+	require.NoError(t, err)
+	assert.Nil(t, openPR)
+}
+
+func TestLocalRepositoryListing(t *testing.T) {
+	dir := t.TempDir()
+	createRepo(t, filepath.Join(dir, "repo1"))
+	createRepo(t, filepath.Join(dir, "repo2"))
+
+	vc, err := local.New(dir, []string{"repo2"}, nil)
+	require.NoError(t, err)
+	repos, err := vc.GetRepositories(context.Background())
+	require.NoError(t, err)
+	require.Len(t, repos, 1)
+	assert.Equal(t, "repo2", repos[0].FullName())
+
+	vc, err = local.New(dir, []string{"missing"}, nil)
+	require.NoError(t, err)
+	_, err = vc.GetRepositories(context.Background())
+	assert.ErrorContains(t, err, "could not find the repository missing")
+}
+
+func TestLocalUnsupportedMergeType(t *testing.T) {
+	dir := t.TempDir()
+	createRepo(t, filepath.Join(dir, "repo"))
+
+	vc, err := local.New(dir, nil, []scm.MergeType{scm.MergeTypeRebase})
+	require.NoError(t, err)
+	repos, err := vc.GetRepositories(context.Background())
+	require.NoError(t, err)
+
+	pr, err := vc.CreatePullRequest(context.Background(), repos[0], repos[0], scm.NewPullRequest{Title: "Test", Head: "feature", Base: "main"})
+	require.NoError(t, err)
+	assert.ErrorContains(t, vc.MergePullRequest(context.Background(), pr), "merge types are supported")
+}
@@ -0,0 +1,109 @@
+package local
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+)
+
+// The states a pull request can be in
+const (
+	stateOpen   = "open"
+	stateMerged = "merged"
+	stateClosed = "closed"
+)
+
+// The results of checks that can be set in the metadata file, to simulate checks of a real platform
+const (
+	checksSuccess = "success"
+	checksPending = "pending"
+	checksError   = "error"
+)
+
+// pullRequestData is a pull request as it's stored in the metadata file of a repository
+type pullRequestData struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body,omitempty"`
+	Head   string `json:"head"`
+	Base   string `json:"base"`
+
+	Reviewers     []string `json:"reviewers,omitempty"`
+	TeamReviewers []string `json:"team_reviewers,omitempty"`
+	Assignees     []string `json:"assignees,omitempty"`
+	Labels        []string `json:"labels,omitempty"`
+	Draft         bool     `json:"draft,omitempty"`
+	AutoMerge     bool     `json:"auto_merge,omitempty"`
+
+	State string `json:"state"`
+	// Checks can be changed manually to simulate the checks of a real platform, it defaults to success
+	Checks   string   `json:"checks,omitempty"`
+	Comments []string `json:"comments,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type pullRequest struct {
+	repository repository
+	data       pullRequestData
+}
+
+func (pr pullRequest) String() string {
+	return fmt.Sprintf("%s #%d", pr.repository.name, pr.data.Number)
+}
+
+// RepositoryName returns the full name of the repository the pull request targets
+func (pr pullRequest) RepositoryName() string {
+	return pr.repository.name
+}
+
+func (pr pullRequest) Status() scm.PullRequestStatus {
+	switch pr.data.State {
+	case stateMerged:
+		return scm.PullRequestStatusMerged
+	case stateClosed:
+		return scm.PullRequestStatusClosed
+	}
+
+	status := scm.PullRequestStatusUnknown
+	switch pr.data.Checks {
+	case "", checksSuccess:
+		status = scm.PullRequestStatusSuccess
+	case checksPending:
+		status = scm.PullRequestStatusPending
+	case checksError:
+		status = scm.PullRequestStatusError
+	}
+	return scm.CombinePullRequestStatus(status, pr.Details())
+}
+
+func (pr pullRequest) Details() scm.PullRequestDetails {
+	details := scm.PullRequestDetails{
+		Number:    pr.data.Number,
+		Title:     pr.data.Title,
+		Body:      pr.data.Body,
+		CreatedAt: pr.data.CreatedAt,
+		UpdatedAt: pr.data.UpdatedAt,
+		Labels:    pr.data.Labels,
+		Draft:     pr.data.Draft,
+
+		BaseBranch: pr.data.Base,
+	}
+
+	if pr.data.State == stateOpen {
+		check := scm.Check{Name: "checks"}
+		switch pr.data.Checks {
+		case "", checksSuccess:
+			check.Status = scm.CheckStatusSuccess
+		case checksPending:
+			check.Status = scm.CheckStatusPending
+		default:
+			check.Status = scm.CheckStatusFailure
+		}
+		details.Checks = []scm.Check{check}
+	}
+
+	return details
+}
@@ -0,0 +1,42 @@
+package local
+
+import (
+	"os"
+	"path/filepath"
+)
+
+type repository struct {
+	path          string // The absolute path of the repository
+	gitDir        string // The path of the git directory, the same as path for bare repositories
+	bare          bool
+	name          string
+	defaultBranch string
+}
+
+func (r repository) CloneURL() string {
+	return r.path
+}
+
+func (r repository) DefaultBranch() string {
+	return r.defaultBranch
+}
+
+func (r repository) FullName() string {
+	return r.name
+}
+
+// findGitDir returns the git directory of a repository in the path, or an empty string if the path is not a repository
+func findGitDir(path string) (gitDir string, bare bool) {
+	dotGit := filepath.Join(path, ".git")
+	if stat, err := os.Stat(dotGit); err == nil && stat.IsDir() {
+		return dotGit, false
+	}
+
+	if _, err := os.Stat(filepath.Join(path, "HEAD")); err != nil {
+		return "", false
+	}
+	if stat, err := os.Stat(filepath.Join(path, "objects")); err != nil || !stat.IsDir() {
+		return "", false
+	}
+	return path, true
+}
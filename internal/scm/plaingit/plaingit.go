@@ -0,0 +1,171 @@
+// Package plaingit contains a platform for git servers without any API, where repositories are defined by their clone URL.
+// Changes can only be pushed, all pull request operations are unsupported
+package plaingit
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/pkg/errors"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+)
+
+// ErrPullRequestsNotSupported is returned by all pull request operations
+var ErrPullRequestsNotSupported = errors.New("pull requests are not supported by the git platform, use --push-only or --skip-pr to push the changes")
+
+// PlainGit is a platform where repositories are only defined by their clone URLs
+type PlainGit struct {
+	urls []string
+}
+
+// New creates a new plain git platform from a list of clone URLs
+func New(urls []string) (*PlainGit, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("no repositories set, the clone URLs of the repositories have to be set with --repo")
+	}
+
+	for _, url := range urls {
+		if _, err := transport.NewEndpoint(url); err != nil {
+			return nil, errors.WithMessagef(err, "invalid clone URL %s", url)
+		}
+	}
+
+	return &PlainGit{
+		urls: urls,
+	}, nil
+}
+
+// GetRepositories returns the repositories of the clone URLs. The remote of each repository is contacted to find its default branch
+func (g *PlainGit) GetRepositories(ctx context.Context) ([]scm.Repository, error) {
+	repos := make([]scm.Repository, 0, len(g.urls))
+	for _, url := range g.urls {
+		defaultBranch, err := getDefaultBranch(ctx, url)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "could not get the default branch of %s", url)
+		}
+
+		repos = append(repos, repository{
+			url:           url,
+			name:          repositoryName(url),
+			defaultBranch: defaultBranch,
+		})
+	}
+	return repos, nil
+}
+
+// getDefaultBranch finds the branch HEAD points to on the remote. The git CLI is used, so that the remote
+// is accessed with the same credentials as when cloning, like ssh keys and credential helpers
+func getDefaultBranch(ctx context.Context, url string) (string, error) {
+	stderr := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, "git", "ls-remote", "--symref", "--", url, "HEAD", "refs/heads/*")
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if matches := errRe.FindStringSubmatch(stderr.String()); matches != nil {
+			return "", errors.New(matches[3])
+		}
+		return "", errors.Wrap(err, "could not list the references of the remote")
+	}
+
+	return parseDefaultBranch(string(out))
+}
+
+var errRe = regexp.MustCompile(`(^|\n)(error|fatal): (.+)`)
+
+// parseDefaultBranch finds the branch HEAD points to in the output of git ls-remote --symref
+func parseDefaultBranch(lsRemote string) (string, error) {
+	var headHash string
+	var branches, hashes []string
+	for _, line := range strings.Split(lsRemote, "\n") {
+		value, ref, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		if !ok {
+			continue
+		}
+
+		switch {
+		case ref == "HEAD" && strings.HasPrefix(value, "ref: "):
+			return strings.TrimPrefix(strings.TrimPrefix(value, "ref: "), "refs/heads/"), nil
+		case ref == "HEAD":
+			headHash = value
+		case strings.HasPrefix(ref, "refs/heads/"):
+			branches = append(branches, strings.TrimPrefix(ref, "refs/heads/"))
+			hashes = append(hashes, value)
+		}
+	}
+	if headHash == "" {
+		return "", errors.New("the repository does not have a HEAD")
+	}
+
+	// Old servers might not advertise what HEAD points to, use a branch pointing to the same commit instead
+	var candidates []string
+	for i, branch := range branches {
+		if hashes[i] == headHash {
+			candidates = append(candidates, branch)
+		}
+	}
+	for _, name := range []string{"main", "master"} {
+		if slices.Contains(candidates, name) {
+			return name, nil
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates[0], nil
+	}
+	return "", errors.New("could not determine what branch HEAD points to")
+}
+
+// repositoryName creates a readable name from a clone URL, the path without a .git suffix
+func repositoryName(url string) string {
+	endpoint, err := transport.NewEndpoint(url)
+	if err != nil {
+		return url
+	}
+
+	name := strings.Trim(endpoint.Path, "/")
+	name = strings.TrimSuffix(name, ".git")
+	if name == "" {
+		return url
+	}
+	return name
+}
+
+// CreatePullRequest is not supported
+func (g *PlainGit) CreatePullRequest(context.Context, scm.Repository, scm.Repository, scm.NewPullRequest) (scm.PullRequest, error) {
+	return nil, ErrPullRequestsNotSupported
+}
+
+// UpdatePullRequest is not supported
+func (g *PlainGit) UpdatePullRequest(context.Context, scm.Repository, scm.PullRequest, scm.NewPullRequest) (scm.PullRequest, error) {
+	return nil, ErrPullRequestsNotSupported
+}
+
+// GetPullRequests is not supported
+func (g *PlainGit) GetPullRequests(context.Context, string) ([]scm.PullRequest, error) {
+	return nil, ErrPullRequestsNotSupported
+}
+
+// GetOpenPullRequest is not supported
+func (g *PlainGit) GetOpenPullRequest(context.Context, scm.Repository, string) (scm.PullRequest, error) {
+	return nil, ErrPullRequestsNotSupported
+}
+
+// MergePullRequest is not supported
+func (g *PlainGit) MergePullRequest(context.Context, scm.PullRequest) error {
+	return ErrPullRequestsNotSupported
+}
+
+// ClosePullRequest is not supported
+func (g *PlainGit) ClosePullRequest(context.Context, scm.PullRequest) error {
+	return ErrPullRequestsNotSupported
+}
+
+// ForkRepository is not supported
+func (g *PlainGit) ForkRepository(context.Context, scm.Repository, string) (scm.Repository, error) {
+	return nil, errors.New("forking is not supported by the git platform")
+}
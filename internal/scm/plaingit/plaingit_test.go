@@ -0,0 +1,120 @@
+package plaingit
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_repositoryName(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "git@git.example.com:team/repo.git", want: "team/repo"},
+		{url: "git@git.example.com:repo", want: "repo"},
+		{url: "ssh://git@git.example.com:2222/srv/git/repo.git", want: "srv/git/repo"},
+		{url: "https://git.example.com/team/repo.git", want: "team/repo"},
+		{url: "https://git.example.com/", want: "https://git.example.com/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			assert.Equal(t, tt.want, repositoryName(tt.url))
+		})
+	}
+}
+
+func TestGetRepositories(t *testing.T) {
+	dir := t.TempDir()
+	for _, branch := range []string{"main", "develop"} {
+		path := filepath.Join(dir, branch)
+		require.NoError(t, os.MkdirAll(path, 0700))
+		cmd := exec.Command("git", "init", "--initial-branch="+branch)
+		cmd.Dir = path
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		cmd = exec.Command("git", "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--allow-empty", "-m", "Initial commit")
+		cmd.Dir = path
+		out, err = cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	vc, err := New([]string{filepath.Join(dir, "main"), filepath.Join(dir, "develop")})
+	require.NoError(t, err)
+
+	repos, err := vc.GetRepositories(context.Background())
+	require.NoError(t, err)
+	require.Len(t, repos, 2)
+	assert.Equal(t, filepath.Join(dir, "main"), repos[0].CloneURL())
+	assert.Equal(t, "main", repos[0].DefaultBranch())
+	assert.Equal(t, "develop", repos[1].DefaultBranch())
+
+	vc, err = New([]string{filepath.Join(dir, "does-not-exist")})
+	require.NoError(t, err)
+	_, err = vc.GetRepositories(context.Background())
+	assert.ErrorContains(t, err, "does not appear to be a git repository")
+}
+
+func Test_parseDefaultBranch(t *testing.T) {
+	tests := []struct {
+		name     string
+		lsRemote string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "symbolic HEAD",
+			lsRemote: "ref: refs/heads/trunk\tHEAD\nabc\tHEAD\nabc\trefs/heads/other\nabc\trefs/heads/trunk\n",
+			want:     "trunk",
+		},
+		{
+			name:     "main is preferred when HEAD is not symbolic",
+			lsRemote: "abc\tHEAD\nabc\trefs/heads/feature\nabc\trefs/heads/main\ndef\trefs/heads/master\n",
+			want:     "main",
+		},
+		{
+			name:     "branch with the same commit as HEAD",
+			lsRemote: "abc\tHEAD\ndef\trefs/heads/main\nabc\trefs/heads/develop\n",
+			want:     "develop",
+		},
+		{
+			name:     "empty repository",
+			lsRemote: "",
+			wantErr:  "the repository does not have a HEAD",
+		},
+		{
+			name:     "no branch with the same commit as HEAD",
+			lsRemote: "abc\tHEAD\ndef\trefs/heads/main\n",
+			wantErr:  "could not determine what branch HEAD points to",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDefaultBranch(tt.lsRemote)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPullRequestsNotSupported(t *testing.T) {
+	_, err := New(nil)
+	assert.Error(t, err)
+
+	vc, err := New([]string{"git@git.example.com:repo.git"})
+	require.NoError(t, err)
+
+	_, err = vc.GetPullRequests(context.Background(), "branch")
+	assert.ErrorIs(t, err, ErrPullRequestsNotSupported)
+	assert.ErrorIs(t, vc.MergePullRequest(context.Background(), nil), ErrPullRequestsNotSupported)
+	assert.ErrorIs(t, vc.ClosePullRequest(context.Background(), nil), ErrPullRequestsNotSupported)
+}
@@ -0,0 +1,19 @@
+package plaingit
+
+type repository struct {
+	url           string
+	name          string
+	defaultBranch string
+}
+
+func (r repository) CloneURL() string {
+	return r.url
+}
+
+func (r repository) DefaultBranch() string {
+	return r.defaultBranch
+}
+
+func (r repository) FullName() string {
+	return r.name
+}
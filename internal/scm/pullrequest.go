@@ -1,8 +1,10 @@
 package scm
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // NewPullRequest is the data needed to create a new pull request
@@ -18,6 +20,10 @@ type NewPullRequest struct {
 	Draft         bool
 	AutoMerge     bool
 	Labels        []string
+
+	// SetDraft changes the draft state of an existing pull request when it's updated, if set.
+	// Platforms that mark drafts in the title use Draft instead, since the title is always updated
+	SetDraft *bool
 }
 
 // PullRequestStatus is the status of a pull request, including statuses of the last commit
@@ -31,8 +37,26 @@ const (
 	PullRequestStatusError
 	PullRequestStatusMerged
 	PullRequestStatusClosed
+	PullRequestStatusNeedsReview
+	PullRequestStatusChangesRequested
+	PullRequestStatusConflicting
+	PullRequestStatusDraft
 )
 
+// AllPullRequestStatuses are all pull request statuses, in the order they are listed to users
+var AllPullRequestStatuses = []PullRequestStatus{
+	PullRequestStatusSuccess,
+	PullRequestStatusPending,
+	PullRequestStatusError,
+	PullRequestStatusNeedsReview,
+	PullRequestStatusChangesRequested,
+	PullRequestStatusConflicting,
+	PullRequestStatusDraft,
+	PullRequestStatusMerged,
+	PullRequestStatusClosed,
+	PullRequestStatusUnknown,
+}
+
 func (s PullRequestStatus) String() string {
 	switch s {
 	case PullRequestStatusUnknown:
@@ -47,16 +71,148 @@ func (s PullRequestStatus) String() string {
 		return "Merged"
 	case PullRequestStatusClosed:
 		return "Closed"
+	case PullRequestStatusNeedsReview:
+		return "NeedsReview"
+	case PullRequestStatusChangesRequested:
+		return "ChangesRequested"
+	case PullRequestStatusConflicting:
+		return "Conflicting"
+	case PullRequestStatusDraft:
+		return "Draft"
 	}
 	return "Unknown"
 }
 
+// IsOpen returns true if the status is the status of a pull request that is neither merged nor closed
+func (s PullRequestStatus) IsOpen() bool {
+	return s != PullRequestStatusMerged && s != PullRequestStatusClosed
+}
+
+// ParsePullRequestStatus parses a pull request status, the parsing ignores casing and dashes,
+// so both "NeedsReview" and "needs-review" are valid
+func ParsePullRequestStatus(str string) (PullRequestStatus, error) {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(str))
+	for _, status := range AllPullRequestStatuses {
+		if strings.ToLower(status.String()) == normalized {
+			return status, nil
+		}
+	}
+	return PullRequestStatusUnknown, fmt.Errorf(`not a valid pull request status: "%s"`, str)
+}
+
+// CombinePullRequestStatus combines the status of the checks of an open pull request with the details about it,
+// into a status that tells if the pull request is ready to be merged, and if not, why.
+// Merged, closed and unknown statuses are returned as is
+func CombinePullRequestStatus(checksStatus PullRequestStatus, details PullRequestDetails) PullRequestStatus {
+	switch checksStatus {
+	case PullRequestStatusSuccess, PullRequestStatusPending, PullRequestStatusError:
+	default:
+		return checksStatus
+	}
+
+	switch {
+	case details.Draft:
+		return PullRequestStatusDraft
+	case details.Mergeability == MergeabilityConflicting:
+		return PullRequestStatusConflicting
+	case checksStatus == PullRequestStatusError:
+		return PullRequestStatusError
+	case details.ReviewDecision == ReviewDecisionChangesRequested:
+		return PullRequestStatusChangesRequested
+	case checksStatus == PullRequestStatusPending:
+		return PullRequestStatusPending
+	case details.ReviewDecision == ReviewDecisionReviewRequired:
+		return PullRequestStatusNeedsReview
+	}
+	return PullRequestStatusSuccess
+}
+
 // PullRequest represents a pull request
 type PullRequest interface {
 	Status() PullRequestStatus
 	String() string
 }
 
+// CheckStatus is the result of a single check, like a CI job, of a pull request
+type CheckStatus string
+
+// All CheckStatuses
+const (
+	CheckStatusSuccess CheckStatus = "success"
+	CheckStatusPending CheckStatus = "pending"
+	CheckStatusFailure CheckStatus = "failure"
+)
+
+// Check is a single check that has been run on the last commit of a pull request
+type Check struct {
+	Name   string
+	Status CheckStatus
+	URL    string
+}
+
+// ReviewDecision is the combined result of the reviews of a pull request
+type ReviewDecision string
+
+// All ReviewDecisions
+const (
+	ReviewDecisionApproved         ReviewDecision = "approved"
+	ReviewDecisionChangesRequested ReviewDecision = "changes_requested"
+	ReviewDecisionReviewRequired   ReviewDecision = "review_required"
+)
+
+// Mergeability describes if a pull request can be merged without conflicts
+type Mergeability string
+
+// All Mergeabilities
+const (
+	MergeabilityMergeable   Mergeability = "mergeable"
+	MergeabilityConflicting Mergeability = "conflicting"
+)
+
+// PullRequestDetails is additional information about a pull request. Not all platforms provide all fields,
+// a field that is unknown is left with its zero value
+type PullRequestDetails struct {
+	Number    int
+	Title     string
+	Body      string
+	Author    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Labels    []string
+	Draft     bool
+	// Checks are the individual checks of the last commit
+	Checks         []Check
+	ReviewDecision ReviewDecision
+	// Approvals is the number of reviewers that currently approve the pull request
+	Approvals    int
+	Mergeability Mergeability
+	// BaseBranch is the branch the pull request is merged into
+	BaseBranch string
+	// HeadRef is a reference in the repository the pull request targets that points to the head of the pull request,
+	// it exists even when the pull request is made from a fork
+	HeadRef string
+}
+
+// PullRequestWithDetails is a pull request that can provide additional details about itself
+type PullRequestWithDetails interface {
+	PullRequest
+	Details() PullRequestDetails
+}
+
+// GetPullRequestDetails returns the details of a pull request, or the zero value if the pull request does not provide it
+func GetPullRequestDetails(pr PullRequest) PullRequestDetails {
+	if p, ok := pr.(PullRequestWithDetails); ok {
+		return p.Details()
+	}
+	return PullRequestDetails{}
+}
+
+// PullRequestWithRepository is a pull request that knows the full name of the repository it targets
+type PullRequestWithRepository interface {
+	PullRequest
+	RepositoryName() string
+}
+
 // MergeType is the way a pull request is "merged" into the base branch
 type MergeType int
 
@@ -94,3 +250,27 @@ func MergeTypeIntersection(mergeTypes1, mergeTypes2 []MergeType) []MergeType {
 	}
 	return res
 }
+
+// ErrUpdateMethodNotSupported is returned when the branch of a pull request can't be updated in the requested way through the API of the platform
+var ErrUpdateMethodNotSupported = errors.New("the platform can't update the branch of the pull request in the requested way")
+
+// ErrOwnPullRequest is returned when a pull request is not approved since it was created by the authenticated user
+var ErrOwnPullRequest = errors.New("the pull request was created by the authenticated user")
+
+// ErrNoFailedChecks is returned when a pull request has no failed checks that can be re-run
+var ErrNoFailedChecks = errors.New("there are no failed checks that can be re-run")
+
+// ErrMaxAttemptsReached is returned when all failed checks of a pull request already have been run the maximum number of times
+var ErrMaxAttemptsReached = errors.New("the failed checks have already been run the maximum number of times")
+
+// RetryResult returns the error a retry of checks should result in, based on how many failed checks were re-run
+// and how many that were not re-run since they had been run the maximum number of times
+func RetryResult(retried, maxedOut int) error {
+	switch {
+	case retried > 0:
+		return nil
+	case maxedOut > 0:
+		return ErrMaxAttemptsReached
+	}
+	return ErrNoFailedChecks
+}
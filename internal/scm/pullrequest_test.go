@@ -0,0 +1,100 @@
+package scm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCombinePullRequestStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		checksStatus PullRequestStatus
+		details      PullRequestDetails
+		want         PullRequestStatus
+	}{
+		{
+			name:         "no details",
+			checksStatus: PullRequestStatusSuccess,
+			want:         PullRequestStatusSuccess,
+		},
+		{
+			name:         "approved",
+			checksStatus: PullRequestStatusSuccess,
+			details:      PullRequestDetails{ReviewDecision: ReviewDecisionApproved, Mergeability: MergeabilityMergeable},
+			want:         PullRequestStatusSuccess,
+		},
+		{
+			name:         "needs review",
+			checksStatus: PullRequestStatusSuccess,
+			details:      PullRequestDetails{ReviewDecision: ReviewDecisionReviewRequired},
+			want:         PullRequestStatusNeedsReview,
+		},
+		{
+			name:         "pending checks before review",
+			checksStatus: PullRequestStatusPending,
+			details:      PullRequestDetails{ReviewDecision: ReviewDecisionReviewRequired},
+			want:         PullRequestStatusPending,
+		},
+		{
+			name:         "changes requested",
+			checksStatus: PullRequestStatusPending,
+			details:      PullRequestDetails{ReviewDecision: ReviewDecisionChangesRequested},
+			want:         PullRequestStatusChangesRequested,
+		},
+		{
+			name:         "failed checks before review",
+			checksStatus: PullRequestStatusError,
+			details:      PullRequestDetails{ReviewDecision: ReviewDecisionChangesRequested},
+			want:         PullRequestStatusError,
+		},
+		{
+			name:         "conflicting",
+			checksStatus: PullRequestStatusError,
+			details:      PullRequestDetails{Mergeability: MergeabilityConflicting},
+			want:         PullRequestStatusConflicting,
+		},
+		{
+			name:         "draft",
+			checksStatus: PullRequestStatusSuccess,
+			details:      PullRequestDetails{Draft: true, Mergeability: MergeabilityConflicting},
+			want:         PullRequestStatusDraft,
+		},
+		{
+			name:         "merged",
+			checksStatus: PullRequestStatusMerged,
+			details:      PullRequestDetails{Draft: true, ReviewDecision: ReviewDecisionReviewRequired},
+			want:         PullRequestStatusMerged,
+		},
+		{
+			name:         "closed",
+			checksStatus: PullRequestStatusClosed,
+			details:      PullRequestDetails{Mergeability: MergeabilityConflicting},
+			want:         PullRequestStatusClosed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CombinePullRequestStatus(tt.checksStatus, tt.details))
+		})
+	}
+}
+
+func TestParsePullRequestStatus(t *testing.T) {
+	for _, status := range AllPullRequestStatuses {
+		parsed, err := ParsePullRequestStatus(status.String())
+		assert.NoError(t, err)
+		assert.Equal(t, status, parsed)
+	}
+
+	status, err := ParsePullRequestStatus("changes-requested")
+	assert.NoError(t, err)
+	assert.Equal(t, PullRequestStatusChangesRequested, status)
+
+	status, err = ParsePullRequestStatus("NEEDS_REVIEW")
+	assert.NoError(t, err)
+	assert.Equal(t, PullRequestStatusNeedsReview, status)
+
+	_, err = ParsePullRequestStatus("approved")
+	assert.EqualError(t, err, `not a valid pull request status: "approved"`)
+}
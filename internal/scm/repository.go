@@ -1,5 +1,11 @@
 package scm
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
 // Repository provides all the information needed about a git repository
 type Repository interface {
 	// CloneURL returns the clone address of the repository
@@ -10,6 +16,54 @@ type Repository interface {
 	FullName() string
 }
 
+// Visibility is the visibility of a repository
+type Visibility string
+
+// All visibilities a repository can have
+const (
+	VisibilityPublic   Visibility = "public"
+	VisibilityPrivate  Visibility = "private"
+	VisibilityInternal Visibility = "internal"
+)
+
+// ParseVisibility parses a visibility from a string
+func ParseVisibility(str string) (Visibility, error) {
+	switch v := Visibility(strings.ToLower(str)); v {
+	case VisibilityPublic, VisibilityPrivate, VisibilityInternal:
+		return v, nil
+	}
+	return "", fmt.Errorf(`not a valid visibility: "%s"`, str)
+}
+
+// RepositoryMetadata is additional information about a repository. Not all platforms provide all fields,
+// a field that is unknown is left with its zero value
+type RepositoryMetadata struct {
+	Archived   bool
+	Visibility Visibility
+	// Language is the primary language of the repository
+	Language string
+	Topics   []string
+	// PushedAt is the last time something was pushed to the repository
+	PushedAt time.Time
+	// Size is the size of the repository in bytes
+	Size int64
+	Fork bool
+}
+
+// RepositoryWithMetadata is a repository that can provide additional metadata about itself
+type RepositoryWithMetadata interface {
+	Repository
+	Metadata() RepositoryMetadata
+}
+
+// GetRepositoryMetadata returns the metadata of a repository, or the zero value if the repository does not provide it
+func GetRepositoryMetadata(repo Repository) RepositoryMetadata {
+	if r, ok := repo.(RepositoryWithMetadata); ok {
+		return r.Metadata()
+	}
+	return RepositoryMetadata{}
+}
+
 func RepoContainsTopic(repoTopics []string, filterTopics []string) bool {
 	repoTopicsMap := map[string]struct{}{}
 	for _, v := range repoTopics {
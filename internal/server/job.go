@@ -0,0 +1,223 @@
+package server
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Commands are the multi-gitter commands that can be run as jobs
+var Commands = []string{"run", "status", "merge", "close", "comment", "update-branch", "edit", "approve", "retry-checks", "diff"}
+
+// commonFlags are the platform, repository listing and filtering flags that a job of any command can set
+var commonFlags = []string{
+	"username", "auth-type", "org", "group", "user", "repo", "repo-search", "code-search", "topic", "project",
+	"include-subgroups", "ssh-auth", "skip-forks", "fork", "fork-owner",
+	"skip-repo", "repo-include", "repo-exclude", "language", "visibility", "exclude-archived", "pushed-after", "filter", "has-file",
+	"branch", "campaign", "status", "label", "older-than", "newer-than",
+}
+
+// commandFlags are the flags that a job can set for each command, in addition to commonFlags.
+// Flags that read or write files on the server, or that could leak the credentials of the server, are left out.
+// "platform", "base-url" and "profile" are only allowed if they match the configuration of the server
+var commandFlags = map[string][]string{
+	"run": {
+		"base-branch", "pr-title", "pr-body", "commit-message", "reviewers", "team-reviewers", "assignees",
+		"max-reviewers", "max-team-reviewers", "codeowners", "concurrent", "skip-pr", "push-only", "manual-commit",
+		"api-push", "push-option", "dry-run", "conflict-strategy", "draft", "pr-auto-merge", "merge-type", "labels",
+		"author-name", "author-email", "git-type", "fetch-depth",
+	},
+	"merge":         {"dry-run", "merge-type", "concurrent", "wait-for-checks", "check-interval", "merge-interval", "required-approvals"},
+	"status":        {"format", "watch", "watch-interval", "watch-timeout"},
+	"close":         {"dry-run", "comment", "delete-branch"},
+	"comment":       {"dry-run", "body", "allow-duplicates"},
+	"update-branch": {"dry-run", "rebase", "base-branch", "author-name", "author-email"},
+	"edit":          {"dry-run", "pr-title", "pr-body", "reviewers", "team-reviewers", "assignees", "labels", "draft", "pr-auto-merge"},
+	"approve":       {"dry-run"},
+	"retry-checks":  {"dry-run", "max-attempts"},
+	"diff":          {"group-identical", "deviating"},
+}
+
+// forbiddenPlatforms are platforms that would give access to the filesystem of the server
+var forbiddenPlatforms = []string{"local", "git"}
+
+// JobPolicy is the configuration of the server that limits what jobs can do
+type JobPolicy struct {
+	// Platform and BaseURL are the platform the server is configured with. A job can't use any other platform
+	// or base URL, since the token of the server would be sent to it
+	Platform string
+	BaseURL  string
+	// Profiles are the profiles, defined in the config file of the server, that jobs are allowed to use
+	Profiles []string
+	// ScriptDir is the directory the scripts of run jobs have to be in. Run jobs are not allowed if it's not set
+	ScriptDir string
+}
+
+// Validate makes sure the policy does not give jobs access to the filesystem of the server
+func (p JobPolicy) Validate() error {
+	if slices.Contains(forbiddenPlatforms, p.Platform) {
+		return errors.Errorf(`the platform "%s" can't be used by the server, since it would give jobs access to its filesystem`, p.Platform)
+	}
+	return nil
+}
+
+// JobStatus is the current state of a job
+type JobStatus string
+
+// All JobStatuses
+const (
+	JobStatusQueued    JobStatus = "queued"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusFailed    JobStatus = "failed"
+	JobStatusCanceled  JobStatus = "canceled"
+)
+
+// Done returns true if the job will not change status anymore
+func (s JobStatus) Done() bool {
+	return s == JobStatusSucceeded || s == JobStatusFailed || s == JobStatusCanceled
+}
+
+// JobRequest is the data needed to submit a new job
+type JobRequest struct {
+	// Command is the multi-gitter command that should be run, for example "run" or "merge"
+	Command string `json:"command"`
+	// Args are the positional arguments of the command, for example the script path of "run"
+	Args []string `json:"args,omitempty"`
+	// Flags are the flags of the command, in the same format as the config file
+	Flags map[string]interface{} `json:"flags,omitempty"`
+}
+
+// Validate makes sure the request can be run as a job within the policy of the server.
+// The script of a run job is replaced with its absolute path
+func (r *JobRequest) Validate(policy JobPolicy) error {
+	if !slices.Contains(Commands, r.Command) {
+		return errors.Errorf(`unknown command "%s"`, r.Command)
+	}
+
+	for name, value := range r.Flags {
+		switch name {
+		case "platform":
+			if platform, _ := value.(string); platform != policy.Platform {
+				return errors.New(`the flag "platform" can only be set to the platform of the server`)
+			}
+			continue
+		case "base-url":
+			if baseURL, _ := value.(string); baseURL != policy.BaseURL {
+				return errors.New(`the flag "base-url" can only be set to the base URL of the server`)
+			}
+			continue
+		case "profile":
+			if err := validateProfiles(value, policy.Profiles); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !slices.Contains(commonFlags, name) && !slices.Contains(commandFlags[r.Command], name) {
+			return errors.Errorf(`the flag "%s" can't be set when running %s through the server`, name, r.Command)
+		}
+	}
+
+	// Arguments are parsed together with the flags, and could otherwise be used to set any flag
+	if r.Command != "run" {
+		if len(r.Args) > 0 {
+			return errors.Errorf("the %s command does not take any arguments", r.Command)
+		}
+		return nil
+	}
+
+	if len(r.Args) != 1 {
+		return errors.New("a run job needs exactly one argument, the path of the script")
+	}
+	script, err := resolveScript(policy.ScriptDir, r.Args[0])
+	if err != nil {
+		return err
+	}
+	r.Args = []string{script}
+
+	return nil
+}
+
+// validateProfiles makes sure that only the profiles allowed by the server are used
+func validateProfiles(value interface{}, allowed []string) error {
+	var profiles []string
+	switch v := value.(type) {
+	case string:
+		profiles = strings.Split(v, ",")
+	case []interface{}:
+		for _, profile := range v {
+			str, _ := profile.(string)
+			profiles = append(profiles, str)
+		}
+	default:
+		return errors.New(`the flag "profile" has to be a string or a list of strings`)
+	}
+
+	for _, profile := range profiles {
+		if !slices.Contains(allowed, strings.TrimSpace(profile)) {
+			return errors.Errorf(`the profile "%s" can't be used when running through the server`, profile)
+		}
+	}
+	return nil
+}
+
+// resolveScript returns the absolute path of the script of a run job, which has to be inside the script directory
+func resolveScript(scriptDir, script string) (string, error) {
+	if scriptDir == "" {
+		return "", errors.New("run jobs are not allowed, since the server has no script directory")
+	}
+
+	// The script is parsed as a command, arguments would allow any program to be run
+	if strings.ContainsAny(script, " \t\"'") {
+		return "", errors.New("the script of a run job can't contain spaces, quotes or arguments")
+	}
+
+	dir, err := filepath.Abs(scriptDir)
+	if err != nil {
+		return "", errors.Wrap(err, "could not resolve the script directory")
+	}
+	dir, err = filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", errors.Wrap(err, "could not resolve the script directory")
+	}
+
+	path := script
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	path, err = filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", errors.Errorf(`the script "%s" does not exist in the script directory`, script)
+	}
+
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.Errorf(`the script "%s" is not in the script directory`, script)
+	}
+	return path, nil
+}
+
+// Job is a submitted request together with the state of its execution
+type Job struct {
+	ID string `json:"id"`
+	JobRequest
+
+	Status JobStatus `json:"status"`
+	Error  string    `json:"error,omitempty"`
+
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// Event is a log entry produced while running a job
+type Event struct {
+	Time    time.Time              `json:"time"`
+	Level   string                 `json:"level"`
+	Message string                 `json:"message"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+}
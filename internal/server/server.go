@@ -0,0 +1,539 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Executor runs a job. Any output of the job should be written to the file at outputPath,
+// and its logs should be written to logs as newline delimited json, in the format of logrus
+type Executor func(ctx context.Context, req JobRequest, outputPath string, logs io.Writer) error
+
+// Server exposes an HTTP API where jobs can be submitted, and runs them
+//
+// Jobs are started in the order they were submitted, and up to MaxJobs of them run at the same time.
+// Each job writes its own logs, which are stored as its events and logged by the server with the id of the job
+type Server struct {
+	store    *Store
+	executor Executor
+	apiToken string
+	policy   JobPolicy
+	maxJobs  int
+
+	queue chan string
+
+	lock        sync.Mutex
+	running     map[string]context.CancelFunc // Cancels each running job
+	subscribers map[string][]*subscriber
+}
+
+// Config is the configuration of a server
+type Config struct {
+	Store    *Store
+	Executor Executor
+	// APIToken, if set, has to be sent as a bearer token with each request
+	APIToken string
+	// QueueSize is the maximum number of jobs that can wait to be run
+	QueueSize int
+	// MaxJobs is the maximum number of jobs that run at the same time, at least one job is always run
+	MaxJobs int
+	// JobPolicy limits what jobs can do
+	JobPolicy JobPolicy
+}
+
+// New creates a new server
+func New(config Config) *Server {
+	return &Server{
+		store:       config.Store,
+		executor:    config.Executor,
+		apiToken:    config.APIToken,
+		policy:      config.JobPolicy,
+		maxJobs:     max(config.MaxJobs, 1),
+		queue:       make(chan string, config.QueueSize),
+		running:     map[string]context.CancelFunc{},
+		subscribers: map[string][]*subscriber{},
+	}
+}
+
+// Start runs queued jobs until the context is canceled
+func (s *Server) Start(ctx context.Context) {
+	for i := 0; i < s.maxJobs; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case id := <-s.queue:
+					s.runJob(ctx, id)
+				}
+			}
+		}()
+	}
+}
+
+// Submit validates and queues a new job
+func (s *Server) Submit(req JobRequest) (Job, error) {
+	if err := req.Validate(s.policy); err != nil {
+		return Job{}, err
+	}
+
+	job := &Job{
+		ID:         newJobID(),
+		JobRequest: req,
+		Status:     JobStatusQueued,
+		CreatedAt:  time.Now(),
+	}
+	if err := s.store.Add(job); err != nil {
+		return Job{}, errors.Wrap(err, "could not store job")
+	}
+	submitted := *job
+
+	select {
+	case s.queue <- job.ID:
+	default:
+		_, _ = s.finish(job.ID, JobStatusFailed, errQueueFull)
+		return Job{}, errQueueFull
+	}
+
+	return submitted, nil
+}
+
+// Cancel cancels a job that is queued or running
+func (s *Server) Cancel(id string) (Job, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if cancel, ok := s.running[id]; ok {
+		cancel()
+		return s.store.Get(id)
+	}
+
+	return s.store.Update(id, func(job *Job) {
+		if job.Status == JobStatusQueued {
+			now := time.Now()
+			job.Status = JobStatusCanceled
+			job.FinishedAt = &now
+		}
+	})
+}
+
+var errQueueFull = errors.New("the job queue is full")
+
+func (s *Server) runJob(ctx context.Context, id string) {
+	job, err := s.store.Get(id)
+	if err != nil || job.Status != JobStatusQueued {
+		return
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s.lock.Lock()
+	s.running[id] = cancel
+	job, err = s.store.Update(id, func(job *Job) {
+		now := time.Now()
+		job.Status = JobStatusRunning
+		job.StartedAt = &now
+	})
+	s.lock.Unlock()
+	if err != nil {
+		return
+	}
+
+	err = s.execute(jobCtx, job)
+
+	s.lock.Lock()
+	delete(s.running, id)
+	s.lock.Unlock()
+
+	switch {
+	case jobCtx.Err() != nil && ctx.Err() == nil:
+		_, _ = s.finish(id, JobStatusCanceled, err)
+	case err != nil:
+		_, _ = s.finish(id, JobStatusFailed, err)
+	default:
+		_, _ = s.finish(id, JobStatusSucceeded, nil)
+	}
+}
+
+func (s *Server) execute(ctx context.Context, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	logs := &jobLogWriter{server: s, id: job.ID}
+	defer logs.flush()
+
+	return s.executor(ctx, job.JobRequest, s.store.OutputPath(job.ID), logs)
+}
+
+// finish sets the final status of a job and ends all ongoing event streams of it
+func (s *Server) finish(id string, status JobStatus, jobErr error) (Job, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, sub := range s.subscribers[id] {
+		sub.close()
+	}
+	delete(s.subscribers, id)
+
+	return s.store.Update(id, func(job *Job) {
+		now := time.Now()
+		job.Status = status
+		job.FinishedAt = &now
+		if jobErr != nil {
+			job.Error = jobErr.Error()
+		}
+	})
+}
+
+// addEvent stores an event of a job, sends it to everyone following the job, and logs it with the id of the job
+func (s *Server) addEvent(id string, event Event) {
+	level, err := log.ParseLevel(event.Level)
+	if err != nil {
+		level = log.InfoLevel
+	} else if level < log.ErrorLevel {
+		level = log.ErrorLevel // The server should not panic or exit because of a job
+	}
+	log.WithFields(event.Fields).WithField("job", id).WithTime(event.Time).Log(level, event.Message)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.store.AddEvent(id, event); err != nil {
+		log.WithError(err).WithField("job", id).Error("Could not store the event of a job")
+	}
+
+	for _, sub := range s.subscribers[id] {
+		sub.add(event)
+	}
+}
+
+// subscriber buffers the events of a job for someone following it.
+// The job is never blocked by a slow subscriber, and the subscriber never misses any events
+type subscriber struct {
+	lock   sync.Mutex
+	events []Event
+	done   bool
+
+	// notify receives a value when there are new events or the job is done
+	notify chan struct{}
+}
+
+func newSubscriber() *subscriber {
+	return &subscriber{
+		notify: make(chan struct{}, 1),
+	}
+}
+
+func (s *subscriber) add(event Event) {
+	s.lock.Lock()
+	s.events = append(s.events, event)
+	s.lock.Unlock()
+	s.wake()
+}
+
+func (s *subscriber) close() {
+	s.lock.Lock()
+	s.done = true
+	s.lock.Unlock()
+	s.wake()
+}
+
+func (s *subscriber) wake() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// next returns the events buffered since the last call, and if the job is done
+func (s *subscriber) next() ([]Event, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	events := s.events
+	s.events = nil
+	return events, s.done
+}
+
+// jobLogWriter parses the logs written by a job, line by line, into events of the job
+type jobLogWriter struct {
+	server *Server
+	id     string
+
+	lock sync.Mutex
+	buf  []byte
+}
+
+func (w *jobLogWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.addLine(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush adds the last line of the logs, if it did not end with a newline
+func (w *jobLogWriter) flush() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.addLine(w.buf)
+	w.buf = nil
+}
+
+func (w *jobLogWriter) addLine(line []byte) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return
+	}
+	w.server.addEvent(w.id, parseEvent(line))
+}
+
+// parseEvent parses a log entry formatted as json by logrus. Lines that are not json, like errors
+// printed by a command, are kept as the message of an event
+func parseEvent(line []byte) Event {
+	event := Event{
+		Time:  time.Now(),
+		Level: log.InfoLevel.String(),
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(line, &fields); err != nil || fields == nil {
+		event.Message = string(line)
+		return event
+	}
+
+	if t, ok := fields[log.FieldKeyTime].(string); ok {
+		if parsed, err := time.Parse(time.RFC3339, t); err == nil {
+			event.Time = parsed
+		}
+	}
+	if level, ok := fields[log.FieldKeyLevel].(string); ok {
+		event.Level = level
+	}
+	event.Message, _ = fields[log.FieldKeyMsg].(string)
+
+	delete(fields, log.FieldKeyTime)
+	delete(fields, log.FieldKeyLevel)
+	delete(fields, log.FieldKeyMsg)
+	if len(fields) > 0 {
+		event.Fields = fields
+	}
+
+	return event
+}
+
+// subscribe returns all events of a job so far, and if the job is not done, a subscriber that will receive
+// all upcoming events until the job is done
+func (s *Server) subscribe(id string) ([]Event, *subscriber, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	job, err := s.store.Get(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	events, err := s.store.Events(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if job.Status.Done() {
+		return events, nil, nil
+	}
+
+	sub := newSubscriber()
+	s.subscribers[id] = append(s.subscribers[id], sub)
+	return events, sub, nil
+}
+
+func (s *Server) unsubscribe(id string, sub *subscriber) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	subs := s.subscribers[id]
+	for i := range subs {
+		if subs[i] == sub {
+			s.subscribers[id] = append(subs[:i], subs[i+1:]...)
+			return
+		}
+	}
+}
+
+// Handler returns the HTTP handler of the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.Handle("POST /jobs", s.authenticated(s.handleSubmit))
+	mux.Handle("GET /jobs", s.authenticated(s.handleList))
+	mux.Handle("GET /jobs/{id}", s.authenticated(s.handleGet))
+	mux.Handle("DELETE /jobs/{id}", s.authenticated(s.handleCancel))
+	mux.Handle("GET /jobs/{id}/events", s.authenticated(s.handleEvents))
+	mux.Handle("GET /jobs/{id}/output", s.authenticated(s.handleOutput))
+	return mux
+}
+
+func (s *Server) authenticated(handler http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.apiToken != "" {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(token), []byte(s.apiToken)) != 1 {
+				writeError(w, http.StatusUnauthorized, errors.New("missing or invalid api token"))
+				return
+			}
+		}
+		handler(w, r)
+	})
+}
+
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	var req JobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, errors.Wrap(err, "could not parse job"))
+		return
+	}
+
+	job, err := s.Submit(req)
+	switch {
+	case err == errQueueFull:
+		writeError(w, http.StatusServiceUnavailable, err)
+	case err != nil:
+		writeError(w, http.StatusBadRequest, err)
+	default:
+		writeJSON(w, http.StatusAccepted, job)
+	}
+}
+
+func (s *Server) handleList(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.store.List())
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	job, err := s.store.Get(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+func (s *Server) handleCancel(w http.ResponseWriter, r *http.Request) {
+	job, err := s.Cancel(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+// handleEvents writes the events of a job as newline delimited json.
+// If the follow query parameter is set, new events are streamed until the job is done
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	follow := r.URL.Query().Get("follow") == "true"
+
+	events, sub, err := s.subscribe(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if sub != nil {
+		defer s.unsubscribe(id, sub)
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(w)
+	for _, event := range events {
+		_ = encoder.Encode(event)
+	}
+
+	if !follow || sub == nil {
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	for {
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-sub.notify:
+		}
+
+		events, done := sub.next()
+		for _, event := range events {
+			_ = encoder.Encode(event)
+		}
+		if done {
+			return
+		}
+	}
+}
+
+func (s *Server) handleOutput(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if _, err := s.store.Get(id); err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	data, err := os.ReadFile(s.store.OutputPath(id))
+	if err != nil && !os.IsNotExist(err) {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func newJobID() string {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%s-%s", time.Now().UTC().Format("20060102T150405"), hex.EncodeToString(b))
+}
@@ -0,0 +1,308 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, executor Executor) (*Server, *httptest.Server) {
+	store, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+
+	scriptDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(scriptDir, "script.sh"), []byte("#!/bin/sh\n"), 0700))
+
+	srv := New(Config{
+		Store:     store,
+		Executor:  executor,
+		APIToken:  "secret",
+		QueueSize: 10,
+		MaxJobs:   2,
+		JobPolicy: JobPolicy{
+			Platform:  "gitlab",
+			BaseURL:   "https://git.example.com",
+			Profiles:  []string{"work"},
+			ScriptDir: scriptDir,
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	srv.Start(ctx)
+
+	httpServer := httptest.NewServer(srv.Handler())
+	t.Cleanup(httpServer.Close)
+
+	return srv, httpServer
+}
+
+func request(t *testing.T, method, url, body string) *http.Response {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func waitForJob(t *testing.T, srv *Server, id string) Job {
+	var job Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = srv.store.Get(id)
+		require.NoError(t, err)
+		return job.Status.Done()
+	}, 5*time.Second, 10*time.Millisecond)
+	return job
+}
+
+func TestServer(t *testing.T) {
+	srv, httpServer := newTestServer(t, func(_ context.Context, req JobRequest, outputPath string, logs io.Writer) error {
+		logger := log.New()
+		logger.SetOutput(logs)
+		logger.SetFormatter(&log.JSONFormatter{})
+		logger.WithField("repo", "owner/repo").Info("Doing things")
+		fmt.Fprintln(logs, "not json")
+		if req.Flags["branch"] == "fail" {
+			return errors.New("it failed")
+		}
+		return os.WriteFile(outputPath, []byte("owner/repo #1: Success\n"), 0600)
+	})
+
+	resp := request(t, "POST", httpServer.URL+"/jobs", `{"command": "status", "flags": {"branch": "my-branch"}}`)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	var submitted Job
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&submitted))
+	assert.Equal(t, "status", submitted.Command)
+	assert.Equal(t, "my-branch", submitted.Flags["branch"])
+
+	job := waitForJob(t, srv, submitted.ID)
+	assert.Equal(t, JobStatusSucceeded, job.Status)
+	assert.NotNil(t, job.StartedAt)
+	assert.NotNil(t, job.FinishedAt)
+
+	resp = request(t, "GET", httpServer.URL+"/jobs/"+submitted.ID+"/output", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	output := new(strings.Builder)
+	_, _ = bufio.NewReader(resp.Body).WriteTo(output)
+	assert.Equal(t, "owner/repo #1: Success\n", output.String())
+
+	resp = request(t, "GET", httpServer.URL+"/jobs/"+submitted.ID+"/events", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	decoder := json.NewDecoder(resp.Body)
+	var event Event
+	require.NoError(t, decoder.Decode(&event))
+	assert.Equal(t, "Doing things", event.Message)
+	assert.Equal(t, "info", event.Level)
+	assert.Equal(t, "owner/repo", event.Fields["repo"])
+	var printed Event
+	require.NoError(t, decoder.Decode(&printed))
+	assert.Equal(t, "not json", printed.Message)
+	assert.Nil(t, printed.Fields)
+
+	// A failing job
+	resp = request(t, "POST", httpServer.URL+"/jobs", `{"command": "merge", "flags": {"branch": "fail"}}`)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&submitted))
+	job = waitForJob(t, srv, submitted.ID)
+	assert.Equal(t, JobStatusFailed, job.Status)
+	assert.Equal(t, "it failed", job.Error)
+
+	resp = request(t, "GET", httpServer.URL+"/jobs", "")
+	var jobs []Job
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&jobs))
+	assert.Len(t, jobs, 2)
+}
+
+func TestServerValidation(t *testing.T) {
+	_, httpServer := newTestServer(t, func(context.Context, JobRequest, string, io.Writer) error {
+		return nil
+	})
+
+	resp := request(t, "POST", httpServer.URL+"/jobs", `{"command": "print"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = request(t, "POST", httpServer.URL+"/jobs", `{"command": "run", "flags": {"token": "abc"}}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// Flags that read or write files on the server
+	for _, body := range []string{
+		`{"command": "comment", "flags": {"body-file": "/proc/self/environ"}}`,
+		`{"command": "comment", "args": ["--body-file=/proc/self/environ"]}`,
+		`{"command": "run", "args": ["script.sh"], "flags": {"reviewer-rotation-file": "/etc/passwd"}}`,
+		`{"command": "run", "args": ["script.sh"], "flags": {"unavailable-reviewers-file": "/etc/passwd"}}`,
+		`{"command": "run", "args": ["script.sh"], "flags": {"clone-dir": "/etc"}}`,
+		`{"command": "status", "args": ["--log-file=/etc/passwd"]}`,
+	} {
+		resp = request(t, "POST", httpServer.URL+"/jobs", body)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, body)
+	}
+
+	// The token could be sent to another host
+	resp = request(t, "POST", httpServer.URL+"/jobs", `{"command": "run", "args": ["script.sh"], "flags": {"platform": "local"}}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp = request(t, "POST", httpServer.URL+"/jobs", `{"command": "status", "flags": {"platform": "github"}}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp = request(t, "POST", httpServer.URL+"/jobs", `{"command": "status", "flags": {"platform": "gitlab"}}`)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	resp = request(t, "POST", httpServer.URL+"/jobs", `{"command": "status", "flags": {"base-url": "https://evil.example.com"}}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp = request(t, "POST", httpServer.URL+"/jobs", `{"command": "status", "flags": {"base-url": "https://git.example.com"}}`)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	resp = request(t, "POST", httpServer.URL+"/jobs", `{"command": "status", "flags": {"profile": ["work", "private"]}}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp = request(t, "POST", httpServer.URL+"/jobs", `{"command": "status", "flags": {"profile": ["work"]}}`)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	// Only scripts in the script directory can be run
+	for _, script := range []string{"/bin/sh", "../script.sh", "does-not-exist.sh", "script.sh --flag"} {
+		resp = request(t, "POST", httpServer.URL+"/jobs", `{"command": "run", "args": ["`+script+`"]}`)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, script)
+	}
+
+	resp = request(t, "GET", httpServer.URL+"/jobs/does-not-exist", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, err := http.Get(httpServer.URL + "/jobs")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestServerCancel(t *testing.T) {
+	started := make(chan struct{})
+	srv, httpServer := newTestServer(t, func(ctx context.Context, _ JobRequest, _ string, _ io.Writer) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	resp := request(t, "POST", httpServer.URL+"/jobs", `{"command": "run", "args": ["script.sh"]}`)
+	var submitted Job
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&submitted))
+
+	<-started
+	resp = request(t, "DELETE", httpServer.URL+"/jobs/"+submitted.ID, "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	job := waitForJob(t, srv, submitted.ID)
+	assert.Equal(t, JobStatusCanceled, job.Status)
+}
+
+func TestServerFollowEvents(t *testing.T) {
+	start := make(chan struct{})
+	srv, httpServer := newTestServer(t, func(_ context.Context, _ JobRequest, _ string, logs io.Writer) error {
+		<-start
+		for i := 0; i < 1000; i++ {
+			fmt.Fprintf(logs, "line %d\n", i)
+		}
+		return nil
+	})
+
+	resp := request(t, "POST", httpServer.URL+"/jobs", `{"command": "status"}`)
+	var submitted Job
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&submitted))
+
+	resp = request(t, "GET", httpServer.URL+"/jobs/"+submitted.ID+"/events?follow=true", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	close(start)
+
+	// A follower that can't keep up should still get every event
+	time.Sleep(100 * time.Millisecond)
+	decoder := json.NewDecoder(resp.Body)
+	for i := 0; i < 1000; i++ {
+		var event Event
+		require.NoError(t, decoder.Decode(&event))
+		assert.Equal(t, fmt.Sprintf("line %d", i), event.Message)
+	}
+	var event Event
+	assert.ErrorIs(t, decoder.Decode(&event), io.EOF)
+
+	assert.Equal(t, JobStatusSucceeded, waitForJob(t, srv, submitted.ID).Status)
+}
+
+func TestServerRunsJobsInParallel(t *testing.T) {
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	srv, httpServer := newTestServer(t, func(_ context.Context, _ JobRequest, _ string, _ io.Writer) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	})
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		resp := request(t, "POST", httpServer.URL+"/jobs", `{"command": "status"}`)
+		var submitted Job
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&submitted))
+		ids = append(ids, submitted.ID)
+	}
+
+	// Only two jobs are allowed to run at the same time
+	<-started
+	<-started
+	select {
+	case <-started:
+		t.Fatal("more jobs than allowed were started")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	for _, id := range ids {
+		assert.Equal(t, JobStatusSucceeded, waitForJob(t, srv, id).Status)
+	}
+}
+
+func TestStoreMarksUnfinishedJobsAsFailed(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewStore(dir)
+	require.NoError(t, err)
+	require.NoError(t, store.Add(&Job{ID: "abc", Status: JobStatusRunning}))
+
+	store, err = NewStore(dir)
+	require.NoError(t, err)
+	job, err := store.Get("abc")
+	require.NoError(t, err)
+	assert.Equal(t, JobStatusFailed, job.Status)
+}
+
+func TestRunJobsWithoutScriptDir(t *testing.T) {
+	req := JobRequest{Command: "run", Args: []string{"script.sh"}}
+	assert.EqualError(t, req.Validate(JobPolicy{}), "run jobs are not allowed, since the server has no script directory")
+}
+
+func TestRunJobScriptIsResolved(t *testing.T) {
+	scriptDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(scriptDir, "script.sh"), []byte("#!/bin/sh\n"), 0700))
+
+	req := JobRequest{Command: "run", Args: []string{"script.sh"}}
+	require.NoError(t, req.Validate(JobPolicy{ScriptDir: scriptDir}))
+
+	expected, err := filepath.EvalSymlinks(filepath.Join(scriptDir, "script.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{expected}, req.Args)
+}
+
+func TestJobPolicyFilesystemPlatforms(t *testing.T) {
+	assert.NoError(t, JobPolicy{Platform: "github"}.Validate())
+	assert.Error(t, JobPolicy{Platform: "local"}.Validate())
+	assert.Error(t, JobPolicy{Platform: "git"}.Validate())
+}
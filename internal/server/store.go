@@ -0,0 +1,195 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	jobFileName    = "job.json"
+	eventsFileName = "events.jsonl"
+	outputFileName = "output.txt"
+)
+
+// errJobNotFound is returned when no job with a specific id exist
+var errJobNotFound = errors.New("job not found")
+
+// Store persists jobs, their events and their output in a directory.
+// Each job get its own sub-directory named after its id
+type Store struct {
+	dir string
+
+	lock sync.RWMutex
+	jobs map[string]*Job
+}
+
+// NewStore creates a store in the given directory, jobs already existing in the directory are loaded.
+// Jobs that were not done when the directory was last used will never finish, and are marked as failed
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "could not create data directory")
+	}
+
+	s := &Store{
+		dir:  dir,
+		jobs: map[string]*Job{},
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read data directory")
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name(), jobFileName))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		job := &Job{}
+		if err := json.Unmarshal(data, job); err != nil {
+			return nil, errors.Wrapf(err, "could not parse job %s", entry.Name())
+		}
+
+		if !job.Status.Done() {
+			now := time.Now()
+			job.Status = JobStatusFailed
+			job.Error = "the server was stopped before the job finished"
+			job.FinishedAt = &now
+			if err := s.writeJob(job); err != nil {
+				return nil, err
+			}
+		}
+
+		s.jobs[job.ID] = job
+	}
+
+	return s, nil
+}
+
+// Add stores a new job
+func (s *Store) Add(job *Job) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := os.MkdirAll(s.jobDir(job.ID), 0700); err != nil {
+		return err
+	}
+
+	s.jobs[job.ID] = job
+	return s.writeJob(job)
+}
+
+// Update changes a job and persists the change
+func (s *Store) Update(id string, fn func(job *Job)) (Job, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return Job{}, errJobNotFound
+	}
+
+	fn(job)
+	return *job, s.writeJob(job)
+}
+
+// Get returns a copy of a job
+func (s *Store) Get(id string) (Job, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return Job{}, errJobNotFound
+	}
+	return *job, nil
+}
+
+// List returns all jobs, the latest created first
+func (s *Store) List() []Job {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	jobs := make([]Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, *job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+	})
+	return jobs
+}
+
+// AddEvent appends an event to the events of a job
+func (s *Store) AddEvent(id string, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filepath.Join(s.jobDir(id), eventsFileName), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Events returns all events of a job
+func (s *Store) Events(id string) ([]Event, error) {
+	if _, err := s.Get(id); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filepath.Join(s.jobDir(id), eventsFileName))
+	if os.IsNotExist(err) {
+		return []Event{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	events := []Event{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+// OutputPath returns the path of the file where the output of a job is written
+func (s *Store) OutputPath(id string) string {
+	return filepath.Join(s.jobDir(id), outputFileName)
+}
+
+func (s *Store) jobDir(id string) string {
+	return filepath.Join(s.dir, id)
+}
+
+func (s *Store) writeJob(job *Job) error {
+	data, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.jobDir(job.ID), jobFileName), data, 0600)
+}
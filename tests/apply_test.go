@@ -0,0 +1,247 @@
+package tests
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeManifest(t *testing.T, dir, content string) string {
+	path := filepath.Join(dir, "manifest.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+// TestApply tests running, getting the status of and merging a campaign defined in a manifest
+func TestApply(t *testing.T) {
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir := t.TempDir()
+	campaignDir := filepath.Join(tmpDir, "campaigns")
+
+	workingDir, err := os.Getwd()
+	require.NoError(t, err)
+
+	changeRepo := createRepo(t, "owner", "should-change", "i like apples")
+	skippedRepo := createRepo(t, "owner", "should-be-skipped", "i like apples")
+	vcMock.AddRepository(changeRepo)
+	vcMock.AddRepository(skippedRepo)
+
+	manifestPath := writeManifest(t, tmpDir, fmt.Sprintf(`
+version: 1
+name: bananas
+targets:
+  platform: github
+  repo: [owner/should-change, owner/should-be-skipped]
+filters:
+  skip-repo: [owner/should-be-skipped]
+script: %s
+branch: campaign-branch
+pull-request:
+  title: Use bananas
+  body: Bananas are better
+  labels: [fruit]
+rollout:
+  author-name: Test Author
+  author-email: test@example.com
+`, normalizePath(filepath.Join(workingDir, changerBinaryPath))))
+
+	runOutFile := filepath.Join(tmpDir, "run-out.txt")
+	command := cmd.RootCmd()
+	command.SetArgs([]string{"apply", manifestPath, "--output", runOutFile, "--campaign-dir", campaignDir})
+	require.NoError(t, command.Execute())
+
+	require.Len(t, vcMock.PullRequests, 1)
+	pr := vcMock.PullRequests[0]
+	assert.Equal(t, "campaign-branch", pr.Head)
+	assert.Equal(t, "Use bananas", pr.Title)
+	assert.Equal(t, "Bananas are better", pr.Body)
+	assert.Equal(t, []string{"fruit"}, pr.Labels)
+	assert.Equal(t, "owner/should-change", pr.Repository.FullName())
+	assert.FileExists(t, filepath.Join(campaignDir, "bananas.json"))
+
+	vcMock.SetPRStatus("should-change", "campaign-branch", scm.PullRequestStatusSuccess)
+
+	statusOutFile := filepath.Join(tmpDir, "status-out.txt")
+	command = cmd.RootCmd()
+	command.SetArgs([]string{"apply", manifestPath, "status", "--output", statusOutFile, "--campaign-dir", campaignDir})
+	require.NoError(t, command.Execute())
+
+	statusOut, err := os.ReadFile(statusOutFile)
+	require.NoError(t, err)
+	assert.Equal(t, "owner/should-change #1: Success\n", string(statusOut))
+
+	command = cmd.RootCmd()
+	command.SetArgs([]string{"apply", manifestPath, "merge", "--log-file", filepath.Join(tmpDir, "merge-log.txt"), "--campaign-dir", campaignDir})
+	require.NoError(t, command.Execute())
+	assert.Equal(t, scm.PullRequestStatusMerged, vcMock.PullRequests[0].PRStatus)
+}
+
+// TestApplyMergeKeys tests that the concurrent and dry-run keys of the merge section are only used when merging
+func TestApplyMergeKeys(t *testing.T) {
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir := t.TempDir()
+	campaignDir := filepath.Join(tmpDir, "campaigns")
+
+	workingDir, err := os.Getwd()
+	require.NoError(t, err)
+
+	vcMock.AddRepository(createRepo(t, "owner", "should-change", "i like apples"))
+
+	manifestPath := writeManifest(t, tmpDir, fmt.Sprintf(`
+version: 1
+name: bananas
+targets:
+  platform: github
+  repo: [owner/should-change]
+script: %s
+branch: campaign-branch
+pull-request:
+  title: Use bananas
+rollout:
+  concurrent: 2
+  author-name: Test Author
+  author-email: test@example.com
+merge:
+  concurrent: 3
+  dry-run: true
+`, normalizePath(filepath.Join(workingDir, changerBinaryPath))))
+
+	command := cmd.RootCmd()
+	command.SetArgs([]string{"apply", manifestPath, "--output", filepath.Join(tmpDir, "run-out.txt"), "--campaign-dir", campaignDir})
+	require.NoError(t, command.Execute())
+	require.Len(t, vcMock.PullRequests, 1)
+
+	vcMock.SetPRStatus("should-change", "campaign-branch", scm.PullRequestStatusSuccess)
+
+	command = cmd.RootCmd()
+	command.SetArgs([]string{"apply", manifestPath, "merge", "--log-file", filepath.Join(tmpDir, "merge-log.txt"), "--campaign-dir", campaignDir})
+	require.NoError(t, command.Execute())
+	assert.Equal(t, scm.PullRequestStatusSuccess, vcMock.PullRequests[0].PRStatus)
+}
+
+func TestApplyInvalidManifest(t *testing.T) {
+	cmd.OverrideVersionController = &vcmock.VersionController{}
+
+	tmpDir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		manifest string
+		errorMsg string
+	}{
+		{
+			name: "missing fields",
+			manifest: `
+version: 1
+targets:
+  org: [my-org]
+`,
+			errorMsg: "branch has to be set, either script or steps has to be set, name has to be set, pull-request.title or pull-request.commit-message has to be set",
+		},
+		{
+			name: "unknown key",
+			manifest: `
+version: 1
+name: test
+targets:
+  org: [my-org]
+  token: abc
+script: ./script.sh
+branch: test
+pull-request:
+  title: Test
+`,
+			errorMsg: `unknown key "token" in targets`,
+		},
+		{
+			name: "unknown version",
+			manifest: `
+version: 2
+name: test
+targets:
+  org: [my-org]
+script: ./script.sh
+branch: test
+pull-request:
+  title: Test
+`,
+			errorMsg: "unsupported version 2",
+		},
+		{
+			name: "invalid value",
+			manifest: `
+version: 1
+name: test
+targets:
+  org: [my-org]
+script: ./script.sh
+branch: test
+pull-request:
+  title: Test
+rollout:
+  concurrent: many
+`,
+			errorMsg: `could not set "concurrent"`,
+		},
+		{
+			name: "invalid merge value",
+			manifest: `
+version: 1
+name: test
+targets:
+  org: [my-org]
+script: ./script.sh
+branch: test
+pull-request:
+  title: Test
+merge:
+  concurrent: many
+`,
+			errorMsg: `could not set "concurrent"`,
+		},
+		{
+			name: "invalid conflict strategy",
+			manifest: `
+version: 1
+name: test
+targets:
+  org: [my-org]
+script: ./script.sh
+branch: test
+pull-request:
+  title: Test
+rollout:
+  conflict-strategy: ignore
+`,
+			errorMsg: `could not parse "ignore" as conflict strategy`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			manifestPath := writeManifest(t, tmpDir, test.manifest)
+
+			command := cmd.RootCmd()
+			command.SetArgs([]string{"apply", manifestPath, "validate", "--log-file", ""})
+			command.SetOut(io.Discard)
+			command.SetErr(io.Discard)
+			err := command.Execute()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.errorMsg)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+)
+
+// TestApprove tests that open pull requests are approved, except the ones created by the authenticated user
+func TestApprove(t *testing.T) {
+	pullRequest := func(number int, repoName, author string, status scm.PullRequestStatus) vcmock.PullRequest {
+		pr := mockPullRequest(number, repoName, status)
+		pr.Author = author
+		return pr
+	}
+
+	vcMock := &vcmock.VersionController{
+		CurrentUser: "admin",
+		PullRequests: []vcmock.PullRequest{
+			pullRequest(1, "repo1", "bot", scm.PullRequestStatusPending),
+			pullRequest(2, "repo2", "admin", scm.PullRequestStatusSuccess),
+			pullRequest(3, "repo3", "bot", scm.PullRequestStatusSuccess),
+			pullRequest(4, "repo4", "bot", scm.PullRequestStatusClosed),
+		},
+	}
+	cmd.OverrideVersionController = vcMock
+
+	out, err := executeCommand(t, "approve", "--dry-run")
+	require.NoError(t, err)
+	assert.Equal(t, `Pull requests that would be approved:
+  owner/repo1 #1
+  owner/repo2 #2
+  owner/repo3 #3
+`, out)
+	for _, pr := range vcMock.PullRequests {
+		assert.Zero(t, pr.Approvals)
+	}
+
+	out, err = executeCommand(t, "approve", "--status", "pending,success")
+	require.NoError(t, err)
+	assert.Equal(t, `Approved pull requests:
+  owner/repo1 #1
+  owner/repo3 #3
+Skipped pull requests:
+  owner/repo2 #2: created by the authenticated user
+`, out)
+	assert.Equal(t, 1, vcMock.PullRequests[0].Approvals)
+	assert.Equal(t, scm.ReviewDecisionApproved, vcMock.PullRequests[0].ReviewDecision)
+	assert.Equal(t, 0, vcMock.PullRequests[1].Approvals)
+	assert.Equal(t, 1, vcMock.PullRequests[2].Approvals)
+	assert.Equal(t, 0, vcMock.PullRequests[3].Approvals)
+}
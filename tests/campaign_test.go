@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCampaign tests that status, merge and close only use the pull requests recorded for a campaign
+func TestCampaign(t *testing.T) {
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir := t.TempDir()
+	campaignDir := filepath.Join(tmpDir, "campaigns")
+
+	workingDir, err := os.Getwd()
+	require.NoError(t, err)
+	changerBinaryPath := normalizePath(filepath.Join(workingDir, changerBinaryPath))
+
+	recordedRepo := createRepo(t, "owner", "recorded", "i like apples")
+	vcMock.AddRepository(recordedRepo)
+
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"run",
+		"--log-file", filepath.Join(tmpDir, "run-log.txt"),
+		"--output", filepath.Join(tmpDir, "run-out.txt"),
+		"--author-name", "Test Author",
+		"--author-email", "test@example.com",
+		"--campaign", "bananas",
+		"--campaign-dir", campaignDir,
+		"-B", "campaign-branch",
+		"-m", "test",
+		changerBinaryPath,
+	})
+	require.NoError(t, command.Execute())
+	require.Len(t, vcMock.PullRequests, 1)
+
+	// A pull request on the same branch, that was not created by the campaign
+	notRecordedRepo := createRepo(t, "owner", "not-recorded", "i like apples")
+	vcMock.AddRepository(notRecordedRepo)
+	command = cmd.RootCmd()
+	command.SetArgs([]string{
+		"run",
+		"--log-file", filepath.Join(tmpDir, "run-log.txt"),
+		"--output", filepath.Join(tmpDir, "run-out.txt"),
+		"--author-name", "Test Author",
+		"--author-email", "test@example.com",
+		"--repo-include", "not-recorded",
+		"-B", "campaign-branch",
+		"-m", "test",
+		changerBinaryPath,
+	})
+	require.NoError(t, command.Execute())
+	require.Len(t, vcMock.PullRequests, 2)
+
+	// Reusing the campaign with another branch is not allowed
+	command = cmd.RootCmd()
+	command.SetArgs([]string{
+		"run",
+		"--log-file", filepath.Join(tmpDir, "run-log.txt"),
+		"--campaign", "bananas",
+		"--campaign-dir", campaignDir,
+		"-B", "other-branch",
+		"-m", "test",
+		changerBinaryPath,
+	})
+	assert.ErrorContains(t, command.Execute(), `the campaign "bananas" uses the branch "campaign-branch", not "other-branch"`)
+
+	statusOutFile := filepath.Join(tmpDir, "status-out.txt")
+	command = cmd.RootCmd()
+	command.SetArgs([]string{
+		"status",
+		"--output", statusOutFile,
+		"--campaign", "bananas",
+		"--campaign-dir", campaignDir,
+	})
+	require.NoError(t, command.Execute())
+	statusOut, err := os.ReadFile(statusOutFile)
+	require.NoError(t, err)
+	assert.Equal(t, "owner/recorded #1: Pending\n", string(statusOut))
+
+	vcMock.SetPRStatus("recorded", "campaign-branch", scm.PullRequestStatusSuccess)
+	vcMock.SetPRStatus("not-recorded", "campaign-branch", scm.PullRequestStatusSuccess)
+
+	command = cmd.RootCmd()
+	command.SetArgs([]string{
+		"merge",
+		"--log-file", filepath.Join(tmpDir, "merge-log.txt"),
+		"--campaign", "bananas",
+		"--campaign-dir", campaignDir,
+	})
+	require.NoError(t, command.Execute())
+	assert.Equal(t, scm.PullRequestStatusMerged, vcMock.PullRequests[0].PRStatus)
+	assert.Equal(t, scm.PullRequestStatusSuccess, vcMock.PullRequests[1].PRStatus)
+
+	command = cmd.RootCmd()
+	command.SetArgs([]string{
+		"status",
+		"--log-file", filepath.Join(tmpDir, "status-log.txt"),
+		"--campaign", "does-not-exist",
+		"--campaign-dir", campaignDir,
+	})
+	assert.ErrorContains(t, command.Execute(), "campaign not found")
+}
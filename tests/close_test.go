@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+)
+
+// failingCloseVersionController fails to close the pull request of one repository
+type failingCloseVersionController struct {
+	*vcmock.VersionController
+	failRepo string
+}
+
+func (vc *failingCloseVersionController) ClosePullRequest(ctx context.Context, pr scm.PullRequest) error {
+	if pr.(vcmock.PullRequest).RepoName == vc.failRepo {
+		return errors.New("the pull request is locked")
+	}
+	return vc.VersionController.ClosePullRequest(ctx, pr)
+}
+
+// TestCloseOptions tests comments, branch deletion, filters and the summary of the close command
+func TestCloseOptions(t *testing.T) {
+	pullRequest := func(number int, repoName string, age time.Duration, labels ...string) vcmock.PullRequest {
+		pr := mockPullRequest(number, repoName, scm.PullRequestStatusSuccess)
+		pr.CreatedAt = time.Now().Add(-age)
+		pr.Labels = labels
+		return pr
+	}
+
+	vc := &failingCloseVersionController{
+		VersionController: &vcmock.VersionController{
+			PullRequests: []vcmock.PullRequest{
+				pullRequest(1, "old", 40*24*time.Hour, "dependencies"),
+				pullRequest(2, "new", time.Hour, "dependencies"),
+				pullRequest(3, "unlabeled", 40*24*time.Hour),
+				pullRequest(4, "locked", 40*24*time.Hour, "automated", "dependencies"),
+			},
+		},
+		failRepo: "locked",
+	}
+	cmd.OverrideVersionController = vc
+
+	pr := func(repoName string) vcmock.PullRequest {
+		for _, pr := range vc.PullRequests {
+			if pr.RepoName == repoName {
+				return pr
+			}
+		}
+		t.Fatalf("could not find the pull request of %s", repoName)
+		return vcmock.PullRequest{}
+	}
+
+	out, err := executeCommand(t, "close", "--older-than", "30d", "--label", "Dependencies", "--comment", "Replaced by a newer version", "--delete-branch")
+	assert.EqualError(t, err, "1 of 2 pull requests could not be closed")
+	assert.Equal(t, `Closed pull requests:
+  owner/old #1
+Pull requests that failed to close:
+  owner/locked #4: the pull request is locked
+`, out)
+
+	assert.Equal(t, scm.PullRequestStatusClosed, pr("old").PRStatus)
+	assert.Equal(t, []string{"Replaced by a newer version"}, pr("old").Comments)
+	assert.True(t, pr("old").BranchDeleted)
+
+	assert.Equal(t, scm.PullRequestStatusSuccess, pr("locked").PRStatus)
+	assert.False(t, pr("locked").BranchDeleted)
+
+	assert.Equal(t, scm.PullRequestStatusSuccess, pr("new").PRStatus)
+	assert.Equal(t, scm.PullRequestStatusSuccess, pr("unlabeled").PRStatus)
+
+	out, err = executeCommand(t, "close", "--newer-than", "1d")
+	require.NoError(t, err)
+	assert.Equal(t, "Closed pull requests:\n  owner/new #2\n", out)
+	assert.Equal(t, scm.PullRequestStatusClosed, pr("new").PRStatus)
+	assert.Empty(t, pr("new").Comments)
+	assert.False(t, pr("new").BranchDeleted)
+
+	_, err = executeCommand(t, "close", "--older-than", "a while")
+	assert.EqualError(t, err, `not a valid age: "a while"`)
+}
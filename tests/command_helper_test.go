@@ -0,0 +1,40 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+)
+
+// mockPullRequest creates a mock pull request of the default feature branch, in a repository owned by "owner"
+func mockPullRequest(number int, repoName string, status scm.PullRequestStatus) vcmock.PullRequest {
+	return vcmock.PullRequest{
+		PRStatus:       status,
+		PRNumber:       number,
+		Repository:     vcmock.Repository{OwnerName: "owner", RepoName: repoName},
+		NewPullRequest: scm.NewPullRequest{Head: "multi-gitter-branch"},
+	}
+}
+
+// executeCommand runs a multi-gitter command with its output written to a file, and returns the output and the error of the command.
+// The output is empty if the command failed before it was written
+func executeCommand(t *testing.T, args ...string) (string, error) {
+	outFile := filepath.Join(t.TempDir(), "out.txt")
+
+	command := cmd.RootCmd()
+	command.SetArgs(append(args, "--output", outFile))
+	err := command.Execute()
+
+	data, readErr := os.ReadFile(outFile)
+	if os.IsNotExist(readErr) {
+		return "", err
+	}
+	require.NoError(t, readErr)
+	return string(data), err
+}
@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+)
+
+// TestComment tests templated comments, dry-run and that comments are not posted twice
+func TestComment(t *testing.T) {
+	vcMock := &vcmock.VersionController{
+		PullRequests: []vcmock.PullRequest{
+			{
+				PRStatus:       scm.PullRequestStatusNeedsReview,
+				PRNumber:       1,
+				Repository:     vcmock.Repository{OwnerName: "owner", RepoName: "repo1"},
+				NewPullRequest: scm.NewPullRequest{Title: "Update dependencies", Head: "multi-gitter-branch"},
+			},
+			{
+				PRStatus:       scm.PullRequestStatusPending,
+				PRNumber:       2,
+				Repository:     vcmock.Repository{OwnerName: "owner", RepoName: "repo2"},
+				NewPullRequest: scm.NewPullRequest{Title: "Update dependencies", Head: "multi-gitter-branch"},
+			},
+			{
+				PRStatus:       scm.PullRequestStatusMerged,
+				PRNumber:       3,
+				Repository:     vcmock.Repository{OwnerName: "owner", RepoName: "repo3"},
+				NewPullRequest: scm.NewPullRequest{Title: "Update dependencies", Head: "multi-gitter-branch"},
+			},
+		},
+	}
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir := t.TempDir()
+	comments := func() [][]string {
+		var comments [][]string
+		for _, pr := range vcMock.PullRequests {
+			comments = append(comments, pr.Comments)
+		}
+		return comments
+	}
+
+	commentFile := filepath.Join(tmpDir, "comment.md")
+	require.NoError(t, os.WriteFile(commentFile, []byte("Please approve {{.Repository}} #{{.Number}} by Friday\n"), 0600))
+
+	out, err := executeCommand(t, "comment", "--body-file", commentFile, "--dry-run")
+	require.NoError(t, err)
+	assert.Equal(t, `Pull requests that would be commented on:
+  owner/repo1 #1
+  owner/repo2 #2
+`, out)
+	assert.Equal(t, [][]string{nil, nil, nil}, comments())
+
+	out, err = executeCommand(t, "comment", "--body-file", commentFile, "--status", "needs-review")
+	require.NoError(t, err)
+	assert.Equal(t, "Commented pull requests:\n  owner/repo1 #1\n", out)
+
+	out, err = executeCommand(t, "comment", "--body-file", commentFile)
+	require.NoError(t, err)
+	assert.Equal(t, `Commented pull requests:
+  owner/repo2 #2
+Skipped pull requests:
+  owner/repo1 #1: already commented
+`, out)
+	assert.Equal(t, [][]string{
+		{"Please approve owner/repo1 #1 by Friday\n"},
+		{"Please approve owner/repo2 #2 by Friday\n"},
+		nil,
+	}, comments())
+
+	_, err = executeCommand(t, "comment", "--body-file", commentFile, "--allow-duplicates", "--status", "pending")
+	require.NoError(t, err)
+	assert.Len(t, vcMock.PullRequests[1].Comments, 2)
+
+	_, err = executeCommand(t, "comment", "--body", "{{.Missing}}")
+	assert.EqualError(t, err, "2 of 2 pull requests could not be commented on")
+
+	_, err = executeCommand(t, "comment")
+	assert.EqualError(t, err, "a comment has to be set with --body or --body-file")
+}
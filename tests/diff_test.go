@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+)
+
+// TestDiff tests showing the diffs of pull requests, and grouping the ones that are identical
+func TestDiff(t *testing.T) {
+	repo1 := createRepo(t, "owner", "repo1", "i like apples")
+	repo2 := createRepo(t, "owner", "repo2", "i like apples")
+	fixedRepo := createRepo(t, "owner", "fixed", "i like apples")
+
+	vc := &vcmock.VersionController{}
+	vc.AddRepository(repo1, repo2, fixedRepo)
+	defer vc.Clean()
+	cmd.OverrideVersionController = vc
+
+	for i, repo := range []vcmock.Repository{repo1, repo2, fixedRepo} {
+		changeBranch(t, repo.Path, "multi-gitter-branch", true)
+		changeTestFile(t, repo.Path, "i like bananas", "Change to bananas")
+		if repo.RepoName == "fixed" {
+			addFile(t, repo.Path, "fixup.txt", "fixed manually", "Fix the change")
+		}
+		changeBranch(t, repo.Path, "master", false)
+
+		vc.PullRequests = append(vc.PullRequests, vcmock.PullRequest{
+			PRStatus:       scm.PullRequestStatusPending,
+			PRNumber:       i + 1,
+			Repository:     repo,
+			NewPullRequest: scm.NewPullRequest{Head: "multi-gitter-branch"},
+		})
+	}
+	// Changes made to the base branch after the pull request was created are not part of its diff
+	addFile(t, repo2.Path, "new-file.txt", "new", "Add new file")
+
+	bananaDiff := "--- a/test.txt\n+++ b/test.txt\n@@ -1 +1 @@\n-i like apples\n\\ No newline at end of file\n+i like bananas\n\\ No newline at end of file\n"
+	fixupDiff := "diff --git a/fixup.txt b/fixup.txt\nnew file mode 100644\n"
+
+	out, err := executeCommand(t, "diff")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out, "owner/fixed #3:\ndiff --git a/fixup.txt b/fixup.txt\n"), out)
+	assert.Contains(t, out, "\nowner/repo1 #1:\n")
+	assert.Contains(t, out, "\nowner/repo2 #2:\n")
+	assert.Equal(t, 3, strings.Count(out, bananaDiff))
+	assert.Equal(t, 1, strings.Count(out, fixupDiff))
+	assert.NotContains(t, out, "new-file.txt")
+
+	out, err = executeCommand(t, "diff", "--group-identical")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out, "2 pull requests with the same diff:\n  owner/repo1 #1\n  owner/repo2 #2\ndiff --git a/test.txt b/test.txt\n"), out)
+	assert.Contains(t, out, "\nowner/fixed #3:\n")
+	assert.Equal(t, 2, strings.Count(out, bananaDiff))
+
+	out, err = executeCommand(t, "diff", "--deviating")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out, "owner/fixed #3:\n"), out)
+	assert.NotContains(t, out, "repo1")
+	assert.NotContains(t, out, "repo2")
+	assert.Equal(t, 1, strings.Count(out, fixupDiff))
+}
+
+// TestDiffTargetBranchAndFork tests that pull requests are compared with the branch they target, also when made from forks
+func TestDiffTargetBranchAndFork(t *testing.T) {
+	releaseRepo := createRepo(t, "owner", "release", "i like apples")
+	forkRepo := createRepo(t, "owner", "fork", "i like apples")
+
+	vc := &vcmock.VersionController{}
+	vc.AddRepository(releaseRepo, forkRepo)
+	defer vc.Clean()
+	cmd.OverrideVersionController = vc
+
+	// The pull request targets a release branch, which has changes that are not on the default branch
+	changeBranch(t, releaseRepo.Path, "release", true)
+	addFile(t, releaseRepo.Path, "release.txt", "release", "Prepare release")
+	changeBranch(t, releaseRepo.Path, "multi-gitter-branch", true)
+	changeTestFile(t, releaseRepo.Path, "i like bananas", "Change to bananas")
+	changeBranch(t, releaseRepo.Path, "master", false)
+
+	// The head of a pull request made from a fork only exist as a pull request reference in the repository
+	changeBranch(t, forkRepo.Path, "fork-branch", true)
+	changeTestFile(t, forkRepo.Path, "i like bananas", "Change to bananas")
+	changeBranch(t, forkRepo.Path, "master", false)
+	moveBranch(t, forkRepo.Path, "fork-branch", "refs/pull/2/head")
+
+	vc.PullRequests = []vcmock.PullRequest{
+		{
+			PRStatus:       scm.PullRequestStatusPending,
+			PRNumber:       1,
+			Repository:     releaseRepo,
+			NewPullRequest: scm.NewPullRequest{Head: "multi-gitter-branch", Base: "release"},
+		},
+		{
+			PRStatus:       scm.PullRequestStatusPending,
+			PRNumber:       2,
+			HeadRef:        "refs/pull/2/head",
+			Repository:     forkRepo,
+			NewPullRequest: scm.NewPullRequest{Head: "multi-gitter-branch"},
+		},
+	}
+
+	out, err := executeCommand(t, "diff", "--group-identical")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out, "2 pull requests with the same diff:\n  owner/fork #2\n  owner/release #1\ndiff --git a/test.txt b/test.txt\n"), out)
+	assert.NotContains(t, out, "release.txt")
+}
@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+)
+
+// TestEdit tests that only the metadata that is set is changed when editing pull requests
+func TestEdit(t *testing.T) {
+	pullRequest := func(number int, repoName string, status scm.PullRequestStatus) vcmock.PullRequest {
+		pr := mockPullRequest(number, repoName, status)
+		pr.Title = "Update dependencies"
+		pr.Body = "Updates all dependencies"
+		pr.Reviewers = []string{"alice"}
+		pr.Labels = []string{"dependencies"}
+		return pr
+	}
+
+	vcMock := &vcmock.VersionController{
+		PullRequests: []vcmock.PullRequest{
+			pullRequest(1, "repo1", scm.PullRequestStatusPending),
+			pullRequest(2, "repo2", scm.PullRequestStatusSuccess),
+			pullRequest(3, "repo3", scm.PullRequestStatusMerged),
+		},
+	}
+	for _, pr := range vcMock.PullRequests {
+		vcMock.AddRepository(pr.Repository)
+	}
+	cmd.OverrideVersionController = vcMock
+
+	out, err := executeCommand(t, "edit", "--labels", "dependencies,security", "--dry-run")
+	require.NoError(t, err)
+	assert.Equal(t, `Pull requests that would be edited:
+  owner/repo1 #1
+  owner/repo2 #2
+`, out)
+	assert.Equal(t, []string{"dependencies"}, vcMock.PullRequests[0].Labels)
+
+	out, err = executeCommand(t, "edit", "--labels", "dependencies,security", "--reviewers", "bob")
+	require.NoError(t, err)
+	assert.Equal(t, `Edited pull requests:
+  owner/repo1 #1
+  owner/repo2 #2
+`, out)
+	for _, pr := range vcMock.PullRequests[:2] {
+		assert.Equal(t, "Update dependencies", pr.Title)
+		assert.Equal(t, "Updates all dependencies", pr.Body)
+		assert.Equal(t, []string{"dependencies", "security"}, pr.Labels)
+		assert.Equal(t, []string{"bob"}, pr.Reviewers)
+		assert.False(t, pr.Draft)
+	}
+	assert.Equal(t, []string{"dependencies"}, vcMock.PullRequests[2].Labels)
+
+	_, err = executeCommand(t, "edit", "--status", "pending", "--pr-title", "Update all dependencies", "--draft")
+	require.NoError(t, err)
+	assert.Equal(t, "Update all dependencies", vcMock.PullRequests[0].Title)
+	assert.True(t, vcMock.PullRequests[0].Draft)
+	assert.Equal(t, "Updates all dependencies", vcMock.PullRequests[0].Body)
+	assert.Equal(t, []string{"dependencies", "security"}, vcMock.PullRequests[0].Labels)
+	assert.Equal(t, "Update dependencies", vcMock.PullRequests[1].Title)
+
+	_, err = executeCommand(t, "edit", "--status", "pending", "--draft=false")
+	require.NoError(t, err)
+	assert.False(t, vcMock.PullRequests[0].Draft)
+
+	_, err = executeCommand(t, "edit")
+	assert.EqualError(t, err, "nothing to edit, at least one of --pr-title, --pr-body, --reviewers, --team-reviewers, --assignees, --labels, --draft or --pr-auto-merge has to be set")
+}
+
+// TestRunKeepsDraftState tests that updating a pull request with run does not change if it's a draft, only edit does
+func TestRunKeepsDraftState(t *testing.T) {
+	workingDir, err := os.Getwd()
+	require.NoError(t, err)
+	changerBinaryPath := normalizePath(filepath.Join(workingDir, changerBinaryPath))
+
+	repo := createRepo(t, "owner", "existing-pr", "i like apples")
+	changeBranch(t, repo.Path, "multi-gitter-branch", true)
+	changeTestFile(t, repo.Path, "i like apple", "test change")
+	changeBranch(t, repo.Path, "master", false)
+
+	pr := mockPullRequest(1, "existing-pr", scm.PullRequestStatusDraft)
+	pr.Repository = repo
+	pr.Draft = true
+
+	vcMock := &vcmock.VersionController{
+		Repositories: []vcmock.Repository{repo},
+		PullRequests: []vcmock.PullRequest{pr},
+	}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	_, err = executeCommand(t,
+		"run",
+		"--log-file", filepath.Join(t.TempDir(), "log.txt"),
+		"--author-name", "Test Author",
+		"--author-email", "test@example.com",
+		"-m", "custom message",
+		"--conflict-strategy", "replace",
+		changerBinaryPath,
+	)
+	require.NoError(t, err)
+
+	require.Len(t, vcMock.PullRequests, 1)
+	assert.Equal(t, "custom message", vcMock.PullRequests[0].Title)
+	assert.True(t, vcMock.PullRequests[0].Draft)
+}
+
+// TestEditGerrit tests that editing fails on platforms where changes can't be edited
+func TestEditGerrit(t *testing.T) {
+	vcMock := &vcmock.GerritVersionController{
+		VC: vcmock.VersionController{
+			PullRequests: []vcmock.PullRequest{mockPullRequest(1, "repo1", scm.PullRequestStatusPending)},
+		},
+	}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	_, err := executeCommand(t, "edit", "--pr-title", "New title")
+	assert.EqualError(t, err, "the platform does not support editing pull requests")
+}
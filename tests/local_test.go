@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLocalPlatform tests a full run, status and merge with the local platform, without any version controller mock
+func TestLocalPlatform(t *testing.T) {
+	cmd.OverrideVersionController = nil
+
+	reposDir := t.TempDir()
+	repoPath, err := createDummyRepo("i like apples", reposDir)
+	require.NoError(t, err)
+	_, err = createDummyRepo("i like oranges", reposDir)
+	require.NoError(t, err)
+
+	tmpDir := t.TempDir()
+	workingDir, err := os.Getwd()
+	require.NoError(t, err)
+
+	runOutFile := filepath.Join(tmpDir, "run-out.txt")
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"run",
+		"--platform", "local",
+		"--base-url", reposDir,
+		"--output", runOutFile,
+		"--author-name", "Test Author",
+		"--author-email", "test@example.com",
+		"-B", "local-branch",
+		"-m", "test",
+		normalizePath(filepath.Join(workingDir, changerBinaryPath)),
+	})
+	require.NoError(t, command.Execute())
+
+	runOut, err := os.ReadFile(runOutFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(runOut), filepath.Base(repoPath)+" #1")
+	assert.Contains(t, string(runOut), "No data was changed")
+	assert.True(t, branchExist(t, repoPath, "local-branch"))
+
+	statusOutFile := filepath.Join(tmpDir, "status-out.txt")
+	command = cmd.RootCmd()
+	command.SetArgs([]string{
+		"status",
+		"--platform", "local",
+		"--base-url", reposDir,
+		"--output", statusOutFile,
+		"-B", "local-branch",
+	})
+	require.NoError(t, command.Execute())
+	statusOut, err := os.ReadFile(statusOutFile)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Base(repoPath)+" #1: Success\n", string(statusOut))
+
+	command = cmd.RootCmd()
+	command.SetArgs([]string{
+		"merge",
+		"--platform", "local",
+		"--base-url", reposDir,
+		"--log-file", filepath.Join(tmpDir, "merge-log.txt"),
+		"-B", "local-branch",
+	})
+	require.NoError(t, command.Execute())
+
+	data, err := os.ReadFile(filepath.Join(repoPath, fileName))
+	require.NoError(t, err)
+	assert.Equal(t, "i like bananas", string(data))
+}
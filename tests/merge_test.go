@@ -0,0 +1,186 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+)
+
+// failingMergeVersionController fails to merge the pull request of one repository
+type failingMergeVersionController struct {
+	*pollingVersionController
+	failRepo string
+}
+
+func (vc *failingMergeVersionController) MergePullRequest(ctx context.Context, pr scm.PullRequest) error {
+	if pr.(vcmock.PullRequest).RepoName == vc.failRepo {
+		return errors.New("merge blocked by branch protection")
+	}
+	return vc.pollingVersionController.MergePullRequest(ctx, pr)
+}
+
+// TestMergeOptions tests waiting for checks, required approvals and the summary of the merge command
+func TestMergeOptions(t *testing.T) {
+	pullRequest := func(number int, repoName string, status scm.PullRequestStatus, approvals int) vcmock.PullRequest {
+		pr := mockPullRequest(number, repoName, status)
+		pr.Approvals = approvals
+		return pr
+	}
+
+	vc := &failingMergeVersionController{
+		pollingVersionController: &pollingVersionController{
+			VersionController: &vcmock.VersionController{
+				PullRequests: []vcmock.PullRequest{
+					pullRequest(1, "approved", scm.PullRequestStatusSuccess, 2),
+					pullRequest(2, "pending", scm.PullRequestStatusPending, 2),
+					pullRequest(3, "still-pending", scm.PullRequestStatusPending, 2),
+					pullRequest(4, "one-approval", scm.PullRequestStatusSuccess, 1),
+					pullRequest(5, "protected", scm.PullRequestStatusSuccess, 3),
+					pullRequest(6, "failing", scm.PullRequestStatusError, 2),
+				},
+			},
+			onPoll: func(vc *vcmock.VersionController, poll int) {
+				if poll == 3 {
+					vc.SetPRStatus("pending", "multi-gitter-branch", scm.PullRequestStatusSuccess)
+				}
+			},
+		},
+		failRepo: "protected",
+	}
+	cmd.OverrideVersionController = vc
+
+	tmpDir := t.TempDir()
+	outFile := filepath.Join(tmpDir, "out.txt")
+	logFile := filepath.Join(tmpDir, "log.txt")
+
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"merge",
+		"--output", outFile,
+		"--log-file", logFile,
+		"--wait-for-checks", "100ms",
+		"--check-interval", "1ms",
+		"--required-approvals", "2",
+		"--concurrent", "3",
+		"--merge-interval", "1ms",
+	})
+	err := command.Execute()
+	assert.EqualError(t, err, "1 of 3 pull requests could not be merged")
+
+	// The pull requests are polled until the timeout, since one of them never completes its checks
+	assert.Greater(t, vc.polls, 3)
+
+	out, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+	assert.Equal(t, `Merged pull requests:
+  owner/approved #1
+  owner/pending #2
+Skipped pull requests:
+  owner/failing #6: checks failed
+  owner/one-approval #4: 1 of 2 required approvals
+  owner/still-pending #3: checks did not complete within 100ms
+Pull requests that failed to merge:
+  owner/protected #5: merge blocked by branch protection
+`, string(out))
+
+	logData, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(logData), "Waiting for the checks of 2 pull requests to complete")
+	assert.Contains(t, string(logData), "The checks of 1 pull requests did not complete within 100ms")
+
+	statuses := map[string]scm.PullRequestStatus{}
+	for _, pr := range vc.PullRequests {
+		statuses[pr.RepoName] = pr.PRStatus
+	}
+	assert.Equal(t, map[string]scm.PullRequestStatus{
+		"approved":      scm.PullRequestStatusMerged,
+		"pending":       scm.PullRequestStatusMerged,
+		"still-pending": scm.PullRequestStatusPending,
+		"one-approval":  scm.PullRequestStatusSuccess,
+		"protected":     scm.PullRequestStatusSuccess,
+		"failing":       scm.PullRequestStatusError,
+	}, statuses)
+}
+
+// withoutApprovalsVersionController is a platform that does not report the approvals of pull requests
+type withoutApprovalsVersionController struct {
+	*vcmock.VersionController
+}
+
+func (vc withoutApprovalsVersionController) ReportsApprovals() bool {
+	return false
+}
+
+// TestMergeRequiredApprovalsNotReported tests that required approvals can't be used on platforms that don't report approvals
+func TestMergeRequiredApprovalsNotReported(t *testing.T) {
+	vcMock := &vcmock.VersionController{
+		PullRequests: []vcmock.PullRequest{
+			mockPullRequest(1, "repo", scm.PullRequestStatusSuccess),
+		},
+	}
+	cmd.OverrideVersionController = withoutApprovalsVersionController{VersionController: vcMock}
+
+	command := cmd.RootCmd()
+	command.SetArgs([]string{"merge", "--required-approvals", "1"})
+	err := command.Execute()
+	assert.EqualError(t, err, "the platform does not report the approvals of pull requests, so the required approvals can't be checked")
+	assert.Equal(t, scm.PullRequestStatusSuccess, vcMock.PullRequests[0].PRStatus)
+}
+
+// TestPullRequestCommandFilters tests repository filters and dry-run of the commands acting on existing pull requests
+func TestPullRequestCommandFilters(t *testing.T) {
+	vcMock := &vcmock.VersionController{}
+	for i, name := range []string{"service-a", "sensitive", "service-b"} {
+		repo := vcmock.Repository{OwnerName: "owner", RepoName: name}
+		vcMock.AddRepository(repo)
+		vcMock.PullRequests = append(vcMock.PullRequests, mockPullRequest(i+1, name, scm.PullRequestStatusSuccess))
+	}
+	cmd.OverrideVersionController = vcMock
+
+	execute := func(t *testing.T, args ...string) string {
+		out, err := executeCommand(t, args...)
+		require.NoError(t, err)
+		return out
+	}
+	statuses := func() []scm.PullRequestStatus {
+		var statuses []scm.PullRequestStatus
+		for _, pr := range vcMock.PullRequests {
+			statuses = append(statuses, pr.PRStatus)
+		}
+		return statuses
+	}
+
+	assert.Equal(t, "owner/sensitive #2: Success\n", execute(t, "status", "--repo-include", "sensitive"))
+
+	assert.Equal(t, `Pull requests that would be merged:
+  owner/service-a #1
+  owner/service-b #3
+`, execute(t, "merge", "--dry-run", "--skip-repo", "owner/sensitive"))
+
+	assert.Equal(t, `Pull requests that would be closed:
+  owner/service-a #1
+  owner/sensitive #2
+`, execute(t, "close", "--dry-run", "--repo-exclude", "service-b$"))
+
+	// Nothing is changed by a dry-run
+	assert.Equal(t, []scm.PullRequestStatus{
+		scm.PullRequestStatusSuccess, scm.PullRequestStatusSuccess, scm.PullRequestStatusSuccess,
+	}, statuses())
+
+	assert.Equal(t, `Merged pull requests:
+  owner/service-a #1
+  owner/service-b #3
+`, execute(t, "merge", "--skip-repo", "owner/sensitive"))
+	assert.Equal(t, []scm.PullRequestStatus{
+		scm.PullRequestStatusMerged, scm.PullRequestStatusSuccess, scm.PullRequestStatusMerged,
+	}, statuses())
+}
@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/multigitter/multiplex"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMultiplex tests that a run covers the repositories of several platforms, and that each operation is made on the correct platform
+func TestMultiplex(t *testing.T) {
+	githubMock := &vcmock.VersionController{}
+	defer githubMock.Clean()
+	gitlabMock := &vcmock.VersionController{}
+	defer gitlabMock.Clean()
+
+	// The same repository name exist on both platforms
+	githubMock.AddRepository(createRepo(t, "owner", "repo", "i like apples"))
+	gitlabMock.AddRepository(createRepo(t, "owner", "repo", "i like apples"))
+	gitlabMock.AddRepository(createRepo(t, "owner", "other-repo", "i like oranges"))
+
+	vc, err := multiplex.New(
+		multiplex.Source{Name: "github", VersionController: githubMock},
+		multiplex.Source{Name: "gitlab", VersionController: gitlabMock},
+	)
+	require.NoError(t, err)
+	cmd.OverrideVersionController = vc
+
+	tmpDir := t.TempDir()
+	workingDir, err := os.Getwd()
+	require.NoError(t, err)
+
+	runOutFile := filepath.Join(tmpDir, "run-out.txt")
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"run",
+		"--output", runOutFile,
+		"--author-name", "Test Author",
+		"--author-email", "test@example.com",
+		"-B", "multiplex-branch",
+		"-m", "test",
+		normalizePath(filepath.Join(workingDir, changerBinaryPath)),
+	})
+	require.NoError(t, command.Execute())
+
+	require.Len(t, githubMock.PullRequests, 1)
+	require.Len(t, gitlabMock.PullRequests, 1)
+
+	runOut, err := os.ReadFile(runOutFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(runOut), "[github] owner/repo #1")
+	assert.Contains(t, string(runOut), "[gitlab] owner/repo #1")
+
+	gitlabMock.SetPRStatus("repo", "multiplex-branch", scm.PullRequestStatusSuccess)
+
+	command = cmd.RootCmd()
+	command.SetArgs([]string{
+		"merge",
+		"--log-file", filepath.Join(tmpDir, "merge-log.txt"),
+		"-B", "multiplex-branch",
+	})
+	require.NoError(t, command.Execute())
+	assert.Equal(t, scm.PullRequestStatusPending, githubMock.PullRequests[0].PRStatus)
+	assert.Equal(t, scm.PullRequestStatusMerged, gitlabMock.PullRequests[0].PRStatus)
+
+	statusOutFile := filepath.Join(tmpDir, "status-out.txt")
+	command = cmd.RootCmd()
+	command.SetArgs([]string{
+		"status",
+		"--output", statusOutFile,
+		"-B", "multiplex-branch",
+	})
+	require.NoError(t, command.Execute())
+	statusOut, err := os.ReadFile(statusOutFile)
+	require.NoError(t, err)
+	assert.Equal(t, "[github] owner/repo #1: Pending\n[gitlab] owner/repo #1: Merged\n", string(statusOut))
+}
+
+// TestMultiplexGerrit tests that the Gerrit specific parts of a run are only used for the repositories of the Gerrit platform
+func TestMultiplexGerrit(t *testing.T) {
+	githubMock := &vcmock.VersionController{}
+	defer githubMock.Clean()
+	gerritMock := &vcmock.GerritVersionController{}
+	defer gerritMock.Clean()
+
+	githubMock.AddRepository(createRepo(t, "owner", "repo", "i like apples"))
+	gerritMock.VC.AddRepository(createRepo(t, "owner", "repo", "i like apples"))
+
+	vc, err := multiplex.New(
+		multiplex.Source{Name: "github", VersionController: githubMock},
+		multiplex.Source{Name: "gerrit", VersionController: gerritMock},
+	)
+	require.NoError(t, err)
+	cmd.OverrideVersionController = vc
+
+	workingDir, err := os.Getwd()
+	require.NoError(t, err)
+
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"run",
+		"--log-file", filepath.Join(t.TempDir(), "log.txt"),
+		"--author-name", "Test Author",
+		"--author-email", "test@example.com",
+		"-B", "multiplex-branch",
+		"-m", "test",
+		normalizePath(filepath.Join(workingDir, changerBinaryPath)),
+	})
+	require.NoError(t, command.Execute())
+
+	require.Len(t, githubMock.PullRequests, 1)
+	require.Len(t, gerritMock.VC.PullRequests, 1)
+
+	commitMessage, err := getCommitMessage(t, gerritMock.VC.Repositories[0].Path, "refs/heads/mocked-multiplex-branch")
+	require.NoError(t, err)
+	assert.Equal(t, "test\n\nMocked-Footer: multiplex-branch", strings.TrimSuffix(commitMessage, "\n"))
+
+	commitMessage, err = getCommitMessage(t, githubMock.Repositories[0].Path, "refs/heads/multiplex-branch")
+	require.NoError(t, err)
+	assert.Equal(t, "test", strings.TrimSuffix(commitMessage, "\n"))
+}
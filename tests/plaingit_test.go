@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPlainGitPlatform tests pushing to repositories defined only by their clone URLs
+func TestPlainGitPlatform(t *testing.T) {
+	cmd.OverrideVersionController = nil
+
+	repoPath, err := createDummyRepo("i like apples", t.TempDir())
+	require.NoError(t, err)
+
+	tmpDir := t.TempDir()
+	workingDir, err := os.Getwd()
+	require.NoError(t, err)
+	changer := normalizePath(filepath.Join(workingDir, changerBinaryPath))
+
+	// Pull requests can't be created
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"run",
+		"--platform", "git",
+		"--repo", repoPath,
+		"--log-file", filepath.Join(tmpDir, "log.txt"),
+		"-m", "test",
+		changer,
+	})
+	command.SilenceErrors = true
+	command.SilenceUsage = true
+	assert.ErrorContains(t, command.Execute(), "the git platform does not support pull requests")
+
+	command = cmd.RootCmd()
+	command.SetArgs([]string{
+		"run",
+		"--platform", "git",
+		"--repo", repoPath,
+		"--log-file", filepath.Join(tmpDir, "log.txt"),
+		"--author-name", "Test Author",
+		"--author-email", "test@example.com",
+		"--output", filepath.Join(tmpDir, "out.txt"),
+		"--push-only",
+		"-B", "plain-git-branch",
+		"-m", "test",
+		changer,
+	})
+	require.NoError(t, command.Execute())
+	assert.True(t, branchExist(t, repoPath, "plain-git-branch"))
+
+	// Change branch so that the branch that is pushed to is not checked out
+	changeBranch(t, repoPath, "test", true)
+
+	command = cmd.RootCmd()
+	command.SetArgs([]string{
+		"run",
+		"--platform", "git",
+		"--repo", repoPath,
+		"--log-file", filepath.Join(tmpDir, "log.txt"),
+		"--author-name", "Test Author",
+		"--author-email", "test@example.com",
+		"--output", filepath.Join(tmpDir, "out.txt"),
+		"--skip-pr",
+		"--base-branch", "master",
+		"-m", "test",
+		changer,
+	})
+	require.NoError(t, command.Execute())
+
+	changeBranch(t, repoPath, "master", false)
+	data, err := os.ReadFile(filepath.Join(repoPath, fileName))
+	require.NoError(t, err)
+	assert.Equal(t, "i like bananas", string(data))
+}
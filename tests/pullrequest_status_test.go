@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+)
+
+// TestPullRequestStatuses tests that merge only merges pull requests that are ready, and that status and close can filter on statuses
+func TestPullRequestStatuses(t *testing.T) {
+	vcMock := &vcmock.VersionController{
+		PullRequests: []vcmock.PullRequest{
+			mockPullRequest(1, "ready", scm.PullRequestStatusSuccess),
+			mockPullRequest(2, "unapproved", scm.PullRequestStatusNeedsReview),
+			mockPullRequest(3, "rejected", scm.PullRequestStatusChangesRequested),
+			mockPullRequest(4, "conflicting", scm.PullRequestStatusConflicting),
+			mockPullRequest(5, "draft", scm.PullRequestStatusDraft),
+		},
+	}
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir := t.TempDir()
+
+	//
+	// Merge
+	//
+	mergeLogFile := filepath.Join(tmpDir, "merge-log.txt")
+
+	command := cmd.RootCmd()
+	command.SetArgs([]string{"merge", "--log-file", mergeLogFile})
+	require.NoError(t, command.Execute())
+
+	mergeLogData, err := os.ReadFile(mergeLogFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(mergeLogData), "Merging 1 pull requests")
+	assert.Contains(t, string(mergeLogData), `Skipping pull request that is not ready to be merged: review required" pr="owner/unapproved #2"`)
+	assert.Contains(t, string(mergeLogData), `Skipping pull request that is not ready to be merged: conflicts with the base branch" pr="owner/conflicting #4"`)
+
+	//
+	// Status
+	//
+	statusOutFile := filepath.Join(tmpDir, "status-out.txt")
+
+	command = cmd.RootCmd()
+	command.SetArgs([]string{"status", "--output", statusOutFile, "--status", "needs-review,ChangesRequested"})
+	require.NoError(t, command.Execute())
+
+	statusOutData, err := os.ReadFile(statusOutFile)
+	require.NoError(t, err)
+	assert.Equal(t, "owner/unapproved #2: NeedsReview\nowner/rejected #3: ChangesRequested\n", string(statusOutData))
+
+	//
+	// Close
+	//
+	closeLogFile := filepath.Join(tmpDir, "close-log.txt")
+
+	command = cmd.RootCmd()
+	command.SetArgs([]string{"close", "--log-file", closeLogFile, "--status", "conflicting,draft"})
+	require.NoError(t, command.Execute())
+
+	closeLogData, err := os.ReadFile(closeLogFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(closeLogData), "Closing 2 pull requests")
+
+	statuses := map[string]scm.PullRequestStatus{}
+	for _, pr := range vcMock.PullRequests {
+		statuses[pr.RepoName] = pr.PRStatus
+	}
+	assert.Equal(t, map[string]scm.PullRequestStatus{
+		"ready":       scm.PullRequestStatusMerged,
+		"unapproved":  scm.PullRequestStatusNeedsReview,
+		"rejected":    scm.PullRequestStatusChangesRequested,
+		"conflicting": scm.PullRequestStatusClosed,
+		"draft":       scm.PullRequestStatusClosed,
+	}, statuses)
+
+	//
+	// Invalid status
+	//
+	command = cmd.RootCmd()
+	command.SetArgs([]string{"status", "--status", "approved"})
+	assert.EqualError(t, command.Execute(), `not a valid pull request status: "approved"`)
+}
@@ -95,6 +95,17 @@ func changeBranch(t *testing.T, path string, branchName string, create bool) {
 	assert.NoError(t, err)
 }
 
+// moveBranch moves a branch to another reference, like the ones platforms keep for pull requests made from forks
+func moveBranch(t *testing.T, path string, branchName string, referenceName string) {
+	repo, err := git.PlainOpen(path)
+	require.NoError(t, err)
+
+	branch, err := repo.Reference(plumbing.NewBranchReferenceName(branchName), false)
+	require.NoError(t, err)
+	require.NoError(t, repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(referenceName), branch.Hash())))
+	require.NoError(t, repo.Storer.RemoveReference(branch.Name()))
+}
+
 func branchExist(t *testing.T, path string, branchName string) bool {
 	repo, err := git.PlainOpen(path)
 	assert.NoError(t, err)
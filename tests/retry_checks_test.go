@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+)
+
+// TestRetryChecks tests that the checks of failed pull requests are re-run until the maximum number of attempts is reached
+func TestRetryChecks(t *testing.T) {
+	pullRequest := func(number int, repoName string, status scm.PullRequestStatus, checksRetried int) vcmock.PullRequest {
+		pr := mockPullRequest(number, repoName, status)
+		pr.ChecksRetried = checksRetried
+		return pr
+	}
+
+	vcMock := &vcmock.VersionController{
+		PullRequests: []vcmock.PullRequest{
+			pullRequest(1, "repo1", scm.PullRequestStatusError, 0),
+			pullRequest(2, "repo2", scm.PullRequestStatusError, 2),
+			pullRequest(3, "repo3", scm.PullRequestStatusSuccess, 0),
+			pullRequest(4, "repo4", scm.PullRequestStatusMerged, 0),
+		},
+	}
+	// A draft gets the draft status even though its checks fail
+	draft := pullRequest(5, "repo5", scm.PullRequestStatusDraft, 0)
+	draft.Draft = true
+	draft.Checks = []scm.Check{
+		{Name: "build", Status: scm.CheckStatusSuccess},
+		{Name: "test", Status: scm.CheckStatusFailure},
+	}
+	vcMock.PullRequests = append(vcMock.PullRequests, draft)
+	cmd.OverrideVersionController = vcMock
+
+	out, err := executeCommand(t, "retry-checks", "--dry-run")
+	require.NoError(t, err)
+	assert.Equal(t, `Pull requests that would have their checks re-run:
+  owner/repo1 #1
+  owner/repo2 #2
+  owner/repo5 #5
+`, out)
+	assert.Equal(t, scm.PullRequestStatusError, vcMock.PullRequests[0].PRStatus)
+
+	out, err = executeCommand(t, "retry-checks")
+	require.NoError(t, err)
+	assert.Equal(t, `Pull requests with re-run checks:
+  owner/repo1 #1
+  owner/repo5 #5
+Skipped pull requests:
+  owner/repo2 #2: the checks have already been run 3 times
+`, out)
+	assert.Equal(t, 1, vcMock.PullRequests[0].ChecksRetried)
+	assert.Equal(t, scm.PullRequestStatusPending, vcMock.PullRequests[0].PRStatus)
+	assert.Equal(t, scm.PullRequestStatusError, vcMock.PullRequests[1].PRStatus)
+	assert.Equal(t, 1, vcMock.PullRequests[4].ChecksRetried)
+	assert.Equal(t, scm.CheckStatusPending, vcMock.PullRequests[4].Checks[1].Status)
+
+	// The checks fail again, and are re-run a second time when more attempts are allowed
+	vcMock.PullRequests[0].PRStatus = scm.PullRequestStatusError
+	out, err = executeCommand(t, "retry-checks", "--max-attempts", "4")
+	require.NoError(t, err)
+	assert.Equal(t, `Pull requests with re-run checks:
+  owner/repo1 #1
+  owner/repo2 #2
+`, out)
+	assert.Equal(t, 2, vcMock.PullRequests[0].ChecksRetried)
+	assert.Equal(t, 3, vcMock.PullRequests[1].ChecksRetried)
+
+	_, err = executeCommand(t, "retry-checks", "--max-attempts", "1")
+	assert.EqualError(t, err, "the maximum number of attempts has to be at least 2")
+}
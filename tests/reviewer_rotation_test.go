@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+)
+
+// TestReviewerRotation tests that reviews are spread over the available reviewers, and that the counts are stored
+func TestReviewerRotation(t *testing.T) {
+	workingDir, err := os.Getwd()
+	require.NoError(t, err)
+	changerBinaryPath := normalizePath(filepath.Join(workingDir, changerBinaryPath))
+
+	vcMock := &vcmock.VersionController{
+		Repositories: []vcmock.Repository{
+			createRepo(t, "owner", "repo-1", "i like apples"),
+			createRepo(t, "owner", "repo-2", "i like apples"),
+			createRepo(t, "owner", "repo-3", "i like apples"),
+			createRepo(t, "owner", "repo-4", "i like apples"),
+		},
+	}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir := t.TempDir()
+	unavailableFile := filepath.Join(tmpDir, "unavailable-reviewers.txt")
+	require.NoError(t, os.WriteFile(unavailableFile, []byte("# On vacation\nreviewer4\n"), 0600))
+	rotationFile := filepath.Join(tmpDir, "rotation.json")
+
+	_, err = executeCommand(t,
+		"run",
+		"--log-file", filepath.Join(tmpDir, "log.txt"),
+		"--author-name", "Test Author",
+		"--author-email", "test@example.com",
+		"-m", "custom message",
+		"-r", "reviewer1,reviewer2,reviewer3,reviewer4",
+		"--max-reviewers", "2",
+		"--unavailable-reviewers-file", unavailableFile,
+		"--reviewer-rotation-file", rotationFile,
+		changerBinaryPath,
+	)
+	require.NoError(t, err)
+
+	require.Len(t, vcMock.PullRequests, 4)
+	counts := map[string]int{}
+	for _, pr := range vcMock.PullRequests {
+		require.Len(t, pr.Reviewers, 2)
+		for _, reviewer := range pr.Reviewers {
+			counts[reviewer]++
+		}
+	}
+	assert.Equal(t, map[string]int{"reviewer1": 3, "reviewer2": 3, "reviewer3": 2}, counts)
+
+	data, err := os.ReadFile(rotationFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"reviewer3": 2`)
+}
+
+// TestReviewerRotationWithoutPullRequests tests that reviewers are not counted when no pull request is created
+func TestReviewerRotationWithoutPullRequests(t *testing.T) {
+	workingDir, err := os.Getwd()
+	require.NoError(t, err)
+	changerBinaryPath := normalizePath(filepath.Join(workingDir, changerBinaryPath))
+
+	vcMock := &vcmock.VersionController{
+		Repositories: []vcmock.Repository{
+			createRepo(t, "owner", "repo-1", "i like apples"),
+		},
+	}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir := t.TempDir()
+	rotationFile := filepath.Join(tmpDir, "rotation.json")
+
+	_, err = executeCommand(t,
+		"run",
+		"--log-file", filepath.Join(tmpDir, "log.txt"),
+		"--author-name", "Test Author",
+		"--author-email", "test@example.com",
+		"-m", "custom message",
+		"-r", "reviewer1,reviewer2",
+		"--max-reviewers", "1",
+		"--skip-pr",
+		"--reviewer-rotation-file", rotationFile,
+		changerBinaryPath,
+	)
+	require.NoError(t, err)
+
+	require.Len(t, vcMock.PullRequests, 0)
+
+	data, err := os.ReadFile(rotationFile)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "reviewer1")
+}
+
+// TestReviewerRotationUpdatedPullRequest tests that reviewers requested when updating a pull request are counted
+func TestReviewerRotationUpdatedPullRequest(t *testing.T) {
+	workingDir, err := os.Getwd()
+	require.NoError(t, err)
+	changerBinaryPath := normalizePath(filepath.Join(workingDir, changerBinaryPath))
+
+	repo := createRepo(t, "owner", "existing-pr", "i like apples")
+	changeBranch(t, repo.Path, "multi-gitter-branch", true)
+	changeTestFile(t, repo.Path, "i like apple", "test change")
+	changeBranch(t, repo.Path, "master", false)
+
+	vcMock := &vcmock.VersionController{
+		Repositories: []vcmock.Repository{repo},
+		PullRequests: []vcmock.PullRequest{
+			{
+				PRStatus:       scm.PullRequestStatusSuccess,
+				PRNumber:       42,
+				Repository:     repo,
+				NewPullRequest: scm.NewPullRequest{Head: "multi-gitter-branch"},
+			},
+		},
+	}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir := t.TempDir()
+	rotationFile := filepath.Join(tmpDir, "rotation.json")
+
+	_, err = executeCommand(t,
+		"run",
+		"--log-file", filepath.Join(tmpDir, "log.txt"),
+		"--author-name", "Test Author",
+		"--author-email", "test@example.com",
+		"-m", "custom message",
+		"--conflict-strategy", "replace",
+		"-r", "reviewer1,reviewer2",
+		"--max-reviewers", "1",
+		"--reviewer-rotation-file", rotationFile,
+		changerBinaryPath,
+	)
+	require.NoError(t, err)
+
+	require.Len(t, vcMock.PullRequests, 1)
+	assert.Equal(t, []string{"reviewer1"}, vcMock.PullRequests[0].Reviewers)
+
+	data, err := os.ReadFile(rotationFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"reviewer1": 1`)
+}
@@ -0,0 +1,226 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+)
+
+// TestStatusFormats tests the structured output formats of the status command
+func TestStatusFormats(t *testing.T) {
+	vcMock := &vcmock.VersionController{
+		PullRequests: []vcmock.PullRequest{
+			{
+				PRStatus:   scm.PullRequestStatusError,
+				PRNumber:   1,
+				Repository: vcmock.Repository{OwnerName: "owner", RepoName: "has-url"},
+				NewPullRequest: scm.NewPullRequest{
+					Title:  "Update dependencies",
+					Head:   "multi-gitter-branch",
+					Labels: []string{"dependencies", "automated"},
+				},
+				Checks: []scm.Check{
+					{Name: "build", Status: scm.CheckStatusSuccess, URL: "https://ci.example.com/build/1"},
+					{Name: "lint", Status: scm.CheckStatusFailure},
+				},
+				ReviewDecision: scm.ReviewDecisionChangesRequested,
+				Mergeability:   scm.MergeabilityMergeable,
+			},
+			{
+				PRStatus:       scm.PullRequestStatusPending,
+				PRNumber:       2,
+				Repository:     vcmock.Repository{OwnerName: "owner", RepoName: "other"},
+				NewPullRequest: scm.NewPullRequest{Title: "Update dependencies", Head: "multi-gitter-branch", Draft: true},
+			},
+		},
+	}
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir := t.TempDir()
+	status := func(t *testing.T, format string) string {
+		outFile := filepath.Join(tmpDir, format+".txt")
+
+		command := cmd.RootCmd()
+		command.SetArgs([]string{"status", "--output", outFile, "--format", format})
+		require.NoError(t, command.Execute())
+
+		data, err := os.ReadFile(outFile)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var statuses []map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(status(t, "json")), &statuses))
+		require.Len(t, statuses, 2)
+
+		assert.Equal(t, "owner/has-url #1", statuses[0]["pull_request"])
+		assert.Equal(t, "Error", statuses[0]["status"])
+		assert.Equal(t, "https://github.com/owner/has-url/pull/1", statuses[0]["url"])
+		assert.Equal(t, float64(1), statuses[0]["number"])
+		assert.Equal(t, "Update dependencies", statuses[0]["title"])
+		assert.Equal(t, "changes_requested", statuses[0]["review_decision"])
+		assert.Equal(t, "mergeable", statuses[0]["mergeability"])
+		assert.Equal(t, []interface{}{"dependencies", "automated"}, statuses[0]["labels"])
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"name": "build", "status": "success", "url": "https://ci.example.com/build/1"},
+			map[string]interface{}{"name": "lint", "status": "failure"},
+		}, statuses[0]["checks"])
+
+		assert.Equal(t, "owner/other #2", statuses[1]["pull_request"])
+		assert.Equal(t, true, statuses[1]["draft"])
+		assert.Equal(t, []interface{}{}, statuses[1]["checks"])
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		assert.Equal(t, `pull_request,status,url,number,title,author,created_at,updated_at,draft,review_decision,mergeability,labels,checks
+owner/has-url #1,Error,https://github.com/owner/has-url/pull/1,1,Update dependencies,,,,false,changes_requested,mergeable,dependencies;automated,build=success;lint=failure
+owner/other #2,Pending,,2,Update dependencies,,,,true,,,,
+`, status(t, "csv"))
+	})
+
+	t.Run("table", func(t *testing.T) {
+		assert.Equal(t, `PULL REQUEST      STATUS   TITLE                AUTHOR  CHECKS                REVIEW             MERGEABLE  LABELS                  UPDATED  URL
+owner/has-url #1  Error    Update dependencies  -       1/2 passed, 1 failed  changes_requested  mergeable  dependencies,automated  -        https://github.com/owner/has-url/pull/1
+owner/other #2    Pending  Update dependencies  -       -                     -                  -          -                       -        -
+`, status(t, "table"))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		command := cmd.RootCmd()
+		command.SetArgs([]string{"status", "--format", "xml"})
+		assert.EqualError(t, command.Execute(), `not a valid status format: "xml"`)
+	})
+}
+
+// pollingVersionController changes the status of pull requests each time they are polled
+type pollingVersionController struct {
+	*vcmock.VersionController
+	polls  int
+	onPoll func(vc *vcmock.VersionController, poll int)
+}
+
+func (vc *pollingVersionController) GetPullRequests(ctx context.Context, branchName string) ([]scm.PullRequest, error) {
+	vc.polls++
+	vc.onPoll(vc.VersionController, vc.polls)
+	return vc.VersionController.GetPullRequests(ctx, branchName)
+}
+
+// TestStatusWatch tests that the status command can poll pull requests until they have settled
+func TestStatusWatch(t *testing.T) {
+	newVersionController := func(onPoll func(vc *vcmock.VersionController, poll int)) *pollingVersionController {
+		return &pollingVersionController{
+			VersionController: &vcmock.VersionController{
+				PullRequests: []vcmock.PullRequest{
+					{
+						PRStatus:       scm.PullRequestStatusPending,
+						PRNumber:       1,
+						Repository:     vcmock.Repository{OwnerName: "owner", RepoName: "repo1"},
+						NewPullRequest: scm.NewPullRequest{Head: "multi-gitter-branch"},
+					},
+					{
+						PRStatus:       scm.PullRequestStatusNeedsReview,
+						PRNumber:       2,
+						Repository:     vcmock.Repository{OwnerName: "owner", RepoName: "repo2"},
+						NewPullRequest: scm.NewPullRequest{Head: "multi-gitter-branch"},
+					},
+				},
+			},
+			onPoll: onPoll,
+		}
+	}
+
+	tmpDir := t.TempDir()
+	watch := func(t *testing.T, name string, args ...string) (string, error) {
+		outFile := filepath.Join(tmpDir, name+".txt")
+
+		command := cmd.RootCmd()
+		command.SetArgs(append([]string{"status", "--output", outFile, "--watch", "--watch-interval", "1ms", "--plain-output"}, args...))
+		err := command.Execute()
+
+		data, readErr := os.ReadFile(outFile)
+		require.NoError(t, readErr)
+		return string(data), err
+	}
+
+	t.Run("settled", func(t *testing.T) {
+		vc := newVersionController(func(vc *vcmock.VersionController, poll int) {
+			switch poll {
+			case 2:
+				vc.SetPRStatus("repo1", "multi-gitter-branch", scm.PullRequestStatusMerged)
+			case 3:
+				vc.SetPRStatus("repo2", "multi-gitter-branch", scm.PullRequestStatusClosed)
+			}
+		})
+		cmd.OverrideVersionController = vc
+
+		out, err := watch(t, "settled")
+		require.NoError(t, err)
+		assert.Equal(t, 3, vc.polls)
+
+		assert.Equal(t, 3, strings.Count(out, "2 pull requests at"))
+		assert.Contains(t, out, "(Pending: 1, NeedsReview: 1)\n\nowner/repo1 #1: Pending\nowner/repo2 #2: NeedsReview\n")
+		assert.Contains(t, out, "(NeedsReview: 1, Merged: 1)\n\nowner/repo1 #1: Merged\nowner/repo2 #2: NeedsReview\n")
+		assert.Contains(t, out, "(Merged: 1, Closed: 1)\n\nowner/repo1 #1: Merged\nowner/repo2 #2: Closed\n")
+	})
+
+	t.Run("file output", func(t *testing.T) {
+		vc := newVersionController(func(vc *vcmock.VersionController, poll int) {
+			if poll == 2 {
+				vc.SetPRStatus("repo1", "multi-gitter-branch", scm.PullRequestStatusMerged)
+				vc.SetPRStatus("repo2", "multi-gitter-branch", scm.PullRequestStatusClosed)
+			}
+		})
+		cmd.OverrideVersionController = vc
+
+		outFile := filepath.Join(tmpDir, "file-output.txt")
+		command := cmd.RootCmd()
+		command.SetArgs([]string{"status", "--output", outFile, "--watch", "--watch-interval", "1ms"})
+		require.NoError(t, command.Execute())
+
+		data, err := os.ReadFile(outFile)
+		require.NoError(t, err)
+		assert.Equal(t, 2, strings.Count(string(data), "2 pull requests at"))
+		assert.NotContains(t, string(data), "\033[2J")
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		vc := newVersionController(func(vc *vcmock.VersionController, poll int) {
+			if poll == 2 {
+				vc.SetPRStatus("repo1", "multi-gitter-branch", scm.PullRequestStatusMerged)
+				vc.SetPRStatus("repo2", "multi-gitter-branch", scm.PullRequestStatusConflicting)
+			}
+		})
+		cmd.OverrideVersionController = vc
+
+		_, err := watch(t, "failed", "--format", "table")
+		assert.EqualError(t, err, "1 pull requests failed: owner/repo2 #2")
+		assert.Equal(t, 2, vc.polls)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		vc := newVersionController(func(_ *vcmock.VersionController, _ int) {})
+		cmd.OverrideVersionController = vc
+
+		_, err := watch(t, "timeout", "--watch-timeout", "50ms")
+		assert.EqualError(t, err, "timed out after 50ms with 2 pull requests that have not settled")
+		assert.Greater(t, vc.polls, 1)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		cmd.OverrideVersionController = newVersionController(func(_ *vcmock.VersionController, _ int) {})
+
+		_, err := watch(t, "json", "--format", "json")
+		assert.EqualError(t, err, "watching statuses is only supported with the text and table formats")
+	})
+}
@@ -350,6 +350,57 @@ func TestTable(t *testing.T) {
 			},
 		},
 
+		{
+			name: "codeowners reviewers",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "should-change", "i like apples")
+				require.NoError(t, os.MkdirAll(filepath.Join(repo.Path, ".github"), 0700))
+				addFile(t, repo.Path, ".github/CODEOWNERS", "* @default-owner\n/test.txt @file-owner @my-org/apple-team\n/other.txt @other-owner\n", "add codeowners")
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{repo},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-m", "custom message",
+				"-r", "reviewer1",
+				"--codeowners",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.ElementsMatch(t, []string{"reviewer1", "file-owner"}, vcMock.PullRequests[0].Reviewers)
+				assert.ElementsMatch(t, []string{"apple-team"}, vcMock.PullRequests[0].TeamReviewers)
+			},
+		},
+
+		{
+			name: "codeowners reviewers with max reviewers",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "should-change", "i like apples")
+				addFile(t, repo.Path, "CODEOWNERS", "*.txt @owner1 @owner2 @owner3\n", "add codeowners")
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{repo},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-m", "custom message",
+				"--codeowners",
+				"--max-reviewers", "2",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Len(t, vcMock.PullRequests[0].Reviewers, 2)
+				assert.Subset(t, []string{"owner1", "owner2", "owner3"}, vcMock.PullRequests[0].Reviewers)
+			},
+		},
+
 		{
 			name: "dry run",
 			vcCreate: func(t *testing.T) *vcmock.VersionController {
@@ -476,6 +527,211 @@ func TestTable(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name: "metadata repository filtering",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				goRepo := createRepo(t, "owner", "go-repo", "i like apples")
+				goRepo.Meta = scm.RepositoryMetadata{Language: "Go", Visibility: scm.VisibilityPrivate, PushedAt: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)}
+				oldGoRepo := createRepo(t, "owner", "old-go-repo", "i like apples")
+				oldGoRepo.Meta = scm.RepositoryMetadata{Language: "Go", Visibility: scm.VisibilityPrivate, PushedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+				archivedGoRepo := createRepo(t, "owner", "archived-go-repo", "i like apples")
+				archivedGoRepo.Meta = scm.RepositoryMetadata{Language: "Go", Visibility: scm.VisibilityPrivate, PushedAt: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Archived: true}
+				publicGoRepo := createRepo(t, "owner", "public-go-repo", "i like apples")
+				publicGoRepo.Meta = scm.RepositoryMetadata{Language: "Go", Visibility: scm.VisibilityPublic, PushedAt: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)}
+				javaRepo := createRepo(t, "owner", "java-repo", "i like apples")
+				javaRepo.Meta = scm.RepositoryMetadata{Language: "Java", Visibility: scm.VisibilityPrivate, PushedAt: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)}
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						goRepo,
+						oldGoRepo,
+						archivedGoRepo,
+						publicGoRepo,
+						javaRepo,
+						createRepo(t, "owner", "unknown-repo", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--language", "go",
+				"--visibility", "private",
+				"--pushed-after", "2025-01-01",
+				"--exclude-archived",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, "go-repo", vcMock.PullRequests[0].RepoName)
+				assert.Contains(t, runData.logOut, "Skipping owner/old-go-repo since it was last pushed to 2024-03-01T00:00:00Z")
+				assert.Contains(t, runData.logOut, "Skipping owner/archived-go-repo since it is archived")
+				assert.Contains(t, runData.logOut, "Skipping owner/public-go-repo since its visibility is public")
+				assert.Contains(t, runData.logOut, "Skipping owner/java-repo since its language is Java")
+				assert.Contains(t, runData.logOut, "Skipping owner/unknown-repo since its language is unknown")
+			},
+		},
+		{
+			name: "filter expression",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				goRepo := createRepo(t, "owner", "go-repo", "i like apples")
+				goRepo.Meta = scm.RepositoryMetadata{Language: "Go", Topics: []string{"backend"}}
+				archivedGoRepo := createRepo(t, "owner", "archived-go-repo", "i like apples")
+				archivedGoRepo.Meta = scm.RepositoryMetadata{Language: "Go", Topics: []string{"backend"}, Archived: true}
+				teamGoRepo := createRepo(t, "team-x", "go-repo", "i like apples")
+				teamGoRepo.Meta = scm.RepositoryMetadata{Language: "Go", Topics: []string{"backend"}}
+				frontendRepo := createRepo(t, "owner", "frontend-repo", "i like apples")
+				frontendRepo.Meta = scm.RepositoryMetadata{Language: "Go", Topics: []string{"frontend"}}
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						goRepo,
+						archivedGoRepo,
+						teamGoRepo,
+						frontendRepo,
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--filter", "language == go && topics contains backend && !archived && owner != team-x",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, "owner", vcMock.PullRequests[0].OwnerName)
+				assert.Equal(t, "go-repo", vcMock.PullRequests[0].RepoName)
+				assert.Contains(t, runData.logOut, "Skipping team-x/go-repo since it does not match the filter")
+			},
+		},
+		{
+			name: "invalid filter expression",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "repo1", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--filter", "language == go &&",
+				"--commit-message", "chore: foo",
+				"--dry-run",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				assert.Contains(t, runData.cmdOut, "could not parse filter: unexpected end of the expression")
+			},
+			expectErr: true,
+		},
+		{
+			name: "has-file filtering",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				workflowRepo := createRepo(t, "owner", "workflow-repo", "i like apples")
+				require.NoError(t, os.MkdirAll(filepath.Join(workflowRepo.Path, ".github", "workflows"), 0700))
+				addFile(t, workflowRepo.Path, ".github/workflows/ci.yml", "on: push", "add workflow")
+
+				terraformRepo := createRepo(t, "owner", "terraform-repo", "i like apples")
+				require.NoError(t, os.MkdirAll(filepath.Join(terraformRepo.Path, "infra", "prod"), 0700))
+				addFile(t, terraformRepo.Path, "infra/prod/main.tf", "", "add terraform")
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						workflowRepo,
+						terraformRepo,
+						createRepo(t, "owner", "other-repo", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--has-file", ".github/workflows/ci.yml",
+				"--has-file", "**/*.tf",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 2)
+				assert.Equal(t, "workflow-repo", vcMock.PullRequests[0].RepoName)
+				assert.Equal(t, "terraform-repo", vcMock.PullRequests[1].RepoName)
+				assert.Contains(t, runData.logOut, "Skipping owner/other-repo since it does not contain any matching file")
+			},
+		},
+		{
+			name: "has-file directory filtering",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				workflowRepo := createRepo(t, "owner", "workflow-repo", "i like apples")
+				require.NoError(t, os.MkdirAll(filepath.Join(workflowRepo.Path, ".github", "workflows"), 0700))
+				addFile(t, workflowRepo.Path, ".github/workflows/ci.yml", "on: push", "add workflow")
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						workflowRepo,
+						createRepo(t, "owner", "other-repo", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"print",
+				"--has-file", ".github/workflows",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				assert.Contains(t, runData.logOut, "Running on 1 repositories")
+				assert.Contains(t, runData.logOut, "Skipping owner/other-repo since it does not contain any matching file")
+			},
+		},
+		{
+			name: "invalid visibility",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "repo1", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--visibility", "secret",
+				"--commit-message", "chore: foo",
+				"--dry-run",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				assert.Contains(t, runData.cmdOut, `not a valid visibility: "secret"`)
+			},
+			expectErr: true,
+		},
+		{
+			name: "invalid pushed-after",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "repo1", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--pushed-after", "yesterday",
+				"--commit-message", "chore: foo",
+				"--dry-run",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				assert.Contains(t, runData.cmdOut, "could not parse pushed-after")
+			},
+			expectErr: true,
+		},
 
 		{
 			name:      "parallel",
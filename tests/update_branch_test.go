@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+)
+
+// mergeUpdatingVersionController can update pull requests by merging through its "API", but not by rebasing
+type mergeUpdatingVersionController struct {
+	*vcmock.VersionController
+	updated []string
+}
+
+func (vc *mergeUpdatingVersionController) UpdatePullRequestBranch(_ context.Context, pr scm.PullRequest, rebase bool) error {
+	if rebase {
+		return scm.ErrUpdateMethodNotSupported
+	}
+	vc.updated = append(vc.updated, pr.String())
+	return nil
+}
+
+// TestUpdateBranch tests updating pull requests through the platform, and locally with conflicts reported
+func TestUpdateBranch(t *testing.T) {
+	behindRepo := createRepo(t, "owner", "behind", "i like apples")
+	conflictingRepo := createRepo(t, "owner", "conflicting", "i like apples")
+
+	vc := &mergeUpdatingVersionController{
+		VersionController: &vcmock.VersionController{},
+	}
+	vc.AddRepository(behindRepo, conflictingRepo)
+	defer vc.Clean()
+	cmd.OverrideVersionController = vc
+
+	for i, repo := range []vcmock.Repository{behindRepo, conflictingRepo} {
+		changeBranch(t, repo.Path, "multi-gitter-branch", true)
+		changeTestFile(t, repo.Path, "i like bananas", "Change to bananas")
+		changeBranch(t, repo.Path, "master", false)
+
+		vc.PullRequests = append(vc.PullRequests, vcmock.PullRequest{
+			PRStatus:       scm.PullRequestStatusPending,
+			PRNumber:       i + 1,
+			Repository:     repo,
+			NewPullRequest: scm.NewPullRequest{Head: "multi-gitter-branch"},
+		})
+	}
+	addFile(t, behindRepo.Path, "new-file.txt", "new", "Add new file")
+	changeTestFile(t, conflictingRepo.Path, "i like oranges", "Change to oranges")
+
+	updateBranch := []string{"update-branch", "--author-name", "test", "--author-email", "test@example.com"}
+
+	out, err := executeCommand(t, append(updateBranch, "--dry-run")...)
+	require.NoError(t, err)
+	assert.Equal(t, `Pull requests that would be updated:
+  owner/behind #1
+  owner/conflicting #2
+`, out)
+	assert.Empty(t, vc.updated)
+
+	out, err = executeCommand(t, updateBranch...)
+	require.NoError(t, err)
+	assert.Equal(t, `Updated pull requests:
+  owner/behind #1
+  owner/conflicting #2
+`, out)
+	assert.Equal(t, []string{"owner/behind #1", "owner/conflicting #2"}, vc.updated)
+
+	// Rebasing is not supported by the platform, so the branches are updated locally
+	out, err = executeCommand(t, append(updateBranch, "--rebase")...)
+	assert.EqualError(t, err, "1 of 2 pull requests could not be updated")
+	assert.Equal(t, `Updated pull requests:
+  owner/behind #1
+Pull requests with conflicts:
+  owner/conflicting #2
+`, out)
+
+	changeBranch(t, behindRepo.Path, "multi-gitter-branch", false)
+	assert.True(t, fileExist(t, behindRepo.Path, "new-file.txt"))
+	assert.Equal(t, "i like bananas", readTestFile(t, behindRepo.Path))
+	msg, err := getCommitMessage(t, behindRepo.Path, "refs/heads/multi-gitter-branch")
+	require.NoError(t, err)
+	assert.Equal(t, "Change to bananas", msg)
+
+	changeBranch(t, conflictingRepo.Path, "multi-gitter-branch", false)
+	assert.Equal(t, "i like bananas", readTestFile(t, conflictingRepo.Path))
+}
@@ -26,6 +26,10 @@ func (gm *GerritVersionController) FeatureBranchExist(ctx context.Context, repo
 	return pr != nil, err
 }
 
+func (gm *GerritVersionController) EditsPullRequests() bool {
+	return false
+}
+
 // Below we are just calling the mock VersionController
 
 func (gm *GerritVersionController) ForkRepository(ctx context.Context, repo scm.Repository, newOwner string) (scm.Repository, error) {
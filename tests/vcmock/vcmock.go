@@ -8,8 +8,11 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	internalgit "github.com/lindell/multi-gitter/internal/git"
 	"github.com/lindell/multi-gitter/internal/scm"
 	"github.com/pkg/errors"
@@ -21,6 +24,8 @@ type VersionController struct {
 	Repositories []Repository
 	PullRequests []PullRequest
 	Changes      map[string][]internalgit.Changes
+	// CurrentUser is the user the mock is authenticated as, pull requests authored by it can't be approved
+	CurrentUser string
 
 	prLock sync.RWMutex
 }
@@ -64,10 +69,23 @@ func (vc *VersionController) UpdatePullRequest(_ context.Context, _ scm.Reposito
 		if vc.PullRequests[i].PRNumber == pullRequest.PRNumber && vc.PullRequests[i].Repository.FullName() == pullRequest.Repository.FullName() {
 			vc.PullRequests[i].Title = updatedPR.Title
 			vc.PullRequests[i].Body = updatedPR.Body
-			vc.PullRequests[i].Reviewers = updatedPR.Reviewers
-			vc.PullRequests[i].TeamReviewers = updatedPR.TeamReviewers
-			vc.PullRequests[i].Assignees = updatedPR.Assignees
-			vc.PullRequests[i].Labels = updatedPR.Labels
+			if updatedPR.SetDraft != nil {
+				vc.PullRequests[i].Draft = *updatedPR.SetDraft
+			}
+			vc.PullRequests[i].AutoMerge = vc.PullRequests[i].AutoMerge || updatedPR.AutoMerge
+			// Like the real platforms, slices that are not set are left unchanged
+			if updatedPR.Reviewers != nil {
+				vc.PullRequests[i].Reviewers = updatedPR.Reviewers
+			}
+			if updatedPR.TeamReviewers != nil {
+				vc.PullRequests[i].TeamReviewers = updatedPR.TeamReviewers
+			}
+			if updatedPR.Assignees != nil {
+				vc.PullRequests[i].Assignees = updatedPR.Assignees
+			}
+			if updatedPR.Labels != nil {
+				vc.PullRequests[i].Labels = updatedPR.Labels
+			}
 			return vc.PullRequests[i], nil
 		}
 	}
@@ -104,7 +122,13 @@ func (vc *VersionController) GetOpenPullRequest(_ context.Context, repo scm.Repo
 }
 
 func openPullRequest(pr PullRequest) bool {
-	return pr.PRStatus == scm.PullRequestStatusSuccess || pr.PRStatus == scm.PullRequestStatusPending
+	switch pr.PRStatus {
+	case scm.PullRequestStatusSuccess, scm.PullRequestStatusPending,
+		scm.PullRequestStatusNeedsReview, scm.PullRequestStatusChangesRequested,
+		scm.PullRequestStatusConflicting, scm.PullRequestStatusDraft:
+		return true
+	}
+	return false
 }
 
 // MergePullRequest sets the status of a mock pull requests to merged
@@ -137,6 +161,107 @@ func (vc *VersionController) ClosePullRequest(_ context.Context, pr scm.PullRequ
 	return errors.New("could not find pull request")
 }
 
+// CommentPullRequest adds a comment to a mock pull request
+func (vc *VersionController) CommentPullRequest(_ context.Context, pr scm.PullRequest, comment string) error {
+	vc.prLock.Lock()
+	defer vc.prLock.Unlock()
+
+	pullRequest := pr.(PullRequest)
+	for i := range vc.PullRequests {
+		if vc.PullRequests[i].Repository.FullName() == pullRequest.Repository.FullName() {
+			vc.PullRequests[i].Comments = append(vc.PullRequests[i].Comments, comment)
+			return nil
+		}
+	}
+	return errors.New("could not find pull request")
+}
+
+// PullRequestComments returns the comments of a mock pull request
+func (vc *VersionController) PullRequestComments(_ context.Context, pr scm.PullRequest) ([]string, error) {
+	vc.prLock.RLock()
+	defer vc.prLock.RUnlock()
+
+	pullRequest := pr.(PullRequest)
+	for i := range vc.PullRequests {
+		if vc.PullRequests[i].Repository.FullName() == pullRequest.Repository.FullName() {
+			return vc.PullRequests[i].Comments, nil
+		}
+	}
+	return nil, errors.New("could not find pull request")
+}
+
+// DeletePullRequestBranch marks the branch of a mock pull request as deleted
+func (vc *VersionController) DeletePullRequestBranch(_ context.Context, pr scm.PullRequest) error {
+	vc.prLock.Lock()
+	defer vc.prLock.Unlock()
+
+	pullRequest := pr.(PullRequest)
+	for i := range vc.PullRequests {
+		if vc.PullRequests[i].Repository.FullName() == pullRequest.Repository.FullName() {
+			vc.PullRequests[i].BranchDeleted = true
+			return nil
+		}
+	}
+	return errors.New("could not find pull request")
+}
+
+// ReportsApprovals tells that the mock pull requests contain the number of approvals
+func (vc *VersionController) ReportsApprovals() bool {
+	return true
+}
+
+// ApprovePullRequest adds an approval to a mock pull request
+func (vc *VersionController) ApprovePullRequest(_ context.Context, pr scm.PullRequest) error {
+	vc.prLock.Lock()
+	defer vc.prLock.Unlock()
+
+	pullRequest := pr.(PullRequest)
+	if vc.CurrentUser != "" && pullRequest.Author == vc.CurrentUser {
+		return scm.ErrOwnPullRequest
+	}
+	for i := range vc.PullRequests {
+		if vc.PullRequests[i].Repository.FullName() == pullRequest.Repository.FullName() {
+			vc.PullRequests[i].Approvals++
+			vc.PullRequests[i].ReviewDecision = scm.ReviewDecisionApproved
+			return nil
+		}
+	}
+	return errors.New("could not find pull request")
+}
+
+// RetryFailedChecks sets the failed checks of a mock pull request as pending, unless its checks have been run maxAttempts times
+func (vc *VersionController) RetryFailedChecks(_ context.Context, pr scm.PullRequest, maxAttempts int) error {
+	vc.prLock.Lock()
+	defer vc.prLock.Unlock()
+
+	pullRequest := pr.(PullRequest)
+	for i := range vc.PullRequests {
+		if vc.PullRequests[i].Repository.FullName() == pullRequest.Repository.FullName() {
+			checks := append([]scm.Check{}, vc.PullRequests[i].Checks...)
+			failedChecks := false
+			for j := range checks {
+				if checks[j].Status == scm.CheckStatusFailure {
+					checks[j].Status = scm.CheckStatusPending
+					failedChecks = true
+				}
+			}
+			if !failedChecks && vc.PullRequests[i].PRStatus != scm.PullRequestStatusError {
+				return scm.ErrNoFailedChecks
+			}
+			if vc.PullRequests[i].ChecksRetried+1 >= maxAttempts {
+				return scm.ErrMaxAttemptsReached
+			}
+			vc.PullRequests[i].ChecksRetried++
+			vc.PullRequests[i].Checks = checks
+			if vc.PullRequests[i].PRStatus == scm.PullRequestStatusError {
+				vc.PullRequests[i].PRStatus = scm.PullRequestStatusPending
+			}
+			return nil
+		}
+	}
+	return errors.New("could not find pull request")
+}
+
 // AddRepository adds a repository to the mock
 func (vc *VersionController) AddRepository(repo ...Repository) {
 	vc.Repositories = append(vc.Repositories, repo...)
@@ -210,6 +335,35 @@ func (vc *VersionController) ForkRepository(ctx context.Context, repo scm.Reposi
 	}, nil
 }
 
+// RepositoryFiles returns the paths of all files in the repository on the branch
+func (vc *VersionController) RepositoryFiles(_ context.Context, repo scm.Repository, branch string) ([]string, error) {
+	r := repo.(Repository)
+
+	gitRepo, err := git.PlainOpen(r.Path)
+	if err != nil {
+		return nil, err
+	}
+	ref, err := gitRepo.Reference(plumbing.NewBranchReferenceName(branch), true)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := gitRepo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, err
+	}
+	fileIter, err := commit.Files()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	err = fileIter.ForEach(func(file *object.File) error {
+		files = append(files, file.Name)
+		return nil
+	})
+	return files, err
+}
+
 // Clean cleans up the data on disk that exist within the version controller mock
 func (vc *VersionController) Clean() {
 	for _, repo := range vc.Repositories {
@@ -222,6 +376,20 @@ type PullRequest struct {
 	PRStatus scm.PullRequestStatus
 	PRNumber int
 	Merged   bool
+	Author   string
+
+	Checks         []scm.Check
+	ReviewDecision scm.ReviewDecision
+	Approvals      int
+	Mergeability   scm.Mergeability
+	CreatedAt      time.Time
+	// HeadRef is a reference to the head of the pull request, like the ones platforms keep for pull requests made from forks
+	HeadRef string
+
+	Comments      []string
+	BranchDeleted bool
+	// ChecksRetried is the number of times the checks have been re-run
+	ChecksRetried int
 
 	Repository
 	scm.NewPullRequest
@@ -237,6 +405,30 @@ func (pr PullRequest) String() string {
 	return fmt.Sprintf("%s #%d", pr.Repository.FullName(), pr.PRNumber)
 }
 
+// Details returns the details of the pr
+func (pr PullRequest) Details() scm.PullRequestDetails {
+	return scm.PullRequestDetails{
+		Number:         pr.PRNumber,
+		Title:          pr.Title,
+		Body:           pr.Body,
+		Author:         pr.Author,
+		CreatedAt:      pr.CreatedAt,
+		Labels:         pr.Labels,
+		Draft:          pr.Draft,
+		Checks:         pr.Checks,
+		ReviewDecision: pr.ReviewDecision,
+		Approvals:      pr.Approvals,
+		Mergeability:   pr.Mergeability,
+		BaseBranch:     pr.Base,
+		HeadRef:        pr.HeadRef,
+	}
+}
+
+// RepositoryName returns the full name of the repository the pr targets
+func (pr PullRequest) RepositoryName() string {
+	return pr.Repository.FullName()
+}
+
 func (pr PullRequest) URL() string {
 	if pr.Repository.RepoName == "has-url" {
 		return "https://github.com/owner/has-url/pull/1"
@@ -250,6 +442,7 @@ type Repository struct {
 	OwnerName string
 	RepoName  string
 	Path      string
+	Meta      scm.RepositoryMetadata
 }
 
 // CloneURL return the URL (filepath) of the repository on disk
@@ -267,6 +460,11 @@ func (r Repository) FullName() string {
 	return fmt.Sprintf("%s/%s", r.OwnerName, r.RepoName)
 }
 
+// Metadata returns the metadata set on the mock repo
+func (r Repository) Metadata() scm.RepositoryMetadata {
+	return r.Meta
+}
+
 // Owner returns the owner of a repo
 func (r Repository) Owner() string {
 	return r.OwnerName
@@ -75,6 +75,38 @@ func main() {
 			imgIcon: "docs/img/fa/times-hexagon.svg",
 			cmd:     commandByName(subCommands, "close"),
 		},
+		{
+			imgIcon: "docs/img/fa/cloud.svg",
+			cmd:     commandByName(subCommands, "serve"),
+		},
+		{
+			imgIcon: "docs/img/fa/file-alt.svg",
+			cmd:     commandByName(subCommands, "apply"),
+		},
+		{
+			imgIcon: "docs/img/fa/comment.svg",
+			cmd:     commandByName(subCommands, "comment"),
+		},
+		{
+			imgIcon: "docs/img/fa/sync.svg",
+			cmd:     commandByName(subCommands, "update-branch"),
+		},
+		{
+			imgIcon: "docs/img/fa/pencil.svg",
+			cmd:     commandByName(subCommands, "edit"),
+		},
+		{
+			imgIcon: "docs/img/fa/thumbs-up.svg",
+			cmd:     commandByName(subCommands, "approve"),
+		},
+		{
+			imgIcon: "docs/img/fa/redo.svg",
+			cmd:     commandByName(subCommands, "retry-checks"),
+		},
+		{
+			imgIcon: "docs/img/fa/exchange.svg",
+			cmd:     commandByName(subCommands, "diff"),
+		},
 		{
 			imgIcon: "docs/img/fa/print.svg",
 			cmd:     commandByName(subCommands, "print"),